@@ -0,0 +1,208 @@
+// Package hooks runs hooks before and after a backup, e.g. to make a dump
+// application-consistent by flushing tables or pausing a writer.
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/oiler-backup/core/common/report"
+)
+
+// Phases of hooks.
+const (
+	PhasePre  = "pre"
+	PhasePost = "post"
+)
+
+const (
+	// OnErrorContinue lets the backup go on if the hook fails.
+	OnErrorContinue = "Continue"
+	// defaultTimeout limits hooks which set no timeout.
+	defaultTimeout = time.Minute
+	// maxOutput is how many last bytes of output are reported, since the report
+	// has to fit a termination message of the container.
+	maxOutput = 256
+	// maxMessage is how many bytes of an error are reported.
+	maxMessage = 256
+)
+
+// A Hook is a query, a command or an HTTP request. Exactly one of them is set.
+type Hook struct {
+	Name string `json:"name"`
+	// Query runs in the session shared by all hooks of a backup.
+	Query string `json:"query,omitempty"`
+	// Command runs in the container of backuper without a shell.
+	Command []string `json:"command,omitempty"`
+	HTTP    *HTTP    `json:"http,omitempty"`
+
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+	// OnError is Fail or Continue, Fail by default.
+	OnError string `json:"onError,omitempty"`
+}
+
+// An HTTP request of Hook. Status codes other than 2xx fail the hook.
+type HTTP struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// Hooks run before and after a backup.
+type Hooks struct {
+	Pre  []Hook `json:"pre,omitempty"`
+	Post []Hook `json:"post,omitempty"`
+}
+
+// A Result is an outcome of Hook.
+type Result struct {
+	Name      string `json:"name"`
+	Phase     string `json:"phase"`
+	Succeeded bool   `json:"succeeded"`
+	Output    string `json:"output,omitempty"`
+	Duration  string `json:"duration"`
+	Message   string `json:"message,omitempty"`
+}
+
+// A Querier runs query against the database and returns its output.
+type Querier func(ctx context.Context, query string) (string, error)
+
+// Parse parses hooks from JSON. Empty string means no hooks.
+func Parse(s string) (Hooks, error) {
+	var hooks Hooks
+	if s == "" {
+		return hooks, nil
+	}
+	if err := json.Unmarshal([]byte(s), &hooks); err != nil {
+		return Hooks{}, fmt.Errorf("failed to parse hooks: %w", err)
+	}
+	return hooks, nil
+}
+
+// NeedsQuerier tells whether any hook runs a query.
+func (h Hooks) NeedsQuerier() bool {
+	for _, hook := range append(h.Pre, h.Post...) {
+		if hook.Query != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// A Runner runs hooks.
+type Runner struct {
+	// Query runs queries of hooks. Query hooks fail if it is nil.
+	Query  Querier
+	Client *http.Client
+}
+
+// Run runs hooks of phase one by one. Pre hooks stop at the first failed hook
+// which does not continue on error, post hooks always run all. The error is
+// of the first failed hook which does not continue on error.
+func (r Runner) Run(ctx context.Context, phase string, hooks []Hook) ([]Result, error) {
+	var results []Result
+	var failed error
+	for _, hook := range hooks {
+		result := r.run(ctx, phase, hook)
+		results = append(results, result)
+		if result.Succeeded || hook.OnError == OnErrorContinue || failed != nil {
+			continue
+		}
+		failed = fmt.Errorf("%s hook %s failed: %s", phase, hook.Name, result.Message)
+		if phase == PhasePre {
+			break
+		}
+	}
+	return results, failed
+}
+
+func (r Runner) run(ctx context.Context, phase string, hook Hook) Result {
+	timeout := defaultTimeout
+	if hook.TimeoutSeconds > 0 {
+		timeout = time.Duration(hook.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var output string
+	var err error
+	switch {
+	case hook.Query != "":
+		if r.Query == nil {
+			err = errors.New("query hooks are not supported by this database")
+			break
+		}
+		output, err = r.Query(ctx, hook.Query)
+	case len(hook.Command) > 0:
+		var out []byte
+		out, err = exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...).CombinedOutput()
+		output = string(out)
+	case hook.HTTP != nil:
+		output, err = r.request(ctx, hook.HTTP)
+	default:
+		err = errors.New("hook sets neither query, nor command, nor http")
+	}
+
+	result := Result{
+		Name:      hook.Name,
+		Phase:     phase,
+		Succeeded: err == nil,
+		Output:    tail(output),
+		Duration:  time.Since(start).Round(time.Millisecond).String(),
+	}
+	if err != nil {
+		result.Message = report.Truncate(err.Error(), maxMessage)
+	}
+	return result
+}
+
+func (r Runner) request(ctx context.Context, spec *HTTP) (string, error) {
+	method := spec.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, spec.URL, strings.NewReader(spec.Body))
+	if err != nil {
+		return "", err
+	}
+	for name, value := range spec.Headers {
+		req.Header.Set(name, value)
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*maxOutput))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return string(body), fmt.Errorf("%s %s returned %s", method, spec.URL, resp.Status)
+	}
+	return string(body), nil
+}
+
+// tail returns the last maxOutput bytes of output.
+func tail(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) <= maxOutput {
+		return output
+	}
+	return "..." + output[len(output)-maxOutput:]
+}
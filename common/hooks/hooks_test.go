@@ -0,0 +1,131 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Parse(t *testing.T) {
+	hooks, err := Parse("")
+	require.NoError(t, err)
+	assert.Empty(t, hooks.Pre)
+
+	hooks, err = Parse(`{"pre":[{"name":"checkpoint","query":"CHECKPOINT"}],"post":[{"name":"resume","command":["true"]}]}`)
+	require.NoError(t, err)
+	assert.Equal(t, []Hook{{Name: "checkpoint", Query: "CHECKPOINT"}}, hooks.Pre)
+	assert.Equal(t, []Hook{{Name: "resume", Command: []string{"true"}}}, hooks.Post)
+	assert.True(t, hooks.NeedsQuerier())
+
+	_, err = Parse("[")
+	assert.ErrorContains(t, err, "failed to parse hooks")
+}
+
+func Test_Run_Query(t *testing.T) {
+	var queries []string
+	runner := Runner{Query: func(ctx context.Context, query string) (string, error) {
+		queries = append(queries, query)
+		if query == "broken" {
+			return "", errors.New("syntax error")
+		}
+		return "1 rows affected", nil
+	}}
+
+	results, err := runner.Run(context.Background(), PhasePre, []Hook{
+		{Name: "checkpoint", Query: "CHECKPOINT"},
+		{Name: "broken", Query: "broken"},
+		{Name: "skipped", Query: "SELECT 1"},
+	})
+	assert.EqualError(t, err, "pre hook broken failed: syntax error")
+	assert.Equal(t, []string{"CHECKPOINT", "broken"}, queries, "pre hooks stop at failure")
+	require.Len(t, results, 2)
+	assert.True(t, results[0].Succeeded)
+	assert.Equal(t, "1 rows affected", results[0].Output)
+	assert.Equal(t, PhasePre, results[0].Phase)
+	assert.False(t, results[1].Succeeded)
+	assert.Equal(t, "syntax error", results[1].Message)
+}
+
+func Test_Run_PostRunsAll(t *testing.T) {
+	results, err := Runner{}.Run(context.Background(), PhasePost, []Hook{
+		{Name: "unsupported", Query: "UNLOCK TABLES"},
+		{Name: "failing", Command: []string{"false"}},
+		{Name: "resume", Command: []string{"echo", "resumed"}},
+	})
+	assert.EqualError(t, err, "post hook unsupported failed: query hooks are not supported by this database")
+	require.Len(t, results, 3)
+	assert.False(t, results[1].Succeeded)
+	assert.True(t, results[2].Succeeded)
+	assert.Equal(t, "resumed", results[2].Output)
+}
+
+func Test_Run_Continue(t *testing.T) {
+	results, err := Runner{}.Run(context.Background(), PhasePre, []Hook{
+		{Name: "optional", Command: []string{"false"}, OnError: OnErrorContinue},
+		{Name: "next", Command: []string{"true"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.False(t, results[0].Succeeded)
+	assert.True(t, results[1].Succeeded)
+}
+
+func Test_Run_Timeout(t *testing.T) {
+	results, err := Runner{}.Run(context.Background(), PhasePre, []Hook{
+		{Name: "slow", Command: []string{"sleep", "5"}, TimeoutSeconds: 1},
+	})
+	require.Error(t, err)
+	assert.False(t, results[0].Succeeded)
+}
+
+func Test_Run_TruncatesMessage(t *testing.T) {
+	results, err := Runner{}.Run(context.Background(), PhasePre, []Hook{
+		{Name: "missing", Command: []string{strings.Repeat("x", 1000)}},
+	})
+	require.Error(t, err)
+	assert.Len(t, results[0].Message, maxMessage)
+}
+
+func Test_Run_HTTP(t *testing.T) {
+	var method, body, token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		method, body, token = r.Method, string(data), r.Header.Get("Authorization")
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("paused"))
+	}))
+	defer server.Close()
+
+	results, err := Runner{}.Run(context.Background(), PhasePre, []Hook{{
+		Name: "pause",
+		HTTP: &HTTP{URL: server.URL + "/pause", Headers: map[string]string{"Authorization": "Bearer t"}, Body: `{"pause":true}`},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "paused", results[0].Output)
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, `{"pause":true}`, body)
+	assert.Equal(t, "Bearer t", token)
+
+	_, err = Runner{}.Run(context.Background(), PhasePost, []Hook{{
+		Name: "missing",
+		HTTP: &HTTP{URL: server.URL + "/missing", Method: http.MethodPut},
+	}})
+	assert.ErrorContains(t, err, "returned 404 Not Found")
+}
+
+func Test_Tail(t *testing.T) {
+	assert.Equal(t, "done", tail("  done\n"))
+	long := tail(strings.Repeat("a", maxOutput) + "end")
+	assert.Len(t, long, maxOutput+len("..."))
+	assert.True(t, strings.HasSuffix(long, "aend"))
+}
@@ -0,0 +1,27 @@
+package hooks
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// An Execer runs queries in a session of the database, e.g. *sql.Conn.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// SessionQuerier runs queries in session, so locks taken by pre hooks are held
+// until post hooks release them. Output is the number of affected rows, if known.
+func SessionQuerier(session Execer) Querier {
+	return func(ctx context.Context, query string) (string, error) {
+		result, err := session.ExecContext(ctx, query)
+		if err != nil {
+			return "", err
+		}
+		if rows, err := result.RowsAffected(); err == nil && rows > 0 {
+			return fmt.Sprintf("%d rows affected", rows), nil
+		}
+		return "", nil
+	}
+}
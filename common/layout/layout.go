@@ -0,0 +1,105 @@
+// Package layout tells where backups are stored in a bucket.
+//
+// Backups made by a BackupRequest are stored under "<namespace>/<name>/<database>/",
+// so BackupRequests of databases with the same name never share backups.
+// Backups are named "<time>-backup.<ext>", where time is formatted as
+// 2006-01-02-15-04-05 in UTC. Objects named otherwise are not backups.
+//
+// Backups made before were stored under "<database>/". They are still found
+// by a Scope, but are never written, rotated or purged.
+package layout
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+const timeLayout = "2006-01-02-15-04-05"
+
+var backupName = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}-\d{2}-\d{2}-\d{2})-backup\.[^/]+$`)
+
+// Prefix returns the prefix of backups of database made by BackupRequest name in namespace.
+func Prefix(namespace, name, database string) string {
+	return namespace + "/" + name + "/" + database + "/"
+}
+
+// LegacyPrefix returns the prefix backups of database were stored under before Prefix.
+func LegacyPrefix(database string) string {
+	return database + "/"
+}
+
+// Key returns the key of a backup made at t under prefix. ext is the extension of the dump, e.g. "sql".
+func Key(prefix string, t time.Time, ext string) string {
+	return prefix + t.UTC().Format(timeLayout) + "-backup." + ext
+}
+
+// Time returns time of a backup from its key. It returns false if key is not a backup
+// directly under prefix.
+func Time(prefix, key string) (time.Time, bool) {
+	name, ok := strings.CutPrefix(key, prefix)
+	if !ok {
+		return time.Time{}, false
+	}
+	match := backupName.FindStringSubmatch(name)
+	if match == nil {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(timeLayout, match[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// A Scope selects backups of Database to restore. Backups of any BackupRequest
+// in Namespace are selected unless Name narrows them to one BackupRequest.
+// Backups under LegacyPrefix are always selected. Without Namespace only they are.
+type Scope struct {
+	Namespace string
+	Name      string
+	Database  string
+}
+
+// Prefixes returns prefixes to list to find all backups of s.
+func (s Scope) Prefixes() []string {
+	switch {
+	case s.Namespace == "":
+		return []string{LegacyPrefix(s.Database)}
+	case s.Name != "":
+		return []string{Prefix(s.Namespace, s.Name, s.Database), LegacyPrefix(s.Database)}
+	default:
+		return []string{s.Namespace + "/", LegacyPrefix(s.Database)}
+	}
+}
+
+// Time returns time of a backup from its key. It returns false if key is not a backup of s.
+func (s Scope) Time(key string) (time.Time, bool) {
+	if t, ok := Time(LegacyPrefix(s.Database), key); ok {
+		return t, true
+	}
+	if s.Namespace == "" {
+		return time.Time{}, false
+	}
+	rest, ok := strings.CutPrefix(key, s.Namespace+"/")
+	if !ok {
+		return time.Time{}, false
+	}
+	name, _, _ := strings.Cut(rest, "/")
+	if name == "" || s.Name != "" && name != s.Name {
+		return time.Time{}, false
+	}
+	return Time(Prefix(s.Namespace, name, s.Database), key)
+}
+
+// Contains tells whether key is under one of Prefixes of s. Keys under LegacyPrefix
+// must be directly under it, since a namespace may be named like a database.
+func (s Scope) Contains(key string) bool {
+	if name, ok := strings.CutPrefix(key, LegacyPrefix(s.Database)); ok && name != "" && !strings.Contains(name, "/") {
+		return true
+	}
+	if s.Namespace == "" {
+		return false
+	}
+	return strings.HasPrefix(key, s.Prefixes()[0])
+}
@@ -0,0 +1,90 @@
+package layout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Key(t *testing.T) {
+	at := time.Date(2025, 5, 10, 15, 4, 5, 0, time.FixedZone("MSK", 3*60*60))
+
+	key := Key(Prefix("team-a", "orders-daily", "orders"), at, "sql")
+
+	assert.Equal(t, "team-a/orders-daily/orders/2025-05-10-12-04-05-backup.sql", key)
+	parsed, ok := Time(Prefix("team-a", "orders-daily", "orders"), key)
+	assert.True(t, ok)
+	assert.True(t, at.Equal(parsed))
+}
+
+func Test_Time(t *testing.T) {
+	tests := []struct {
+		prefix string
+		key    string
+		ok     bool
+	}{
+		{"orders/", "orders/2025-05-10-12-04-05-backup.sql", true},
+		{"orders/", "orders/2025-05-10-12-04-05-backup.tar.gz", true},
+		{"orders/", "orders/notes.txt", false},
+		{"orders/", "orders/nested/2025-05-10-12-04-05-backup.sql", false},
+		{"orders/", "orders-old/2025-05-10-12-04-05-backup.sql", false},
+		{"orders/", "orders/2025-13-10-12-04-05-backup.sql", false},
+	}
+	for _, tt := range tests {
+		_, ok := Time(tt.prefix, tt.key)
+		assert.Equal(t, tt.ok, ok, tt.key)
+	}
+}
+
+func Test_Scope(t *testing.T) {
+	keys := []string{
+		"orders/2025-05-01-00-00-00-backup.sql",
+		"team-a/daily/orders/2025-05-02-00-00-00-backup.sql",
+		"team-a/hourly/orders/2025-05-03-00-00-00-backup.sql",
+		"team-a/daily/users/2025-05-04-00-00-00-backup.sql",
+		"team-b/daily/orders/2025-05-05-00-00-00-backup.sql",
+		"team-a/daily/orders/nested/2025-05-06-00-00-00-backup.sql",
+	}
+	selected := func(scope Scope) []string {
+		var selected []string
+		for _, key := range keys {
+			if _, ok := scope.Time(key); ok {
+				selected = append(selected, key)
+			}
+		}
+		return selected
+	}
+
+	legacy := Scope{Database: "orders"}
+	assert.Equal(t, []string{"orders/"}, legacy.Prefixes())
+	assert.Equal(t, keys[:1], selected(legacy))
+
+	namespace := Scope{Namespace: "team-a", Database: "orders"}
+	assert.Equal(t, []string{"team-a/", "orders/"}, namespace.Prefixes())
+	assert.Equal(t, keys[:3], selected(namespace))
+
+	backupRequest := Scope{Namespace: "team-a", Name: "daily", Database: "orders"}
+	assert.Equal(t, []string{"team-a/daily/orders/", "orders/"}, backupRequest.Prefixes())
+	assert.Equal(t, keys[:2], selected(backupRequest))
+}
+
+func Test_Scope_Contains(t *testing.T) {
+	tests := []struct {
+		scope    Scope
+		key      string
+		expected bool
+	}{
+		{Scope{Database: "orders"}, "orders/dump.sql", true},
+		{Scope{Database: "orders"}, "team-a/daily/orders/dump.sql", false},
+		{Scope{Namespace: "team-a", Database: "orders"}, "team-a/daily/users/dump.sql", true},
+		{Scope{Namespace: "team-a", Database: "orders"}, "team-b/daily/orders/dump.sql", false},
+		{Scope{Namespace: "team-a", Name: "daily", Database: "orders"}, "team-a/daily/orders/dump.sql", true},
+		{Scope{Namespace: "team-a", Name: "daily", Database: "orders"}, "team-a/hourly/orders/dump.sql", false},
+		{Scope{Namespace: "team-a", Database: "team-b"}, "team-b/dump.sql", true},
+		{Scope{Namespace: "team-a", Database: "team-b"}, "team-b/daily/team-b/dump.sql", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, tt.scope.Contains(tt.key), "%+v contains %s", tt.scope, tt.key)
+	}
+}
@@ -0,0 +1,48 @@
+// Package report helps backupers and restorers to fit their run reports into
+// the termination message of the container, which Kubernetes limits to 4096 bytes.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// MaxSize is the limit of a termination message.
+const MaxSize = 4096
+
+// Marshal marshals report to JSON. While the JSON exceeds MaxSize, shrinks are
+// called one by one to drop less important details of report, which therefore
+// must be a pointer. It fails if report does not fit after all of them.
+func Marshal(report any, shrinks ...func()) ([]byte, error) {
+	for i := 0; ; i++ {
+		data, err := json.Marshal(report)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) <= MaxSize {
+			return data, nil
+		}
+		if i == len(shrinks) {
+			return nil, fmt.Errorf("report of %d bytes exceeds %d bytes", len(data), MaxSize)
+		}
+		shrinks[i]()
+	}
+}
+
+// Truncate cuts message to at most max bytes without splitting a UTF-8 sequence.
+// A cut message ends with "...".
+func Truncate(message string, max int) string {
+	const ellipsis = "..."
+	if len(message) <= max {
+		return message
+	}
+	cut := max - len(ellipsis)
+	if cut < 0 {
+		cut = 0
+	}
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	return message[:cut] + ellipsis
+}
@@ -0,0 +1,33 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Truncate(t *testing.T) {
+	assert.Equal(t, "short", Truncate("short", 10))
+	assert.Equal(t, "0123456...", Truncate(strings.Repeat("0123456789", 3), 10))
+	// "ф" takes two bytes and is not split.
+	assert.Equal(t, "фф...", Truncate(strings.Repeat("ф", 10), 8))
+}
+
+func Test_Marshal(t *testing.T) {
+	type runReport struct {
+		Key    string   `json:"key"`
+		Output []string `json:"output,omitempty"`
+	}
+	report := &runReport{Key: "orders/backup.sql", Output: []string{strings.Repeat("x", MaxSize)}}
+
+	data, err := Marshal(report)
+	assert.Nil(t, data)
+	assert.ErrorContains(t, err, "exceeds 4096 bytes")
+
+	var shrunk int
+	data, err = Marshal(report, func() { shrunk++ }, func() { shrunk++; report.Output = nil }, func() { shrunk++ })
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"key":"orders/backup.sql"}`, string(data))
+	assert.Equal(t, 2, shrunk)
+}
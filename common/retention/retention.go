@@ -0,0 +1,235 @@
+// Package retention deletes backups under a prefix which are not kept by a retention policy.
+//
+// Backups are stored as package layout tells. Time of a backup is taken from its name.
+// Objects under the prefix named otherwise or nested deeper are never deleted.
+//
+// A backup is kept if any rule of the policy selects it:
+//
+//	Last      the newest N backups
+//	Hourly    the newest backup of each of the newest N hours having backups
+//	Daily     the same for days
+//	Weekly    the same for ISO weeks
+//	Monthly   the same for months
+//	Yearly    the same for years
+//
+// A policy without rules keeps every backup. Backups older than MaxAge are deleted
+// even if a rule selects them. The newest backup is never deleted.
+//
+// Purge deletes every object under the prefix when backups are not needed anymore.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/oiler-backup/core/common/layout"
+)
+
+// maxDeleteObjects is the most keys S3 deletes in one request.
+const maxDeleteObjects = 1000
+
+// A Policy tells which backups are kept.
+type Policy struct {
+	Last    int
+	Hourly  int
+	Daily   int
+	Weekly  int
+	Monthly int
+	Yearly  int
+	// MaxAge is the age after which backups are deleted. Zero means no limit.
+	MaxAge time.Duration
+}
+
+func (p Policy) String() string {
+	return fmt.Sprintf("{Last: %d, Hourly: %d, Daily: %d, Weekly: %d, Monthly: %d, Yearly: %d, MaxAge: %s}",
+		p.Last, p.Hourly, p.Daily, p.Weekly, p.Monthly, p.Yearly, p.MaxAge)
+}
+
+// A Backup is an object of a backup in S3.
+type Backup struct {
+	Key  string
+	Time time.Time
+}
+
+// ParseAge parses age of backups, e.g. "30d", "2w" or a Go duration like "36h".
+// Empty string means no limit.
+func ParseAge(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count <= 0 {
+				return 0, fmt.Errorf("invalid age %q: expected positive number of %s", s, suffix)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	age, err := time.ParseDuration(s)
+	if err != nil || age <= 0 {
+		return 0, fmt.Errorf("invalid age %q: expected positive duration like 30d, 2w or 36h", s)
+	}
+	return age, nil
+}
+
+// periods of backups selected by rules of Policy.
+var periods = []struct {
+	count  func(Policy) int
+	period func(time.Time) string
+}{
+	{func(p Policy) int { return p.Hourly }, func(t time.Time) string { return t.Format("2006-01-02T15") }},
+	{func(p Policy) int { return p.Daily }, func(t time.Time) string { return t.Format(time.DateOnly) }},
+	{func(p Policy) int { return p.Weekly }, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	}},
+	{func(p Policy) int { return p.Monthly }, func(t time.Time) string { return t.Format("2006-01") }},
+	{func(p Policy) int { return p.Yearly }, func(t time.Time) string { return t.Format("2006") }},
+}
+
+// Expired returns backups not kept by policy at now, newest first.
+func Expired(policy Policy, backups []Backup, now time.Time) []Backup {
+	backups = slices.Clone(backups)
+	slices.SortFunc(backups, func(a, b Backup) int {
+		if c := b.Time.Compare(a.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(b.Key, a.Key)
+	})
+
+	kept := make([]bool, len(backups))
+	hasRules := policy.Last > 0
+	for i := range backups {
+		kept[i] = i < policy.Last
+	}
+	for _, rule := range periods {
+		count := rule.count(policy)
+		if count <= 0 {
+			continue
+		}
+		hasRules = true
+		last := ""
+		for i, backup := range backups {
+			if count == 0 {
+				break
+			}
+			if period := rule.period(backup.Time); period != last {
+				kept[i] = true
+				last = period
+				count--
+			}
+		}
+	}
+
+	var expired []Backup
+	for i, backup := range backups {
+		tooOld := policy.MaxAge > 0 && now.Sub(backup.Time) > policy.MaxAge
+		if i > 0 && (hasRules && !kept[i] || tooOld) {
+			expired = append(expired, backup)
+		}
+	}
+	return expired
+}
+
+// A Client lists and deletes objects of a bucket.
+type Client interface {
+	s3.ListObjectsV2APIClient
+	DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
+}
+
+// Apply deletes backups under prefix in bucketName which are not kept by policy at now.
+// It returns keys of deleted backups.
+func Apply(ctx context.Context, client Client, bucketName, prefix string, policy Policy, now time.Time) ([]string, error) {
+	backups, err := listBackups(ctx, client, bucketName, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups under %s: %w", prefix, err)
+	}
+
+	var keys []string
+	for _, backup := range Expired(policy, backups, now) {
+		keys = append(keys, backup.Key)
+	}
+	if err := deleteKeys(ctx, client, bucketName, keys); err != nil {
+		return nil, fmt.Errorf("failed to delete backups under %s: %w", prefix, err)
+	}
+	return keys, nil
+}
+
+// Purge deletes all objects under prefix in bucketName and returns their keys.
+// An empty prefix is refused, it would empty the whole bucket.
+func Purge(ctx context.Context, client Client, bucketName, prefix string) ([]string, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("refusing to purge the whole bucket %s", bucketName)
+	}
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects under %s: %w", prefix, err)
+		}
+		for _, object := range page.Contents {
+			keys = append(keys, aws.ToString(object.Key))
+		}
+	}
+	if err := deleteKeys(ctx, client, bucketName, keys); err != nil {
+		return nil, fmt.Errorf("failed to purge objects under %s: %w", prefix, err)
+	}
+	return keys, nil
+}
+
+// deleteKeys deletes keys in batches S3 accepts. Keys S3 failed to delete are an error too.
+func deleteKeys(ctx context.Context, client Client, bucketName string, keys []string) error {
+	for chunk := range slices.Chunk(keys, maxDeleteObjects) {
+		objects := make([]types.ObjectIdentifier, 0, len(chunk))
+		for _, key := range chunk {
+			objects = append(objects, types.ObjectIdentifier{Key: aws.String(key)})
+		}
+		out, err := client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucketName),
+			Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return err
+		}
+		if len(out.Errors) > 0 {
+			e := out.Errors[0]
+			return fmt.Errorf("%d of %d objects are not deleted, e.g. %s: %s",
+				len(out.Errors), len(chunk), aws.ToString(e.Key), aws.ToString(e.Message))
+		}
+	}
+	return nil
+}
+
+// listBackups lists backups under prefix. Other objects are skipped.
+func listBackups(ctx context.Context, client s3.ListObjectsV2APIClient, bucketName, prefix string) ([]Backup, error) {
+	var backups []Backup
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range page.Contents {
+			key := aws.ToString(object.Key)
+			if t, ok := layout.Time(prefix, key); ok {
+				backups = append(backups, Backup{Key: key, Time: t})
+			}
+		}
+	}
+	return backups, nil
+}
@@ -0,0 +1,227 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/oiler-backup/core/common/layout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBucket lists objects one object per page and records deleted keys.
+// Keys in undeletable are reported as errors of DeleteObjects.
+type fakeBucket struct {
+	keys        []string
+	deleted     []string
+	undeletable map[string]bool
+	err         error
+}
+
+func (f *fakeBucket) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var matching []string
+	for _, key := range f.keys {
+		if strings.HasPrefix(key, aws.ToString(params.Prefix)) {
+			matching = append(matching, key)
+		}
+	}
+
+	start := 0
+	if params.ContinuationToken != nil {
+		for i, key := range matching {
+			if key == aws.ToString(params.ContinuationToken) {
+				start = i
+			}
+		}
+	}
+	out := &s3.ListObjectsV2Output{}
+	if start < len(matching) {
+		out.Contents = []types.Object{{Key: aws.String(matching[start])}}
+	}
+	if start+1 < len(matching) {
+		out.IsTruncated = aws.Bool(true)
+		out.NextContinuationToken = aws.String(matching[start+1])
+	}
+	return out, nil
+}
+
+func (f *fakeBucket) DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	out := &s3.DeleteObjectsOutput{}
+	for _, object := range params.Delete.Objects {
+		if f.undeletable[aws.ToString(object.Key)] {
+			out.Errors = append(out.Errors, types.Error{Key: object.Key, Message: aws.String("Access Denied")})
+			continue
+		}
+		f.deleted = append(f.deleted, aws.ToString(object.Key))
+	}
+	return out, nil
+}
+
+func at(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
+}
+
+// hourly returns backups made every hour during days before now, newest first.
+func hourly(now time.Time, days int) []Backup {
+	var backups []Backup
+	for t := now; t.After(now.AddDate(0, 0, -days)); t = t.Add(-time.Hour) {
+		backups = append(backups, Backup{Key: layout.Key("mydb/", t, "sql"), Time: t})
+	}
+	return backups
+}
+
+func keptCount(policy Policy, backups []Backup, now time.Time) int {
+	return len(backups) - len(Expired(policy, backups, now))
+}
+
+func Test_ParseAge(t *testing.T) {
+	tests := []struct {
+		age  string
+		want time.Duration
+	}{
+		{"", 0},
+		{"30d", 30 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"36h", 36 * time.Hour},
+		{"90m", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		age, err := ParseAge(tt.age)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, age, tt.age)
+	}
+
+	for _, age := range []string{"d", "-1d", "0w", "month", "-5h"} {
+		_, err := ParseAge(age)
+		assert.ErrorContains(t, err, "invalid age", age)
+	}
+}
+
+func Test_Expired_NoRules(t *testing.T) {
+	now := at("2025-05-10T12:00:00Z")
+	assert.Empty(t, Expired(Policy{}, hourly(now, 30), now))
+}
+
+func Test_Expired_Last(t *testing.T) {
+	now := at("2025-05-10T12:00:00Z")
+	backups := hourly(now, 1)
+
+	expired := Expired(Policy{Last: 3}, backups, now)
+	assert.Len(t, expired, len(backups)-3)
+	assert.Equal(t, backups[3], expired[0])
+}
+
+func Test_Expired_GrandfatherFatherSon(t *testing.T) {
+	now := at("2025-05-10T12:00:00Z")
+	backups := hourly(now, 400)
+
+	// Rules overlap: the newest backups of today and yesterday are also among the newest 24 hourly ones.
+	assert.Equal(t, 24, keptCount(Policy{Hourly: 24}, backups, now))
+	assert.Equal(t, 7, keptCount(Policy{Daily: 7}, backups, now))
+	assert.Equal(t, 24+5, keptCount(Policy{Hourly: 24, Daily: 7}, backups, now))
+	assert.Equal(t, 12, keptCount(Policy{Monthly: 12}, backups, now))
+
+	expired := Expired(Policy{Hourly: 24, Daily: 7, Weekly: 4, Monthly: 12}, backups, now)
+	kept := map[string]bool{}
+	for _, backup := range backups {
+		kept[backup.Key] = true
+	}
+	for _, backup := range expired {
+		delete(kept, backup.Key)
+	}
+	assert.True(t, kept["mydb/2025-05-10-12-00-00-backup.sql"], "the newest backup")
+	assert.True(t, kept["mydb/2025-05-09-23-00-00-backup.sql"], "the newest backup of yesterday")
+	assert.True(t, kept["mydb/2025-05-04-23-00-00-backup.sql"], "the newest backup of the previous ISO week")
+	assert.True(t, kept["mydb/2024-06-30-23-00-00-backup.sql"], "the newest backup of June 2024")
+	assert.False(t, kept["mydb/2024-05-31-23-00-00-backup.sql"], "May 2024 is the 13th month")
+	assert.False(t, kept["mydb/2025-05-08-22-00-00-backup.sql"], "not the newest of its day")
+}
+
+func Test_Expired_MaxAge(t *testing.T) {
+	now := at("2025-05-10T12:00:00Z")
+	backups := hourly(now, 60)
+
+	expired := Expired(Policy{MaxAge: 30 * 24 * time.Hour}, backups, now)
+	for _, backup := range expired {
+		assert.True(t, now.Sub(backup.Time) > 30*24*time.Hour, backup.Key)
+	}
+	assert.Equal(t, 30*24+1, len(backups)-len(expired))
+
+	// Age limits backups kept by rules: today and 30 previous days.
+	assert.Equal(t, 31, keptCount(Policy{Daily: 60, MaxAge: 30 * 24 * time.Hour}, backups, now))
+}
+
+func Test_Expired_KeepsNewest(t *testing.T) {
+	now := at("2025-05-10T12:00:00Z")
+	backups := []Backup{{Key: "mydb/2025-01-01-00-00-00-backup.sql", Time: at("2025-01-01T00:00:00Z")}}
+	assert.Empty(t, Expired(Policy{MaxAge: time.Hour}, backups, now))
+}
+
+func Test_Apply(t *testing.T) {
+	bucket := &fakeBucket{keys: []string{
+		"team-a/daily/mydb/2025-05-10-12-00-00-backup.sql",
+		"team-a/daily/mydb/2025-05-10-11-00-00-backup.sql",
+		"team-a/daily/mydb/2025-05-09-12-00-00-backup.sql",
+		"team-a/daily/mydb/notes.txt",
+		"team-a/daily/mydb/2025-05-01-00-00-00-backup.sql.partial/0",
+		"team-a/daily/mydb2/2025-05-01-00-00-00-backup.sql",
+		"team-a/hourly/mydb/2025-05-01-00-00-00-backup.sql",
+		"team-b/daily/mydb/2025-05-01-00-00-00-backup.sql",
+		"mydb/2025-05-01-00-00-00-backup.sql",
+	}}
+
+	deleted, err := Apply(context.Background(), bucket, "backups", "team-a/daily/mydb/", Policy{Daily: 1}, at("2025-05-10T12:00:00Z"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"team-a/daily/mydb/2025-05-10-11-00-00-backup.sql",
+		"team-a/daily/mydb/2025-05-09-12-00-00-backup.sql",
+	}, deleted, "backups of other BackupRequests and the legacy layout are kept")
+	assert.Equal(t, deleted, bucket.deleted)
+}
+
+func Test_Apply_Error(t *testing.T) {
+	_, err := Apply(context.Background(), &fakeBucket{err: errors.New("access denied")}, "backups", "mydb/", Policy{Last: 1}, time.Now())
+	assert.ErrorContains(t, err, "failed to list backups under mydb/: access denied")
+}
+
+func Test_Purge(t *testing.T) {
+	bucket := &fakeBucket{keys: []string{
+		"team-a/daily/mydb/2025-05-10-12-00-00-backup.sql",
+		"team-a/daily/mydb/2025-05-09-12-00-00-backup.sql",
+		"team-a/daily/mydb/notes.txt",
+		"team-a/daily/mydb2/2025-05-01-00-00-00-backup.sql",
+		"team-a/daily2/mydb/2025-05-01-00-00-00-backup.sql",
+		"mydb/2025-05-01-00-00-00-backup.sql",
+	}}
+
+	deleted, err := Purge(context.Background(), bucket, "backups", "team-a/daily/mydb/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"team-a/daily/mydb/2025-05-10-12-00-00-backup.sql",
+		"team-a/daily/mydb/2025-05-09-12-00-00-backup.sql",
+		"team-a/daily/mydb/notes.txt",
+	}, deleted, "all pages are purged, objects of other prefixes are kept")
+	assert.Equal(t, deleted, bucket.deleted)
+}
+
+func Test_Purge_Errors(t *testing.T) {
+	_, err := Purge(context.Background(), &fakeBucket{keys: []string{"mydb/notes.txt"}}, "backups", "")
+	assert.ErrorContains(t, err, "refusing to purge the whole bucket backups")
+
+	bucket := &fakeBucket{
+		keys:        []string{"team-a/daily/mydb/a", "team-a/daily/mydb/b"},
+		undeletable: map[string]bool{"team-a/daily/mydb/b": true},
+	}
+	_, err = Purge(context.Background(), bucket, "backups", "team-a/daily/mydb/")
+	assert.ErrorContains(t, err, "failed to purge objects under team-a/daily/mydb/: 1 of 2 objects are not deleted, e.g. team-a/daily/mydb/b: Access Denied")
+}
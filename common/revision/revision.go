@@ -0,0 +1,164 @@
+// Package revision resolves BACKUP_REVISION selectors to S3 object keys.
+//
+// Selectors are resolved against backups of a database in a layout.Scope,
+// newest first by time of upload:
+//
+//	latest                  the newest backup
+//	newest:<N>              N-th newest backup, newest:0 is latest
+//	before:<RFC3339 time>   the newest backup uploaded at or before the time
+//
+// Backups of another database are selected by "@<database>" suffix, e.g. "latest@orders".
+// A non-negative number is an index of a backup of the scope ordered by key.
+// Any other revision is an object key, which must be under prefixes of the scope,
+// so backups of other namespaces are never restored.
+package revision
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/oiler-backup/core/common/layout"
+)
+
+const (
+	latestSelector = "latest"
+	newestPrefix   = "newest:"
+	beforePrefix   = "before:"
+	databaseSep    = "@"
+)
+
+// A selector picks a backup among backups of database.
+type selector struct {
+	// newest is a position of a backup, newest first. Used if before is zero.
+	newest int
+	before time.Time
+	// database overrides database to take backups of.
+	database string
+}
+
+// parse parses revision. It returns false if revision is not a selector.
+func parse(revision string) (selector, bool, error) {
+	revision, database, _ := strings.Cut(revision, databaseSep)
+	sel := selector{database: database}
+
+	switch {
+	case revision == latestSelector:
+	case strings.HasPrefix(revision, newestPrefix):
+		n, err := strconv.Atoi(strings.TrimPrefix(revision, newestPrefix))
+		if err != nil || n < 0 {
+			return selector{}, true, fmt.Errorf("invalid revision %q: expected non-negative number after %q", revision, newestPrefix)
+		}
+		sel.newest = n
+	case strings.HasPrefix(revision, beforePrefix):
+		before, err := time.Parse(time.RFC3339, strings.TrimPrefix(revision, beforePrefix))
+		if err != nil {
+			return selector{}, true, fmt.Errorf("invalid revision %q: %w", revision, err)
+		}
+		sel.before = before
+	default:
+		return selector{}, false, nil
+	}
+	return sel, true, nil
+}
+
+// Resolve returns a key of backup selected by revision among backups of scope in bucketName.
+func Resolve(ctx context.Context, client s3.ListObjectsV2APIClient, bucketName string, scope layout.Scope, revision string) (string, error) {
+	sel, ok, err := parse(revision)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return resolveKey(ctx, client, bucketName, scope, revision)
+	}
+	if sel.database != "" {
+		scope.Database = sel.database
+	}
+	dbName := scope.Database
+
+	backups, err := listBackups(ctx, client, bucketName, scope)
+	if err != nil {
+		return "", fmt.Errorf("failed to list backups of %s: %w", dbName, err)
+	}
+	if len(backups) == 0 {
+		return "", fmt.Errorf("no backups of %s in bucket %s", dbName, bucketName)
+	}
+
+	if !sel.before.IsZero() {
+		for _, backup := range backups {
+			if !aws.ToTime(backup.LastModified).After(sel.before) {
+				return aws.ToString(backup.Key), nil
+			}
+		}
+		return "", fmt.Errorf("no backups of %s uploaded before %s", dbName, sel.before.Format(time.RFC3339))
+	}
+
+	if sel.newest >= len(backups) {
+		return "", fmt.Errorf("revision %q is out of range. Available backups of %s: %d", revision, dbName, len(backups))
+	}
+	return aws.ToString(backups[sel.newest].Key), nil
+}
+
+// resolveKey returns a key of backup by its index among backups of scope ordered by key,
+// or revision itself if it is a key under prefixes of scope.
+func resolveKey(ctx context.Context, client s3.ListObjectsV2APIClient, bucketName string, scope layout.Scope, revision string) (string, error) {
+	index, err := strconv.Atoi(revision)
+	if err != nil || index < 0 {
+		if !scope.Contains(revision) {
+			return "", fmt.Errorf("key %q is outside of %s", revision, strings.Join(scope.Prefixes(), ", "))
+		}
+		return revision, nil
+	}
+
+	backups, err := listBackups(ctx, client, bucketName, scope)
+	if err != nil {
+		return "", fmt.Errorf("failed to list backups of %s: %w", scope.Database, err)
+	}
+	if index >= len(backups) {
+		return "", fmt.Errorf("revision %q is out of range. Available backups of %s: %d", revision, scope.Database, len(backups))
+	}
+	slices.SortFunc(backups, func(a, b types.Object) int {
+		return strings.Compare(aws.ToString(a.Key), aws.ToString(b.Key))
+	})
+	return aws.ToString(backups[index].Key), nil
+}
+
+// listBackups lists backups of scope, newest first. Other objects are skipped.
+// Objects uploaded at the same time are ordered by key, since keys contain time of backup.
+func listBackups(ctx context.Context, client s3.ListObjectsV2APIClient, bucketName string, scope layout.Scope) ([]types.Object, error) {
+	var backups []types.Object
+	listed := map[string]bool{}
+	for _, prefix := range scope.Prefixes() {
+		paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+			Bucket: aws.String(bucketName),
+			Prefix: aws.String(prefix),
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, object := range page.Contents {
+				key := aws.ToString(object.Key)
+				if _, ok := scope.Time(key); ok && !listed[key] {
+					listed[key] = true
+					backups = append(backups, object)
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(backups, func(a, b types.Object) int {
+		if c := aws.ToTime(b.LastModified).Compare(aws.ToTime(a.LastModified)); c != 0 {
+			return c
+		}
+		return strings.Compare(aws.ToString(b.Key), aws.ToString(a.Key))
+	})
+	return backups, nil
+}
@@ -0,0 +1,183 @@
+package revision
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/oiler-backup/core/common/layout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLister lists objects of a bucket one object per page.
+type fakeLister struct {
+	objects []types.Object
+	err     error
+}
+
+func (f *fakeLister) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var matching []types.Object
+	for _, object := range f.objects {
+		if strings.HasPrefix(aws.ToString(object.Key), aws.ToString(params.Prefix)) {
+			matching = append(matching, object)
+		}
+	}
+
+	start := 0
+	if params.ContinuationToken != nil {
+		for i, object := range matching {
+			if aws.ToString(object.Key) == aws.ToString(params.ContinuationToken) {
+				start = i
+			}
+		}
+	}
+	out := &s3.ListObjectsV2Output{}
+	if start < len(matching) {
+		out.Contents = matching[start : start+1]
+	}
+	if start+1 < len(matching) {
+		out.IsTruncated = aws.Bool(true)
+		out.NextContinuationToken = matching[start+1].Key
+	}
+	return out, nil
+}
+
+func backup(key string, uploadedAt string) types.Object {
+	lastModified, _ := time.Parse(time.RFC3339, uploadedAt)
+	return types.Object{Key: aws.String(key), LastModified: aws.Time(lastModified)}
+}
+
+func bucket() *fakeLister {
+	return &fakeLister{objects: []types.Object{
+		backup("mydb/2025-05-02-00-00-00-backup.sql", "2025-05-02T00:00:05Z"),
+		backup("mydb/2025-05-01-00-00-00-backup.sql", "2025-05-01T00:00:05Z"),
+		backup("orders/2025-05-04-00-00-00-backup.sql", "2025-05-04T00:00:05Z"),
+		backup("team-a/daily/mydb/2025-05-03-00-00-00-backup.sql", "2025-05-03T00:00:05Z"),
+		backup("team-a/daily/mydb/notes.txt", "2025-05-09T00:00:05Z"),
+		backup("team-a/daily/orders/2025-05-06-00-00-00-backup.sql", "2025-05-06T00:00:05Z"),
+		backup("team-a/hourly/mydb/2025-05-04-00-00-00-backup.sql", "2025-05-04T00:00:05Z"),
+		backup("team-b/daily/mydb/2025-05-05-00-00-00-backup.sql", "2025-05-05T00:00:05Z"),
+	}}
+}
+
+// teamA is a scope of backups of mydb made in namespace team-a.
+var teamA = layout.Scope{Namespace: "team-a", Database: "mydb"}
+
+func Test_Resolve(t *testing.T) {
+	tests := []struct {
+		revision string
+		key      string
+	}{
+		{"latest", "team-a/hourly/mydb/2025-05-04-00-00-00-backup.sql"},
+		{"newest:0", "team-a/hourly/mydb/2025-05-04-00-00-00-backup.sql"},
+		{"newest:1", "team-a/daily/mydb/2025-05-03-00-00-00-backup.sql"},
+		{"newest:3", "mydb/2025-05-01-00-00-00-backup.sql"},
+		{"before:2025-05-02T12:00:00Z", "mydb/2025-05-02-00-00-00-backup.sql"},
+		{"before:2025-05-02T00:00:05Z", "mydb/2025-05-02-00-00-00-backup.sql"},
+		{"before:2025-05-02T03:00:05+03:00", "mydb/2025-05-02-00-00-00-backup.sql"},
+		{"latest@orders", "team-a/daily/orders/2025-05-06-00-00-00-backup.sql"},
+		{"0", "mydb/2025-05-01-00-00-00-backup.sql"},
+		{"2", "team-a/daily/mydb/2025-05-03-00-00-00-backup.sql"},
+		{"mydb/2025-05-01-00-00-00-backup.sql", "mydb/2025-05-01-00-00-00-backup.sql"},
+		{"team-a/daily/mydb/notes.txt", "team-a/daily/mydb/notes.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.revision, func(t *testing.T) {
+			key, err := Resolve(context.Background(), bucket(), "backups", teamA, tt.revision)
+			require.NoError(t, err)
+			assert.Equal(t, tt.key, key)
+		})
+	}
+}
+
+func Test_Resolve_Scope(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope layout.Scope
+		keys  []string
+	}{
+		{"legacy", layout.Scope{Database: "mydb"}, []string{
+			"mydb/2025-05-02-00-00-00-backup.sql",
+			"mydb/2025-05-01-00-00-00-backup.sql",
+		}},
+		{"BackupRequest", layout.Scope{Namespace: "team-a", Name: "daily", Database: "mydb"}, []string{
+			"team-a/daily/mydb/2025-05-03-00-00-00-backup.sql",
+			"mydb/2025-05-02-00-00-00-backup.sql",
+			"mydb/2025-05-01-00-00-00-backup.sql",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, expected := range tt.keys {
+				key, err := Resolve(context.Background(), bucket(), "backups", tt.scope, fmt.Sprintf("newest:%d", i))
+				require.NoError(t, err)
+				assert.Equal(t, expected, key)
+			}
+			_, err := Resolve(context.Background(), bucket(), "backups", tt.scope, fmt.Sprintf("newest:%d", len(tt.keys)))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_Resolve_SameNamespaceAndDatabase(t *testing.T) {
+	lister := &fakeLister{objects: []types.Object{
+		backup("mydb/2025-05-01-00-00-00-backup.sql", "2025-05-01T00:00:05Z"),
+		backup("mydb/daily/mydb/2025-05-02-00-00-00-backup.sql", "2025-05-02T00:00:05Z"),
+	}}
+	scope := layout.Scope{Namespace: "mydb", Database: "mydb"}
+
+	for i, expected := range []string{"mydb/daily/mydb/2025-05-02-00-00-00-backup.sql", "mydb/2025-05-01-00-00-00-backup.sql"} {
+		key, err := Resolve(context.Background(), lister, "backups", scope, fmt.Sprintf("newest:%d", i))
+		require.NoError(t, err)
+		assert.Equal(t, expected, key)
+	}
+	_, err := Resolve(context.Background(), lister, "backups", scope, "newest:2")
+	assert.ErrorContains(t, err, "Available backups of mydb: 2", "backups listed by both prefixes are counted once")
+}
+
+func Test_Resolve_SameUploadTime(t *testing.T) {
+	lister := &fakeLister{objects: []types.Object{
+		backup("mydb/2025-05-01-00-00-00-backup.sql", "2025-05-01T00:00:00Z"),
+		backup("mydb/2025-05-01-00-00-01-backup.sql", "2025-05-01T00:00:00Z"),
+	}}
+
+	key, err := Resolve(context.Background(), lister, "backups", teamA, "latest")
+	require.NoError(t, err)
+	assert.Equal(t, "mydb/2025-05-01-00-00-01-backup.sql", key)
+}
+
+func Test_Resolve_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		lister   *fakeLister
+		revision string
+		err      string
+	}{
+		{"invalid number", bucket(), "newest:-1", "expected non-negative number"},
+		{"invalid time", bucket(), "before:yesterday", "invalid revision"},
+		{"out of range", bucket(), "newest:4", "out of range. Available backups of mydb: 4"},
+		{"nothing before", bucket(), "before:2025-04-30T00:00:00Z", "no backups of mydb uploaded before 2025-04-30T00:00:00Z"},
+		{"no backups", bucket(), "latest@payments", "no backups of payments in bucket backups"},
+		{"index out of range", bucket(), "4", "out of range. Available backups of mydb: 4"},
+		{"key of another namespace", bucket(), "team-b/daily/mydb/2025-05-05-00-00-00-backup.sql", `key "team-b/daily/mydb/2025-05-05-00-00-00-backup.sql" is outside of team-a/, mydb/`},
+		{"nested legacy key", bucket(), "mydb/team-b/daily/mydb/backup.sql", "is outside of"},
+		{"list failure", &fakeLister{err: errors.New("access denied")}, "latest", "failed to list backups of mydb: access denied"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Resolve(context.Background(), tt.lister, "backups", teamA, tt.revision)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
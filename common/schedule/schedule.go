@@ -0,0 +1,156 @@
+// Package schedule implements schedules of BackupRequest: the grammar of CronJob
+// extended with hashed values, which adapters expand before creating CronJobs.
+package schedule
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Descriptors are the predefined schedules CronJob accepts besides five fields.
+var Descriptors = []string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+
+// scheduleFields are ranges of the fields of a standard cron schedule.
+// Days of month are limited to 28 for H, so a backup runs every month.
+var scheduleFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 28},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// parser parses five fields of a CronJob schedule.
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// Validate checks schedule against the grammar of CronJob: five fields, possibly hashed,
+// or one of Descriptors. Time zones are rejected, they are set by spec.timeZone.
+func Validate(schedule string) error {
+	if schedule == "" {
+		return fmt.Errorf("schedule is empty")
+	}
+	if timeZone, _ := cutTimeZone(schedule); timeZone != "" {
+		return fmt.Errorf("time zone must be set in timeZone, not in schedule")
+	}
+	if strings.HasPrefix(schedule, "@") {
+		if !slices.Contains(Descriptors, schedule) {
+			return fmt.Errorf("unsupported descriptor %q, expected one of %s", schedule, strings.Join(Descriptors, ", "))
+		}
+		return nil
+	}
+	// Expansion does not depend on the seed, any seed checks hashed values.
+	expanded, err := Expand(schedule, "")
+	if err != nil {
+		return err
+	}
+	if _, err := parser.Parse(expanded); err != nil {
+		return fmt.Errorf("invalid schedule %q: %w", schedule, err)
+	}
+	return nil
+}
+
+// Expand replaces hashed values of schedule like Jenkins does, so CronJobs
+// with the same schedule do not start at the same moment:
+//
+//	H          a value of the field picked by seed, e.g. "H * * * *" runs hourly at minute 0-59
+//	H(a-b)     a value between a and b
+//	H/n        every n-th value starting from an offset below n, e.g. "H/15" is "7-59/15"
+//	H(a-b)/n   the same within a and b
+//
+// The same seed always gives the same schedule. Schedules without H are returned as is.
+// A TZ= or CRON_TZ= prefix of schedules admitted before spec.timeZone is kept.
+func Expand(schedule, seed string) (string, error) {
+	timeZone, fields := cutTimeZone(schedule)
+	if !strings.Contains(fields, "H") || strings.HasPrefix(fields, "@") {
+		return schedule, nil
+	}
+	values := strings.Fields(fields)
+	if len(values) != len(scheduleFields) {
+		return "", fmt.Errorf("invalid schedule %q: expected %d fields", schedule, len(scheduleFields))
+	}
+
+	for i, field := range values {
+		items := strings.Split(field, ",")
+		for j, item := range items {
+			expanded, err := expandHash(item, scheduleFields[i].min, scheduleFields[i].max, hash(seed, i))
+			if err != nil {
+				return "", fmt.Errorf("invalid %s %q of schedule %q: %w", scheduleFields[i].name, item, schedule, err)
+			}
+			items[j] = expanded
+		}
+		values[i] = strings.Join(items, ",")
+	}
+	return timeZone + strings.Join(values, " "), nil
+}
+
+// cutTimeZone splits schedule into a TZ= or CRON_TZ= prefix with the trailing space and the rest.
+func cutTimeZone(schedule string) (timeZone, fields string) {
+	if !strings.HasPrefix(schedule, "TZ=") && !strings.HasPrefix(schedule, "CRON_TZ=") {
+		return "", schedule
+	}
+	timeZone, fields, _ = strings.Cut(schedule, " ")
+	return timeZone + " ", fields
+}
+
+// expandHash expands value of a field ranging from lo to hi if it is hashed.
+func expandHash(value string, lo, hi int, h uint64) (string, error) {
+	rest, ok := strings.CutPrefix(value, "H")
+	if !ok {
+		return value, nil
+	}
+
+	if spec, ok := strings.CutPrefix(rest, "("); ok {
+		bounds, after, ok := strings.Cut(spec, ")")
+		if !ok {
+			return "", fmt.Errorf("missing )")
+		}
+		from, to, ok := strings.Cut(bounds, "-")
+		if !ok {
+			return "", fmt.Errorf("expected range like H(0-29)")
+		}
+		var err error
+		if lo, err = boundedInt(from, lo, hi); err != nil {
+			return "", err
+		}
+		if hi, err = boundedInt(to, lo, hi); err != nil {
+			return "", err
+		}
+		rest = after
+	}
+
+	if rest == "" {
+		return strconv.Itoa(lo + int(h%uint64(hi-lo+1))), nil
+	}
+	step, ok := strings.CutPrefix(rest, "/")
+	if !ok {
+		return "", fmt.Errorf("unexpected %q after H", rest)
+	}
+	n, err := boundedInt(step, 1, hi-lo+1)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%d/%d", lo+int(h%uint64(n)), hi, n), nil
+}
+
+func boundedInt(s string, lo, hi int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < lo || n > hi {
+		return 0, fmt.Errorf("%q is not a number between %d and %d", s, lo, hi)
+	}
+	return n, nil
+}
+
+// hash returns a hash of seed for the field with index.
+func hash(seed string, index int) uint64 {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%s/%d", seed, index)
+	return h.Sum64()
+}
@@ -0,0 +1,75 @@
+package schedule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Expand_NoHash(t *testing.T) {
+	for _, schedule := range []string{"", "0 3 * * *", "*/15 * * * 1-5", "@hourly"} {
+		expanded, err := Expand(schedule, "team-a/db:5432/orders")
+		require.NoError(t, err)
+		assert.Equal(t, schedule, expanded)
+	}
+}
+
+func Test_Expand_Deterministic(t *testing.T) {
+	first, err := Expand("H H * * *", "team-a/db:5432/orders")
+	require.NoError(t, err)
+	second, err := Expand("H H * * *", "team-a/db:5432/orders")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.NotContains(t, first, "H")
+
+	minutes := map[string]bool{}
+	for _, db := range []string{"orders", "users", "billing", "audit", "events", "stock"} {
+		expanded, err := Expand("H * * * *", "team-a/db:5432/"+db)
+		require.NoError(t, err)
+		minutes[expanded] = true
+	}
+	assert.Greater(t, len(minutes), 1, "schedules of different databases must spread")
+}
+
+func Test_Expand_Forms(t *testing.T) {
+	tests := []struct {
+		schedule string
+		pattern  string
+	}{
+		{"H * * * *", `^([0-9]|[1-5][0-9]) \* \* \* \*$`},
+		{"0 H(1-5) * * *", `^0 [1-5] \* \* \*$`},
+		{"H/15 * * * *", `^([0-9]|1[0-4])-59/15 \* \* \* \*$`},
+		{"0 H(0-11)/6 * * *", `^0 [0-5]-11/6 \* \* \*$`},
+		{"0 0 H * H,0", `^0 0 ([1-9]|1[0-9]|2[0-8]) \* [0-6],0$`},
+		{"CRON_TZ=Europe/Helsinki H * * * *", `^CRON_TZ=Europe/Helsinki ([0-9]|[1-5][0-9]) \* \* \* \*$`},
+	}
+	for _, tt := range tests {
+		expanded, err := Expand(tt.schedule, "team-a/db:5432/orders")
+		require.NoError(t, err, tt.schedule)
+		assert.Regexp(t, tt.pattern, expanded, tt.schedule)
+	}
+}
+
+func Test_Expand_Invalid(t *testing.T) {
+	for _, schedule := range []string{"H * * *", "H(1-5 * * * *", "H(30-10) * * * *", "H(0-99) * * * *", "H/0 * * * *", "Hx * * * *"} {
+		_, err := Expand(schedule, "team-a/db:5432/orders")
+		assert.Error(t, err, schedule)
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	for _, schedule := range []string{"0 3 * * *", "*/15 * * * MON-FRI", "@daily", "H * * * *", "H H(1-5) * * *", "H/15 * * * *", "0 H(0-11)/6 * * H", "0 0 H(1-28) * *"} {
+		assert.NoError(t, Validate(schedule), schedule)
+	}
+}
+
+func Test_Validate_Invalid(t *testing.T) {
+	for _, schedule := range []string{
+		"", "0 0 * *", "0 0 0 * * *", "60 * * * *", "@every 5m", "@reboot",
+		"TZ=UTC 0 0 * * *", "CRON_TZ=Europe/Helsinki H * * * *",
+		"0 0 H(1-31) * *", "H(0-99) * * * *",
+	} {
+		assert.Error(t, Validate(schedule), schedule)
+	}
+}
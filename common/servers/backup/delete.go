@@ -0,0 +1,124 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PurgeEnv switches backuper to deletion of all backups of the BackupRequest.
+const PurgeEnv = "PURGE"
+
+// The BackupService of the base library has no method deleting a CronJob. Its proto lives
+// in the external base module, which core, schedulers and backupers pin at different versions,
+// so adding Delete there would need a release of base and an update of every module at once.
+// Deletion is a separate service registered next to it instead. Its messages are encoded as
+// JSON, since generating protobuf code for them would need a proto of their own, and adapters
+// which do not register it answer with codes.Unimplemented, which core reports as unsupported.
+const (
+	deletionServiceName = "oiler.backup.DeletionService"
+	deleteMethod        = "/" + deletionServiceName + "/Delete"
+	jsonCodecName       = "json"
+)
+
+// A DeleteRequest asks to delete backuper CronJob and purge its backups from S3.
+type DeleteRequest struct {
+	// Request is the BackupRequest message of the BackupService encoded by protojson.
+	// The purge Job is built from it like Update builds the CronJob.
+	Request          json.RawMessage `json:"request"`
+	CronjobName      string          `json:"cronjobName"`
+	CronjobNamespace string          `json:"cronjobNamespace"`
+}
+
+// A DeleteResponse names the Job purging backups.
+type DeleteResponse struct {
+	JobName      string `json:"jobName"`
+	JobNamespace string `json:"jobNamespace"`
+}
+
+// A DeletionServer deletes backuper CronJobs. The overlay of the request is applied
+// to the purge Job like to the CronJob updated by Update.
+type DeletionServer interface {
+	Delete(ctx context.Context, req *DeleteRequest) (*DeleteResponse, error)
+}
+
+// RegisterDeletionServer registers srv next to the BackupService of the adapter.
+func RegisterDeletionServer(s grpc.ServiceRegistrar, srv DeletionServer) {
+	s.RegisterService(&deletionServiceDesc, srv)
+}
+
+var deletionServiceDesc = grpc.ServiceDesc{
+	ServiceName: deletionServiceName,
+	HandlerType: (*DeletionServer)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Delete",
+		Handler:    deleteHandler,
+	}},
+	Streams: []grpc.StreamDesc{},
+}
+
+func deleteHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	req := new(DeleteRequest)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeletionServer).Delete(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: deleteMethod}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(DeletionServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+// A DeletionClient calls DeletionServer of an adapter.
+type DeletionClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewDeletionClient returns a client of DeletionServer reachable by cc.
+func NewDeletionClient(cc grpc.ClientConnInterface) DeletionClient {
+	return DeletionClient{cc: cc}
+}
+
+// Delete asks adapter to delete backuper CronJob and start a Job purging its backups.
+// Adapters without DeletionServer answer with codes.Unimplemented.
+func (c DeletionClient) Delete(ctx context.Context, req *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	resp := new(DeleteResponse)
+	opts = append([]grpc.CallOption{grpc.CallContentSubtype(jsonCodecName)}, opts...)
+	if err := c.cc.Invoke(ctx, deleteMethod, req, resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// jsonCodec encodes messages of DeletionServer, which are plain Go structs.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                       { return jsonCodecName }
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// PurgeJobFromCronJob builds a Job from cj which deletes backups instead of making one.
+// The Job is named after cj, so the same CronJob always has the same purge Job.
+func PurgeJobFromCronJob(cj *batchv1.CronJob) *batchv1.Job {
+	job := JobFromCronJob(cj)
+	job.Name = "purge-" + strings.TrimPrefix(cj.Name, "backup-")
+	delete(job.Annotations, "cronjob.kubernetes.io/instantiate")
+
+	containers := job.Spec.Template.Spec.Containers
+	for i := range containers {
+		containers[i].Env = append(containers[i].Env, corev1.EnvVar{Name: PurgeEnv, Value: "true"})
+	}
+	return job
+}
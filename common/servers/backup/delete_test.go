@@ -0,0 +1,84 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// fakeDeletionServer records requests and overlays it is called with.
+type fakeDeletionServer struct {
+	req     *DeleteRequest
+	overlay []string
+}
+
+func (f *fakeDeletionServer) Delete(ctx context.Context, req *DeleteRequest) (*DeleteResponse, error) {
+	f.req = req
+	md, _ := metadata.FromIncomingContext(ctx)
+	f.overlay = md.Get(OverlayMetadataKey)
+	return &DeleteResponse{JobName: "purge-example", JobNamespace: req.CronjobNamespace}, nil
+}
+
+// dialServer serves s on a local port and returns a connection to it.
+func dialServer(t *testing.T, s *grpc.Server) *grpc.ClientConn {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func Test_DeletionClient(t *testing.T) {
+	srv := &fakeDeletionServer{}
+	s := grpc.NewServer()
+	RegisterDeletionServer(s, srv)
+	conn := dialServer(t, s)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), OverlayMetadataKey, `{"spec":{}}`)
+	resp, err := NewDeletionClient(conn).Delete(ctx, &DeleteRequest{
+		Request:          json.RawMessage(`{"schedule":"0 0 * * *"}`),
+		CronjobName:      "backup-example",
+		CronjobNamespace: "team-a",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, &DeleteResponse{JobName: "purge-example", JobNamespace: "team-a"}, resp)
+	assert.JSONEq(t, `{"schedule":"0 0 * * *"}`, string(srv.req.Request))
+	assert.Equal(t, "backup-example", srv.req.CronjobName)
+	assert.Equal(t, "team-a", srv.req.CronjobNamespace)
+	assert.Equal(t, []string{`{"spec":{}}`}, srv.overlay)
+}
+
+func Test_DeletionClient_Unimplemented(t *testing.T) {
+	conn := dialServer(t, grpc.NewServer())
+
+	_, err := NewDeletionClient(conn).Delete(context.Background(), &DeleteRequest{CronjobName: "backup-example"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func Test_PurgeJobFromCronJob(t *testing.T) {
+	cj := backuperCronJob()
+
+	job := PurgeJobFromCronJob(cj)
+
+	assert.Equal(t, "purge-1a2b3c4d-example", job.Name)
+	assert.Equal(t, "team-a", job.Namespace)
+	assert.NotContains(t, job.Annotations, "cronjob.kubernetes.io/instantiate")
+	assert.Equal(t, []corev1.EnvVar{{Name: "DB_HOST", Value: "db"}, {Name: PurgeEnv, Value: "true"}},
+		job.Spec.Template.Spec.Containers[0].Env)
+	assert.Len(t, cj.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env, 1, "CronJob is not changed")
+}
@@ -0,0 +1,4 @@
+// Package backup provides parts of gRPC servers of adapters which do not depend
+// on the database type: gRPC metadata passed by Kubernetes Operator Core, the deletion service,
+// Jobs built from backuper CronJobs, hashed schedules and TLS.
+package backup
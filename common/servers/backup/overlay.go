@@ -0,0 +1,75 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc/metadata"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// OverlayMetadataKey is a gRPC metadata key carrying a strategic merge patch
+// which is applied to generated CronJob or Job before creating it.
+const OverlayMetadataKey = "x-oiler-overlay"
+
+// An ErrOverlay is required for more verbosity.
+type ErrOverlay = error
+
+// overlayFromContext returns overlay passed by Kubernetes Operator Core.
+// Returns false if no overlay is passed.
+func overlayFromContext(ctx context.Context) ([]byte, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	values := md.Get(OverlayMetadataKey)
+	if len(values) == 0 || values[0] == "" {
+		return nil, false
+	}
+
+	return []byte(values[0]), true
+}
+
+// patchedJSON returns obj patched by overlay from ctx or nil if there is no overlay.
+// dataStruct defines patch strategy and must be of the same type as obj.
+func patchedJSON(ctx context.Context, obj, dataStruct any) ([]byte, ErrOverlay) {
+	patch, ok := overlayFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal object: %w", err)
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, patch, dataStruct)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply overlay: %w", err)
+	}
+
+	return patched, nil
+}
+
+// ApplyCronJobOverlay patches cj in place by overlay from ctx.
+func ApplyCronJobOverlay(ctx context.Context, cj *batchv1.CronJob) ErrOverlay {
+	patched, err := patchedJSON(ctx, cj, batchv1.CronJob{})
+	if err != nil || patched == nil {
+		return err
+	}
+
+	*cj = batchv1.CronJob{}
+	return json.Unmarshal(patched, cj)
+}
+
+// ApplyJobOverlay patches job in place by overlay from ctx.
+func ApplyJobOverlay(ctx context.Context, job *batchv1.Job) ErrOverlay {
+	patched, err := patchedJSON(ctx, job, batchv1.Job{})
+	if err != nil || patched == nil {
+		return err
+	}
+
+	*job = batchv1.Job{}
+	return json.Unmarshal(patched, job)
+}
@@ -0,0 +1,109 @@
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const secretEnvOverlay = `{"spec":{"template":{"spec":{"containers":[{"name":"backup-restore-job",` +
+	`"env":[{"name":"DB_PASSWORD","valueFrom":{"secretKeyRef":{"name":"creds","key":"DB_PASSWORD"}}}]}]}}}}`
+
+func overlayContext(patch string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(OverlayMetadataKey, patch))
+}
+
+func restorerJob() *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: "default"},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "backup-restore-job",
+							Env: []corev1.EnvVar{
+								{Name: "DB_HOST", Value: "localhost"},
+								{Name: "DB_PASSWORD", Value: ""},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_ApplyJobOverlay(t *testing.T) {
+	job := restorerJob()
+
+	err := ApplyJobOverlay(overlayContext(secretEnvOverlay), job)
+	require.NoError(t, err)
+
+	envs := job.Spec.Template.Spec.Containers[0].Env
+	require.Len(t, envs, 2)
+	assert.Equal(t, corev1.EnvVar{Name: "DB_HOST", Value: "localhost"}, envs[0])
+	assert.Equal(t, "DB_PASSWORD", envs[1].Name)
+	assert.Empty(t, envs[1].Value)
+	require.NotNil(t, envs[1].ValueFrom)
+	assert.Equal(t, "creds", envs[1].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, "DB_PASSWORD", envs[1].ValueFrom.SecretKeyRef.Key)
+}
+
+func Test_ApplyJobOverlay_NoOverlay(t *testing.T) {
+	job := restorerJob()
+
+	err := ApplyJobOverlay(context.Background(), job)
+	require.NoError(t, err)
+	assert.Equal(t, restorerJob(), job)
+}
+
+func Test_ApplyCronJobOverlay_Namespace(t *testing.T) {
+	cj := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "oiler-backup-system"},
+		Spec:       batchv1.CronJobSpec{Schedule: "0 0 * * *"},
+	}
+
+	err := ApplyCronJobOverlay(overlayContext(`{"metadata":{"namespace":"team-a"},"spec":{}}`), cj)
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", cj.Namespace)
+	assert.Equal(t, "backup", cj.Name)
+	assert.Equal(t, "0 0 * * *", cj.Spec.Schedule)
+}
+
+func Test_ApplyCronJobOverlay_PodTemplate(t *testing.T) {
+	cj := backuperCronJob()
+	patch := `{"spec":{"jobTemplate":{"spec":{"template":{` +
+		`"metadata":{"labels":{"team":"a"}},` +
+		`"spec":{"tolerations":[{"key":"backup","operator":"Exists"}],"volumes":[{"name":"scratch","emptyDir":{}}],` +
+		`"containers":[{"name":"backup-job","resources":{"requests":{"memory":"1Gi"}},` +
+		`"volumeMounts":[{"name":"scratch","mountPath":"/tmp"}]}]}}}}}}`
+
+	err := ApplyCronJobOverlay(overlayContext(patch), cj)
+	require.NoError(t, err)
+
+	template := cj.Spec.JobTemplate.Spec.Template
+	assert.Equal(t, map[string]string{"team": "a"}, template.Labels)
+	assert.Equal(t, "backup", template.Spec.Tolerations[0].Key)
+	assert.Equal(t, "scratch", template.Spec.Volumes[0].Name)
+	require.Len(t, template.Spec.Containers, 1)
+	container := template.Spec.Containers[0]
+	assert.Equal(t, "backuper", container.Image, "fields of adapter are kept")
+	assert.Equal(t, []corev1.EnvVar{{Name: "DB_HOST", Value: "db"}}, container.Env)
+	assert.Equal(t, "1Gi", container.Resources.Requests.Memory().String())
+	assert.Equal(t, "/tmp", container.VolumeMounts[0].MountPath)
+}
+
+func Test_ApplyCronJobOverlay_Invalid(t *testing.T) {
+	cj := &batchv1.CronJob{}
+
+	err := ApplyCronJobOverlay(overlayContext("not a json"), cj)
+	require.Error(t, err)
+}
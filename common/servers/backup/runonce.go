@@ -0,0 +1,49 @@
+package backup
+
+import (
+	"context"
+	"maps"
+
+	"google.golang.org/grpc/metadata"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RunOnceMetadataKey is a gRPC metadata key asking Backup to create a one-off Job
+// from the template of backuper CronJob instead of the CronJob itself.
+const RunOnceMetadataKey = "x-oiler-run-once"
+
+// RunOnceFromContext tells whether Kubernetes Operator Core asked for a one-off Job.
+func RunOnceFromContext(ctx context.Context) bool {
+	return flagFromContext(ctx, RunOnceMetadataKey)
+}
+
+// flagFromContext tells whether key of incoming gRPC metadata of ctx is "true".
+func flagFromContext(ctx context.Context, key string) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	values := md.Get(key)
+	return len(values) > 0 && values[0] == "true"
+}
+
+// JobFromCronJob builds a Job from jobTemplate of cj like `kubectl create job --from=cronjob` does.
+func JobFromCronJob(cj *batchv1.CronJob) *batchv1.Job {
+	annotations := map[string]string{"cronjob.kubernetes.io/instantiate": "manual"}
+	maps.Copy(annotations, cj.Spec.JobTemplate.Annotations)
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        cj.Name,
+			Namespace:   cj.Namespace,
+			Labels:      maps.Clone(cj.Spec.JobTemplate.Labels),
+			Annotations: annotations,
+		},
+		Spec: *cj.Spec.JobTemplate.Spec.DeepCopy(),
+	}
+}
@@ -0,0 +1,60 @@
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func runOnceContext() context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(RunOnceMetadataKey, "true"))
+}
+
+func backuperCronJob() *batchv1.CronJob {
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-1a2b3c4d-example", Namespace: "team-a"},
+		Spec: batchv1.CronJobSpec{
+			Schedule: "0 0 * * *",
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "backup"}},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Name:  "backup-job",
+								Image: "backuper",
+								Env:   []corev1.EnvVar{{Name: "DB_HOST", Value: "db"}},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_RunOnceFromContext(t *testing.T) {
+	assert.False(t, RunOnceFromContext(context.Background()))
+	assert.False(t, RunOnceFromContext(overlayContext(`{}`)))
+	assert.True(t, RunOnceFromContext(runOnceContext()))
+}
+
+func Test_JobFromCronJob(t *testing.T) {
+	cj := backuperCronJob()
+
+	job := JobFromCronJob(cj)
+
+	assert.Equal(t, "backup-1a2b3c4d-example", job.Name)
+	assert.Equal(t, "team-a", job.Namespace)
+	assert.Equal(t, map[string]string{"app": "backup"}, job.Labels)
+	assert.Equal(t, "manual", job.Annotations["cronjob.kubernetes.io/instantiate"])
+	assert.Equal(t, cj.Spec.JobTemplate.Spec, job.Spec)
+
+	job.Labels["app"] = "changed"
+	assert.Equal(t, "backup", cj.Spec.JobTemplate.Labels["app"], "CronJob is not changed")
+}
@@ -0,0 +1,27 @@
+package backup
+
+import (
+	"fmt"
+
+	"github.com/oiler-backup/core/common/schedule"
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// An ErrSchedule is required for more verbosity.
+type ErrSchedule = error
+
+// ScheduleSeed identifies backups of database at host:port made in namespace
+// and stays the same while the BackupRequest backs up the same database.
+func ScheduleSeed(namespace, host string, port int64, database string) string {
+	return fmt.Sprintf("%s/%s:%d/%s", namespace, host, port, database)
+}
+
+// ApplySchedule expands hashed values of the schedule of cj by seed, see schedule.Expand.
+func ApplySchedule(cj *batchv1.CronJob, seed string) ErrSchedule {
+	expanded, err := schedule.Expand(cj.Spec.Schedule, seed)
+	if err != nil {
+		return err
+	}
+	cj.Spec.Schedule = expanded
+	return nil
+}
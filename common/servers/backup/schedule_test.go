@@ -0,0 +1,31 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/oiler-backup/core/common/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ScheduleSeed(t *testing.T) {
+	assert.Equal(t, "team-a/db:5432/orders", ScheduleSeed("team-a", "db", 5432, "orders"))
+}
+
+func Test_ApplySchedule(t *testing.T) {
+	cj := backuperCronJob()
+	cj.Spec.Schedule = "H 3 * * *"
+
+	require.NoError(t, ApplySchedule(cj, "team-a/db:5432/orders"))
+	expected, err := schedule.Expand("H 3 * * *", "team-a/db:5432/orders")
+	require.NoError(t, err)
+	assert.Equal(t, expected, cj.Spec.Schedule)
+}
+
+func Test_ApplySchedule_Invalid(t *testing.T) {
+	cj := backuperCronJob()
+	cj.Spec.Schedule = "H(0-99) * * * *"
+
+	require.Error(t, ApplySchedule(cj, "team-a/db:5432/orders"))
+	assert.Equal(t, "H(0-99) * * * *", cj.Spec.Schedule, "CronJob is not changed")
+}
@@ -0,0 +1,77 @@
+package backup
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCertificate writes a self-signed PEM certificate and its key to dir.
+func writeCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "adapter"},
+		DNSNames:     []string{"adapter"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile = filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func Test_TransportCredentials_Insecure(t *testing.T) {
+	creds, err := TransportCredentials("", "", "")
+
+	require.NoError(t, err)
+	assert.Equal(t, "insecure", creds.Info().SecurityProtocol)
+}
+
+func Test_TransportCredentials_TLS(t *testing.T) {
+	certFile, keyFile := writeCertificate(t, t.TempDir())
+
+	creds, err := TransportCredentials(certFile, keyFile, certFile)
+
+	require.NoError(t, err)
+	assert.Equal(t, "tls", creds.Info().SecurityProtocol)
+}
+
+func Test_TransportCredentials_MissingCertificate(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := TransportCredentials(filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), "")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load certificate")
+}
+
+func Test_TransportCredentials_InvalidClientCA(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir)
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+
+	_, err := TransportCredentials(certFile, keyFile, caFile)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no PEM certificates")
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupRequestLabel is set on Backups to the name of BackupRequest which made them.
+const BackupRequestLabel = "backup.oiler.backup/backup-request"
+
+// BackupSpec describes a backup uploaded to S3 by a run of BackupRequest.
+type BackupSpec struct {
+	// BackupRequestName is a name of BackupRequest which made the backup.
+	BackupRequestName string `json:"backupRequestName"`
+	// JobName is a name of the Job which made the backup.
+	JobName string `json:"jobName"`
+
+	DatabaseType string `json:"databaseType"`
+	// DatabaseVersion is a version of the database server.
+	// +optional
+	DatabaseVersion string `json:"databaseVersion,omitempty"`
+	// ToolVersion is a version of the dump tool, e.g. pg_dump.
+	// +optional
+	ToolVersion string `json:"toolVersion,omitempty"`
+
+	// StorageLocationName is a name of BackupStorageLocation used by BackupRequest.
+	// +optional
+	StorageLocationName string `json:"storageLocationName,omitempty"`
+	Endpoint            string `json:"endpoint"`
+	BucketName          string `json:"bucketName"`
+	// Key is an S3 object key of the backup.
+	Key string `json:"key"`
+	// Size of the backup in bytes.
+	// +optional
+	Size int64 `json:"size,omitempty"`
+	// Checksum of the backup in form of <algorithm>:<hex digest>.
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:printcolumn:name="BackupRequest",type=string,JSONPath=`.spec.backupRequestName`
+// +kubebuilder:printcolumn:name="Key",type=string,JSONPath=`.spec.key`
+// +kubebuilder:printcolumn:name="Size",type=integer,JSONPath=`.spec.size`
+// +kubebuilder:printcolumn:name="Completed",type=date,JSONPath=`.spec.completionTime`
+// Backup is a record of a backup stored in S3. It is created by the operator
+// for each successful run of BackupRequest and owned by it. It is deleted when
+// retention of the BackupRequest deletes the backup from S3.
+type Backup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BackupSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// BackupList contains a list of Backup.
+type BackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Backup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Backup{}, &BackupList{})
+}
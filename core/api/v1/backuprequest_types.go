@@ -17,16 +17,34 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// SecretKeyReference points to a single key of a Kubernetes Secret.
+type SecretKeyReference struct {
+	Name string `json:"name"`
+	// Namespace of the Secret. Must be empty or match the namespace of the resource.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	Key       string `json:"key"`
+}
+
 type DatabaseSpec struct {
-	URI    string `json:"uri"`
-	Port   int    `json:"port"`
-	User   string `json:"user"`
-	Pass   string `json:"pass"`
-	DbName string `json:"dbName"`
-	DbType string `json:"dbType"`
+	URI string `json:"uri"`
+	// Port defaults to the well-known port of DbType.
+	// +optional
+	Port int    `json:"port,omitempty"`
+	User string `json:"user"`
+	// Pass is a plaintext password. Prefer PasswordSecretRef.
+	// +optional
+	Pass string `json:"pass,omitempty"`
+	// PasswordSecretRef references a Secret key holding the password.
+	// Takes precedence over Pass.
+	// +optional
+	PasswordSecretRef *SecretKeyReference `json:"passwordSecretRef,omitempty"`
+	DbName            string              `json:"dbName"`
+	DbType            string              `json:"dbType"`
 }
 
 type S3Auth struct {
@@ -34,9 +52,297 @@ type S3Auth struct {
 	SecretKey string `json:"secretKey"`
 }
 type S3Spec struct {
-	Endpoint   string `json:"endpoint"`
-	Auth       S3Auth `json:"auth"`
-	BucketName string `json:"bucketName"`
+	// StorageLocationName is a name of BackupStorageLocation to take
+	// endpoint, bucket and credentials from. Credentials below override it,
+	// Endpoint and BucketName may not be set with it.
+	// +optional
+	StorageLocationName string `json:"storageLocationName,omitempty"`
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+	// Auth holds plaintext keys. Prefer CredentialsSecretRef.
+	// +optional
+	Auth S3Auth `json:"auth,omitempty"`
+	// CredentialsSecretRef references a Secret holding S3 keys.
+	// Takes precedence over Auth.
+	// +optional
+	CredentialsSecretRef *S3CredentialsSecretRef `json:"credentialsSecretRef,omitempty"`
+	// +optional
+	BucketName string `json:"bucketName,omitempty"`
+}
+
+// RetentionPolicy tells which backups are kept in S3 besides the newest MaxBackupCount ones.
+// A backup is kept if any rule selects it: e.g. Daily keeps the newest backup of each
+// of the newest Daily days having backups. The newest backup is never deleted.
+type RetentionPolicy struct {
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Hourly int32 `json:"hourly,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Daily int32 `json:"daily,omitempty"`
+	// Weekly counts ISO weeks.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Weekly int32 `json:"weekly,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Monthly int32 `json:"monthly,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Yearly int32 `json:"yearly,omitempty"`
+	// MaxAge deletes backups older than it even if a rule keeps them,
+	// e.g. "30d", "2w" or "36h".
+	// +optional
+	MaxAge string `json:"maxAge,omitempty"`
+}
+
+// JobControls tune Jobs of backups and restores. Unset fields keep defaults of adapters.
+type JobControls struct {
+	// ActiveDeadlineSeconds limits how long a Job may run before it is failed.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+	// BackoffLimit is a number of retries before a Job is failed.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+	// TTLSecondsAfterFinished deletes a finished Job after the delay.
+	// The operator must see the Job finish first, so keep it well above a few seconds.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+// CronJobControls tune scheduling of backups. Unset fields keep defaults of adapters.
+type CronJobControls struct {
+	// ConcurrencyPolicy tells what to do if the previous backup is still running when the next one is due.
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	// +optional
+	ConcurrencyPolicy string `json:"concurrencyPolicy,omitempty"`
+	// StartingDeadlineSeconds is how late a missed backup may still start.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+	// SuccessfulJobsHistoryLimit is a number of succeeded Jobs kept by CronJob.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SuccessfulJobsHistoryLimit *int32 `json:"successfulJobsHistoryLimit,omitempty"`
+	// FailedJobsHistoryLimit is a number of failed Jobs kept by CronJob.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailedJobsHistoryLimit *int32 `json:"failedJobsHistoryLimit,omitempty"`
+}
+
+// PodTemplateOverride is merged into the pod template of Jobs created by adapters.
+// Lists are merged by names of their items, other fields replace values set by adapters.
+type PodTemplateOverride struct {
+	// Labels added to pods.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations added to pods.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// SecurityContext of pods.
+	// +optional
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+	// Volumes added to pods. Mount them with VolumeMounts.
+	// +optional
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+	// Resources of the backuper or restorer container.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// ContainerSecurityContext is SecurityContext of the backuper or restorer container.
+	// +optional
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+	// VolumeMounts of the backuper or restorer container.
+	// +optional
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+}
+
+// HTTPHook is an HTTP request made by BackupHook. Status codes other than 2xx fail the hook.
+type HTTPHook struct {
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+	// +kubebuilder:default=POST
+	// +optional
+	Method string `json:"method,omitempty"`
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// +optional
+	Body string `json:"body,omitempty"`
+}
+
+// BackupHook runs a query, a command or an HTTP request around a backup. Exactly one of them must be set.
+type BackupHook struct {
+	// Name identifies the hook in the run report.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+	// Query is an SQL statement, e.g. CHECKPOINT. Queries of all hooks of a backup run in one
+	// session, so a lock taken by a pre hook is held until a post hook releases it.
+	// Not supported for MongoDB, the webhook rejects query hooks of its BackupRequests.
+	// +optional
+	Query string `json:"query,omitempty"`
+	// Command runs in the backuper container without a shell.
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// +optional
+	HTTP *HTTPHook `json:"http,omitempty"`
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// OnError tells whether a failed hook fails the backup.
+	// +kubebuilder:validation:Enum=Fail;Continue
+	// +kubebuilder:default=Fail
+	// +optional
+	OnError string `json:"onError,omitempty"`
+}
+
+// BackupHooks run by backuper around the dump. Post hooks run even if pre hooks or the dump failed.
+// Results of all hooks are reported in the termination message of backuper, so there are at most 5 of each.
+type BackupHooks struct {
+	// Pre hooks run one by one before the dump. The first failed one fails the backup.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=5
+	// +optional
+	Pre []BackupHook `json:"pre,omitempty"`
+	// Post hooks run one by one after the dump, before the upload.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=5
+	// +optional
+	Post []BackupHook `json:"post,omitempty"`
+}
+
+// HookResult is an outcome of BackupHook.
+type HookResult struct {
+	Name string `json:"name"`
+	// +kubebuilder:validation:Enum=pre;post
+	Phase     string `json:"phase"`
+	Succeeded bool   `json:"succeeded"`
+	// Output is the tail of the output of the hook.
+	// +optional
+	Output string `json:"output,omitempty"`
+	// +optional
+	Duration string `json:"duration,omitempty"`
+	// Message explains why the hook failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Notification events.
+const (
+	NotificationBackupFailed     = "BackupFailed"
+	NotificationBackupSucceeded  = "BackupSucceeded"
+	NotificationBackupStale      = "BackupStale"
+	NotificationRestoreCompleted = "RestoreCompleted"
+)
+
+// WebhookSink posts notifications to an HTTP endpoint. Status codes other than 2xx are retried.
+type WebhookSink struct {
+	// URL to post notifications to.
+	// The operator connects to it from inside the cluster, so loopback, link-local, private
+	// and other addresses internal to the cluster are refused unless the operator allows
+	// their networks by NOTIFICATION_ALLOWED_NETWORKS.
+	// +optional
+	URL string `json:"url,omitempty"`
+	// URLSecretRef references a Secret key holding the URL, e.g. if it contains a token.
+	// Takes precedence over URL.
+	// +optional
+	URLSecretRef *SecretKeyReference `json:"urlSecretRef,omitempty"`
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// BodyTemplate is a Go template of the JSON body. Fields of the notification are .Event,
+	// .Namespace, .BackupRequest, .Object, .Message and .Time, and the json function quotes
+	// a value, e.g. {"text": {{ json .Message }}}. Defaults to the notification as JSON.
+	// +optional
+	BodyTemplate string `json:"bodyTemplate,omitempty"`
+}
+
+// SlackSink posts notifications to a Slack incoming webhook or a compatible one, e.g. of Mattermost.
+type SlackSink struct {
+	// URLSecretRef references a Secret key holding the URL of the incoming webhook.
+	// The operator connects to it from inside the cluster, so loopback, link-local, private
+	// and other addresses internal to the cluster are refused unless the operator allows
+	// their networks by NOTIFICATION_ALLOWED_NETWORKS.
+	URLSecretRef SecretKeyReference `json:"urlSecretRef"`
+	// Channel overrides the channel of the webhook.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+}
+
+// EmailSink sends notifications by SMTP. STARTTLS is used if the server supports it.
+type EmailSink struct {
+	// SMTPAddress is host:port of the SMTP server.
+	// The operator connects to it from inside the cluster, so loopback, link-local, private
+	// and other addresses internal to the cluster are refused unless the operator allows
+	// their networks by NOTIFICATION_ALLOWED_NETWORKS.
+	// +kubebuilder:validation:MinLength=1
+	SMTPAddress string `json:"smtpAddress"`
+	// +kubebuilder:validation:MinLength=1
+	From string `json:"from"`
+	// +kubebuilder:validation:MinItems=1
+	To []string `json:"to"`
+	// Username for PLAIN authentication. Mail is sent without authentication if it is empty.
+	// +optional
+	Username string `json:"username,omitempty"`
+	// PasswordSecretRef references a Secret key holding the password of Username.
+	// +optional
+	PasswordSecretRef *SecretKeyReference `json:"passwordSecretRef,omitempty"`
+}
+
+// NotificationSink receives notifications. Exactly one of Webhook, Slack and Email must be set.
+type NotificationSink struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +optional
+	Webhook *WebhookSink `json:"webhook,omitempty"`
+	// +optional
+	Slack *SlackSink `json:"slack,omitempty"`
+	// +optional
+	Email *EmailSink `json:"email,omitempty"`
+}
+
+// Notifications tell sinks about failed and stale backups and completed restores.
+type Notifications struct {
+	// Events to send. Defaults to BackupFailed, BackupStale and RestoreCompleted.
+	// +kubebuilder:validation:items:Enum=BackupFailed;BackupSucceeded;BackupStale;RestoreCompleted
+	// +optional
+	Events []string `json:"events,omitempty"`
+	// StaleAfter is how long after the last successful backup, or after creation of
+	// BackupRequest if there is none, BackupStale is sent. Not sent if StaleAfter is not set.
+	// +optional
+	StaleAfter *metav1.Duration `json:"staleAfter,omitempty"`
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MinItems=1
+	Sinks []NotificationSink `json:"sinks"`
+}
+
+// NotificationRecord is the last notification sent about an event.
+type NotificationRecord struct {
+	Event string `json:"event"`
+	// Key identifies what the notification was about, e.g. a Job, so it is sent only once.
+	Key string `json:"key"`
+	// +optional
+	SentTime *metav1.Time `json:"sentTime,omitempty"`
+	// Message tells which sinks failed to receive the notification after retries.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // BackupRequestSpec defines the desired state of BackupRequest.
@@ -44,8 +350,45 @@ type BackupRequestSpec struct {
 	DbSpec DatabaseSpec `json:"dbSpec"`
 	S3Spec S3Spec       `json:"s3Spec"`
 
-	Schedule       string `json:"schedule"`
-	MaxBackupCount int64  `json:"maxBackupCount"`
+	// Schedule in cron format. Defaults to the operator-level schedule.
+	// A field may be H, H(a-b), H/n or H(a-b)/n: adapter replaces it with a value
+	// picked by hash of the database, so backups of many databases do not start at once.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+	// TimeZone of Schedule, e.g. "Europe/Moscow". Defaults to the time zone of kube-controller-manager.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// MaxBackupCount is a number of backups of this BackupRequest kept in S3.
+	// Defaults to the operator-level count.
+	// +optional
+	MaxBackupCount int64 `json:"maxBackupCount,omitempty"`
+	// Retention keeps older backups by hours, days, weeks, months and years
+	// and limits their age. Backups are stored under <namespace>/<name>/<database>/,
+	// and only backups of this BackupRequest are rotated. Backups stored under <database>/
+	// by older versions are never rotated.
+	// +optional
+	Retention *RetentionPolicy `json:"retention,omitempty"`
+	// Suspend stops scheduling new backups. Running and manual backups are not affected.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// DeletionPolicy tells what happens to backups in S3 when BackupRequest is deleted.
+	// Retain keeps them, Delete purges them before BackupRequest is gone.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default=Retain
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
+	JobControls     `json:",inline"`
+	CronJobControls `json:",inline"`
+	// PodTemplate overrides pods of backups, e.g. to place them on specific nodes.
+	// +optional
+	PodTemplate *PodTemplateOverride `json:"podTemplate,omitempty"`
+	// Hooks run before and after each backup, e.g. to make it application-consistent.
+	// +optional
+	Hooks *BackupHooks `json:"hooks,omitempty"`
+	// Notifications about failed and stale backups and completed restores of them.
+	// +optional
+	Notifications *Notifications `json:"notifications,omitempty"`
 }
 
 type CreatedCronJobData struct {
@@ -53,16 +396,132 @@ type CreatedCronJobData struct {
 	Namespace string `json:"namespace,required"` //nolint:staticcheck
 }
 
+// Deletion policies of BackupRequest.
+const (
+	DeletionPolicyRetain = "Retain"
+	DeletionPolicyDelete = "Delete"
+)
+
+// RunNowAnnotation requests an immediate backup. Any new value of the annotation,
+// e.g. current time, starts a one-off Job from the template of the CronJob.
+const RunNowAnnotation = "backup.oiler.backup/run-now"
+
+// BackupRun describes a finished run of the backup CronJob.
+type BackupRun struct {
+	// JobName is a name of the Job of the run.
+	JobName string `json:"jobName"`
+	// Succeeded tells whether the backuper exited with 0.
+	Succeeded bool `json:"succeeded"`
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Duration of the run.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// Size of the uploaded backup in bytes.
+	// +optional
+	Size int64 `json:"size,omitempty"`
+	// Key is an S3 object key of the uploaded backup.
+	// +optional
+	Key string `json:"key,omitempty"`
+	// Message explains why the run failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Hooks are results of hooks run by the backuper.
+	// +optional
+	Hooks []HookResult `json:"hooks,omitempty"`
+}
+
+// Phases of ManualRun. They are the phases of its Job, as of BackupRestore.
+const (
+	RunPhasePending   = "Pending"
+	RunPhaseRunning   = "Running"
+	RunPhaseSucceeded = "Succeeded"
+	RunPhaseFailed    = "Failed"
+)
+
+// ManualRun describes the last backup requested by RunNowAnnotation.
+type ManualRun struct {
+	// RequestedAt is the value of RunNowAnnotation which requested the run.
+	RequestedAt string `json:"requestedAt"`
+	// JobName is a name of the one-off Job of the run.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+	// Phase of the Job.
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Message explains why the run failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // BackupRequestStatus defines the observed state of BackupRequest.
 type BackupRequestStatus struct {
-	Status         string             `json:"status,omitempty"`
-	LastBackupTime *metav1.Time       `json:"lastBackupTime,omitempty"`
-	CronJobData    CreatedCronJobData `json:"cronJobData,omitempty"`
+	// Status is a short summary of the state, kept for compatibility.
+	// Use Conditions to find out the reason of failure.
+	Status string `json:"status,omitempty"`
+	// LastBackupTime is when the last successful backup finished.
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
+	// LastFailedBackupTime is when the last failed backup finished.
+	// +optional
+	LastFailedBackupTime *metav1.Time       `json:"lastFailedBackupTime,omitempty"`
+	CronJobData          CreatedCronJobData `json:"cronJobData,omitempty"`
+
+	// History of recent finished runs of the CronJob, newest first.
+	// +optional
+	History []BackupRun `json:"history,omitempty"`
+	// ManualRun is the last backup requested by RunNowAnnotation.
+	// +optional
+	ManualRun *ManualRun `json:"manualRun,omitempty"`
+	// Suspended tells whether scheduling of the CronJob is suspended.
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+	// SuspendedSince is when the CronJob was suspended.
+	// +optional
+	SuspendedSince *metav1.Time `json:"suspendedSince,omitempty"`
+	// PurgeJobName is a name of the Job purging backups after BackupRequest with
+	// DeletionPolicy Delete was deleted.
+	// +optional
+	PurgeJobName string `json:"purgeJobName,omitempty"`
+	// Notifications are the last notifications sent about each event.
+	// +optional
+	// +listType=map
+	// +listMapKey=event
+	Notifications []NotificationRecord `json:"notifications,omitempty"`
+
+	// ObservedGeneration is the generation of BackupRequest last processed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// AppliedGeneration is the generation of BackupRequest the CronJob was last created or updated from.
+	// It lags behind ObservedGeneration while the CronJob cannot be updated.
+	// +optional
+	AppliedGeneration int64 `json:"appliedGeneration,omitempty"`
+
+	// Conditions are Ready, AdapterReachable, ScheduleApplied and LastBackupSucceeded.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:resource:scope=Cluster,shortName=br
+// +kubebuilder:resource:scope=Namespaced,shortName=br
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Suspended",type=boolean,JSONPath=`.status.suspended`
+// +kubebuilder:printcolumn:name="Last Backup",type=date,JSONPath=`.status.lastBackupTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 // BackupRequest is the Schema for the backuprequests API.
 type BackupRequest struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -73,7 +532,7 @@ type BackupRequest struct {
 }
 
 // +kubebuilder:object:root=true
-// +kubebuilder:resource:scope=Cluster,shortName=brs
+// +kubebuilder:resource:scope=Namespaced,shortName=brs
 // BackupRequestList contains a list of BackupRequest.
 type BackupRequestList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -25,33 +25,138 @@ import (
 
 // BackupRestoreSpec defines the desired state of BackupRestore.
 type BackupRestoreSpec struct {
-	DatabaseURI  string `json:"dbUri"`
-	DatabasePort int    `json:"databasePort"`
+	DatabaseURI string `json:"dbUri"`
+	// DatabasePort defaults to the well-known port of DatabaseType.
+	// +optional
+	DatabasePort int    `json:"databasePort,omitempty"`
 	DatabaseUser string `json:"databaseUser"`
-	DatabasePass string `json:"databasePass"`
-	DatabaseName string `json:"databaseName"`
-	DatabaseType string `json:"databaseType"`
-
-	S3Endpoint     string `json:"s3Endpoint"`
-	S3AccessKey    string `json:"s3AccessKey"`
-	S3SecretKey    string `json:"s3SecretKey"`
-	S3BucketName   string `json:"s3BucketName"`
-	BackupRevision string `json:"backupRevision"` // переделать на int
+	// DatabasePass is a plaintext password. Prefer DatabasePasswordSecretRef.
+	// +optional
+	DatabasePass string `json:"databasePass,omitempty"`
+	// DatabasePasswordSecretRef references a Secret key holding the password.
+	// Takes precedence over DatabasePass.
+	// +optional
+	DatabasePasswordSecretRef *SecretKeyReference `json:"databasePasswordSecretRef,omitempty"`
+	DatabaseName              string              `json:"databaseName"`
+	DatabaseType              string              `json:"databaseType"`
+
+	// StorageLocationName is a name of BackupStorageLocation to take
+	// endpoint, bucket and credentials from. S3 credentials below override it,
+	// S3Endpoint and S3BucketName may not be set with it.
+	// +optional
+	StorageLocationName string `json:"storageLocationName,omitempty"`
+	// +optional
+	S3Endpoint string `json:"s3Endpoint,omitempty"`
+	// +optional
+	S3AccessKey string `json:"s3AccessKey,omitempty"`
+	// +optional
+	S3SecretKey string `json:"s3SecretKey,omitempty"`
+	// S3CredentialsSecretRef references a Secret holding S3 keys.
+	// Takes precedence over S3AccessKey and S3SecretKey.
+	// +optional
+	S3CredentialsSecretRef *S3CredentialsSecretRef `json:"s3CredentialsSecretRef,omitempty"`
+	// +optional
+	S3BucketName string `json:"s3BucketName,omitempty"`
+	// BackupRevision selects a backup to restore among backups of the database:
+	// "latest", "newest:<N>" for N-th newest backup, "before:<RFC3339 time>" for the newest
+	// backup uploaded at or before the time, or "backupRequest:<name>" for the newest backup
+	// of BackupRequest in the namespace, taken from its storage unless storage is set here.
+	// A non-negative number is an index among backups of the database ordered by key, anything
+	// else is an object key, which must be under "<namespace>/" of the BackupRestore or, for
+	// backups made before BackupRequest prefixes, directly under "<database>/".
+	BackupRevision string `json:"backupRevision"`
+	// Source selects backups made of another database. Database fields above
+	// describe the target database, which is created if it does not exist.
+	// +optional
+	Source *RestoreSource `json:"source,omitempty"`
+
+	JobControls `json:",inline"`
+	// PodTemplate overrides the pod of the restore.
+	// +optional
+	PodTemplate *PodTemplateOverride `json:"podTemplate,omitempty"`
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 }
 
+// RestoreSource selects backups to restore when they were made of another database. Exactly one field must be set.
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type RestoreSource struct {
+	// BackupRequestName restores backups made by BackupRequest in namespace of BackupRestore.
+	// Storage of BackupRequest is used if BackupRestore sets none.
+	// +optional
+	BackupRequestName string `json:"backupRequestName,omitempty"`
+	// DatabaseName restores backups of the database from storage of BackupRestore.
+	// +optional
+	DatabaseName string `json:"databaseName,omitempty"`
+}
+
+// Selectors of BackupRestoreSpec.BackupRevision.
+const (
+	RevisionLatest              = "latest"
+	RevisionNewestPrefix        = "newest:"
+	RevisionBeforePrefix        = "before:"
+	RevisionBackupRequestPrefix = "backupRequest:"
+)
+
+// Phases of BackupRestore.
+const (
+	RestorePhasePending   = "Pending"
+	RestorePhaseRunning   = "Running"
+	RestorePhaseSucceeded = "Succeeded"
+	RestorePhaseFailed    = "Failed"
+)
+
 // BackupRestoreStatus defines the observed state of BackupRestore.
 type BackupRestoreStatus struct {
-	Status          string       `json:"status,omitempty"`
+	// Status is a short summary of the state, kept for compatibility.
+	// Use Conditions to find out the reason of failure.
+	Status string `json:"status,omitempty"`
+	// LastRestoreTime is when the restore Job succeeded.
 	LastRestoreTime *metav1.Time `json:"lastRestoreTime,omitempty"`
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+
+	// Phase of the restore Job.
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// JobName is a name of the restore Job in namespace of BackupRestore.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+	// StartTime is when the restore Job was started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is when the restore Job succeeded or failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Message explains why pods of the restore Job failed or do not start.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// NotifiedTime is when RestoreCompleted was sent to sinks of the BackupRequest
+	// backups of which are restored.
+	// +optional
+	NotifiedTime *metav1.Time `json:"notifiedTime,omitempty"`
+
+	// ObservedGeneration is the generation of BackupRestore last processed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions are Ready and AdapterReachable.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:storageversion
 
 // BackupRestore is the Schema for the backuprestores API.
 type BackupRestore struct {
@@ -63,7 +168,7 @@ type BackupRestore struct {
 }
 
 // +kubebuilder:object:root=true
-// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:resource:scope=Namespaced
 // BackupRestoreList contains a list of BackupRestore.
 type BackupRestoreList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// S3CredentialsSecretRef references a Secret holding S3 access and secret keys.
+type S3CredentialsSecretRef struct {
+	Name string `json:"name"`
+	// Namespace of the Secret. Defaults to the namespace of the referring resource,
+	// which is the operator namespace for BackupStorageLocation.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// +kubebuilder:default=accessKey
+	// +optional
+	AccessKeyKey string `json:"accessKeyKey,omitempty"`
+	// +kubebuilder:default=secretKey
+	// +optional
+	SecretKeyKey string `json:"secretKeyKey,omitempty"`
+}
+
+// BackupStorageLocationSpec defines the desired state of BackupStorageLocation.
+type BackupStorageLocationSpec struct {
+	Endpoint             string                 `json:"endpoint"`
+	BucketName           string                 `json:"bucketName"`
+	CredentialsSecretRef S3CredentialsSecretRef `json:"credentialsSecretRef"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=bsl
+// +kubebuilder:printcolumn:name="Endpoint",type=string,JSONPath=`.spec.endpoint`
+// +kubebuilder:printcolumn:name="Bucket",type=string,JSONPath=`.spec.bucketName`
+// BackupStorageLocation is an S3-compatible storage shared by several
+// BackupRequests and BackupRestores. Its keys are copied into Secrets in namespaces
+// of the resources referring to it, so whoever reads Secrets there can read the keys.
+type BackupStorageLocation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BackupStorageLocationSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// BackupStorageLocationList contains a list of BackupStorageLocation.
+type BackupStorageLocationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BackupStorageLocation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BackupStorageLocation{}, &BackupStorageLocationList{})
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VerificationCheck is a query run against the restored database.
+type VerificationCheck struct {
+	// Name identifies the check in status and metrics.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+	// Query is an SQL query for PostgreSQL and MySQL. For MongoDB it is a JSON object
+	// with a collection and an optional filter, e.g. {"collection": "orders", "filter": {"paid": true}}.
+	// +kubebuilder:validation:MinLength=1
+	Query string `json:"query"`
+	// MinRows is the least number of rows (documents) the query must return.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinRows *int64 `json:"minRows,omitempty"`
+}
+
+// BackupVerificationSpec defines the desired state of BackupVerification.
+type BackupVerificationSpec struct {
+	// BackupRequestName is a name of BackupRequest in the same namespace whose backups are verified.
+	// Type of the database, its name and the storage are taken from it.
+	// +kubebuilder:validation:MinLength=1
+	BackupRequestName string `json:"backupRequestName"`
+	// Schedule of verifications in the format of BackupRequestSpec.Schedule, hashed values included.
+	// Hashed values are picked by namespace and name of the BackupVerification.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+	// BackupRevision selects a backup to verify, see BackupRestoreSpec.BackupRevision.
+	// +kubebuilder:default=latest
+	// +optional
+	BackupRevision string `json:"backupRevision,omitempty"`
+	// Checks run against the restored database. A verification without checks
+	// only tells whether the backup can be restored. Results of all checks must fit
+	// the termination message of the restorer, so there may be at most 10 of them.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	Checks []VerificationCheck `json:"checks,omitempty"`
+	// DatabaseImage overrides the image of the throwaway database, e.g. to match the version of the source one.
+	// +optional
+	DatabaseImage string `json:"databaseImage,omitempty"`
+	// ActiveDeadlineSeconds limits how long a verification may run before it is failed.
+	// +kubebuilder:default=7200
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+	// Suspend stops starting new verifications. A running one is not stopped.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// CheckResult is an outcome of VerificationCheck.
+type CheckResult struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	// Rows is the number of rows (documents) returned by the query.
+	Rows int64 `json:"rows"`
+	// Duration of the query.
+	// +optional
+	Duration string `json:"duration,omitempty"`
+	// Message explains why the check failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// VerificationRun describes a finished verification.
+type VerificationRun struct {
+	// JobName is a name of the Job of the run.
+	JobName string `json:"jobName"`
+	// Passed tells whether the backup was restored and all checks passed.
+	Passed bool `json:"passed"`
+	// Key is an S3 object key of the restored backup.
+	// +optional
+	Key string `json:"key,omitempty"`
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Duration of the run.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// +optional
+	Checks []CheckResult `json:"checks,omitempty"`
+	// Message explains why the run failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// BackupVerificationStatus defines the observed state of BackupVerification.
+type BackupVerificationStatus struct {
+	// JobName is a name of the running verification Job.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+	// LastScheduleTime is when the last verification was started.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// NextScheduleTime is when the next verification is due.
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+	// LastRun is the last finished verification.
+	// +optional
+	LastRun *VerificationRun `json:"lastRun,omitempty"`
+	// LastPassedTime is when the last passed verification finished.
+	// +optional
+	LastPassedTime *metav1.Time `json:"lastPassedTime,omitempty"`
+
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,shortName=bv
+// +kubebuilder:printcolumn:name="BackupRequest",type=string,JSONPath=`.spec.backupRequestName`
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Passed",type=boolean,JSONPath=`.status.lastRun.passed`
+// +kubebuilder:printcolumn:name="Last Run",type=date,JSONPath=`.status.lastRun.completionTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// BackupVerification periodically restores a backup of BackupRequest into a throwaway
+// database and runs checks against it.
+type BackupVerification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BackupVerificationSpec   `json:"spec,omitempty"`
+	Status BackupVerificationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// BackupVerificationList contains a list of BackupVerification.
+type BackupVerificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BackupVerification `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BackupVerification{}, &BackupVerificationList{})
+}
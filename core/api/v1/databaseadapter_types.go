@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Capabilities of a database adapter.
+const (
+	// AdapterCapabilityBackup is creating and updating backup CronJobs.
+	AdapterCapabilityBackup = "Backup"
+	// AdapterCapabilityRestore is creating restore Jobs, used by BackupRestore and BackupVerification.
+	AdapterCapabilityRestore = "Restore"
+	// AdapterCapabilityPurge is deleting backups of BackupRequest with the Delete deletion policy.
+	AdapterCapabilityPurge = "Purge"
+	// AdapterCapabilityRunNow is starting a manual backup.
+	AdapterCapabilityRunNow = "RunNow"
+)
+
+// TLSSecretReference references a kubernetes.io/tls Secret with tls.crt and tls.key.
+type TLSSecretReference struct {
+	Name string `json:"name"`
+	// Namespace of the Secret. Defaults to the operator namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// AdapterTLS configures TLS of connections to the adapter.
+type AdapterTLS struct {
+	// CASecretRef references PEM-encoded CA certificates verifying the adapter.
+	// System roots are used if it is not set. Namespace defaults to the operator namespace.
+	// +optional
+	CASecretRef *SecretKeyReference `json:"caSecretRef,omitempty"`
+	// CertificateSecretRef references a client certificate the operator presents to the adapter.
+	// +optional
+	CertificateSecretRef *TLSSecretReference `json:"certificateSecretRef,omitempty"`
+	// ServerName overrides the host name the adapter certificate is verified against.
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+	// InsecureSkipVerify disables verification of the adapter certificate.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// DatabaseAdapterSpec defines the desired state of DatabaseAdapter.
+type DatabaseAdapterSpec struct {
+	// Address is host:port of the gRPC server of the adapter.
+	// +kubebuilder:validation:MinLength=1
+	Address string `json:"address"`
+	// TLS enables TLS for connections to the adapter. They are plaintext if it is not set.
+	// +optional
+	TLS *AdapterTLS `json:"tls,omitempty"`
+	// SupportedVersions are versions of the database the adapter can back up and restore.
+	// +listType=set
+	// +optional
+	SupportedVersions []string `json:"supportedVersions,omitempty"`
+	// Capabilities are operations the adapter supports. All are assumed if empty.
+	// Resources needing other operations are rejected.
+	// +listType=set
+	// +kubebuilder:validation:items:Enum=Backup;Restore;Purge;RunNow
+	// +optional
+	Capabilities []string `json:"capabilities,omitempty"`
+	// ProbeInterval is how often the operator checks that the adapter is reachable.
+	// +kubebuilder:default="30s"
+	// +optional
+	ProbeInterval *metav1.Duration `json:"probeInterval,omitempty"`
+}
+
+// DatabaseAdapterStatus defines the observed state of DatabaseAdapter.
+type DatabaseAdapterStatus struct {
+	// LastProbeTime is when the adapter was probed last.
+	// +optional
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
+
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=dba
+// +kubebuilder:printcolumn:name="Address",type=string,JSONPath=`.spec.address`
+// +kubebuilder:printcolumn:name="Versions",type=string,JSONPath=`.spec.supportedVersions`
+// +kubebuilder:printcolumn:name="Reachable",type=string,JSONPath=`.status.conditions[?(@.type=="Reachable")].status`
+// +kubebuilder:printcolumn:name="Last Probe",type=date,JSONPath=`.status.lastProbeTime`
+// DatabaseAdapter is a database adapter serving the database type it is named after,
+// e.g. DatabaseAdapter postgres serves BackupRequests with dbType postgres.
+type DatabaseAdapter struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatabaseAdapterSpec   `json:"spec,omitempty"`
+	Status DatabaseAdapterStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// DatabaseAdapterList contains a list of DatabaseAdapter.
+type DatabaseAdapterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseAdapter `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatabaseAdapter{}, &DatabaseAdapterList{})
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+// ConvertTo converts this BackupRequest (v2) to the Hub version (v1).
+func (src *BackupRequest) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*backupv1.BackupRequest)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = backupv1.BackupRequestSpec{
+		DbSpec:          convertDatabaseSpecTo(src.Spec.DbSpec),
+		S3Spec:          convertS3SpecTo(src.Spec.S3Spec),
+		Schedule:        src.Spec.Schedule,
+		TimeZone:        src.Spec.TimeZone,
+		MaxBackupCount:  src.Spec.MaxBackupCount,
+		Retention:       (*backupv1.RetentionPolicy)(src.Spec.Retention),
+		Suspend:         src.Spec.Suspend,
+		DeletionPolicy:  src.Spec.DeletionPolicy,
+		JobControls:     backupv1.JobControls(src.Spec.JobControls),
+		CronJobControls: backupv1.CronJobControls(src.Spec.CronJobControls),
+		PodTemplate:     (*backupv1.PodTemplateOverride)(src.Spec.PodTemplate),
+		Hooks:           convertHooksTo(src.Spec.Hooks),
+		Notifications:   convertNotificationsTo(src.Spec.Notifications),
+	}
+	dst.Status = backupv1.BackupRequestStatus{
+		Status:               src.Status.Status,
+		LastBackupTime:       src.Status.LastBackupTime,
+		LastFailedBackupTime: src.Status.LastFailedBackupTime,
+		CronJobData:          backupv1.CreatedCronJobData(src.Status.CronJobData),
+		History:              convertHistoryTo(src.Status.History),
+		ManualRun:            (*backupv1.ManualRun)(src.Status.ManualRun),
+		Suspended:            src.Status.Suspended,
+		SuspendedSince:       src.Status.SuspendedSince,
+		PurgeJobName:         src.Status.PurgeJobName,
+		Notifications:        convertRecordsTo(src.Status.Notifications),
+		ObservedGeneration:   src.Status.ObservedGeneration,
+		AppliedGeneration:    src.Status.AppliedGeneration,
+		Conditions:           src.Status.Conditions,
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1) to this version (v2).
+func (dst *BackupRequest) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*backupv1.BackupRequest)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = BackupRequestSpec{
+		DbSpec:          convertDatabaseSpecFrom(src.Spec.DbSpec),
+		S3Spec:          convertS3SpecFrom(src.Spec.S3Spec),
+		Schedule:        src.Spec.Schedule,
+		TimeZone:        src.Spec.TimeZone,
+		MaxBackupCount:  src.Spec.MaxBackupCount,
+		Retention:       (*RetentionPolicy)(src.Spec.Retention),
+		Suspend:         src.Spec.Suspend,
+		DeletionPolicy:  src.Spec.DeletionPolicy,
+		JobControls:     JobControls(src.Spec.JobControls),
+		CronJobControls: CronJobControls(src.Spec.CronJobControls),
+		PodTemplate:     (*PodTemplateOverride)(src.Spec.PodTemplate),
+		Hooks:           convertHooksFrom(src.Spec.Hooks),
+		Notifications:   convertNotificationsFrom(src.Spec.Notifications),
+	}
+	dst.Status = BackupRequestStatus{
+		Status:               src.Status.Status,
+		LastBackupTime:       src.Status.LastBackupTime,
+		LastFailedBackupTime: src.Status.LastFailedBackupTime,
+		CronJobData:          CreatedCronJobData(src.Status.CronJobData),
+		History:              convertHistoryFrom(src.Status.History),
+		ManualRun:            (*ManualRun)(src.Status.ManualRun),
+		Suspended:            src.Status.Suspended,
+		SuspendedSince:       src.Status.SuspendedSince,
+		PurgeJobName:         src.Status.PurgeJobName,
+		Notifications:        convertRecordsFrom(src.Status.Notifications),
+		ObservedGeneration:   src.Status.ObservedGeneration,
+		AppliedGeneration:    src.Status.AppliedGeneration,
+		Conditions:           src.Status.Conditions,
+	}
+
+	return nil
+}
+
+func convertDatabaseSpecTo(src DatabaseSpec) backupv1.DatabaseSpec {
+	return backupv1.DatabaseSpec{
+		URI:               src.URI,
+		Port:              src.Port,
+		User:              src.User,
+		Pass:              src.Pass,
+		PasswordSecretRef: (*backupv1.SecretKeyReference)(src.PasswordSecretRef),
+		DbName:            src.DbName,
+		DbType:            src.DbType,
+	}
+}
+
+func convertDatabaseSpecFrom(src backupv1.DatabaseSpec) DatabaseSpec {
+	return DatabaseSpec{
+		URI:               src.URI,
+		Port:              src.Port,
+		User:              src.User,
+		Pass:              src.Pass,
+		PasswordSecretRef: (*SecretKeyReference)(src.PasswordSecretRef),
+		DbName:            src.DbName,
+		DbType:            src.DbType,
+	}
+}
+
+func convertS3SpecTo(src S3Spec) backupv1.S3Spec {
+	return backupv1.S3Spec{
+		StorageLocationName:  src.StorageLocationName,
+		Endpoint:             src.Endpoint,
+		Auth:                 backupv1.S3Auth(src.Auth),
+		CredentialsSecretRef: (*backupv1.S3CredentialsSecretRef)(src.CredentialsSecretRef),
+		BucketName:           src.BucketName,
+	}
+}
+
+func convertS3SpecFrom(src backupv1.S3Spec) S3Spec {
+	return S3Spec{
+		StorageLocationName:  src.StorageLocationName,
+		Endpoint:             src.Endpoint,
+		Auth:                 S3Auth(src.Auth),
+		CredentialsSecretRef: (*S3CredentialsSecretRef)(src.CredentialsSecretRef),
+		BucketName:           src.BucketName,
+	}
+}
+
+func convertHistoryTo(history []BackupRun) []backupv1.BackupRun {
+	if history == nil {
+		return nil
+	}
+	runs := make([]backupv1.BackupRun, 0, len(history))
+	for _, run := range history {
+		var hooks []backupv1.HookResult
+		for _, hook := range run.Hooks {
+			hooks = append(hooks, backupv1.HookResult(hook))
+		}
+		runs = append(runs, backupv1.BackupRun{
+			JobName:        run.JobName,
+			Succeeded:      run.Succeeded,
+			StartTime:      run.StartTime,
+			CompletionTime: run.CompletionTime,
+			Duration:       run.Duration,
+			Size:           run.Size,
+			Key:            run.Key,
+			Message:        run.Message,
+			Hooks:          hooks,
+		})
+	}
+	return runs
+}
+
+func convertHistoryFrom(history []backupv1.BackupRun) []BackupRun {
+	if history == nil {
+		return nil
+	}
+	runs := make([]BackupRun, 0, len(history))
+	for _, run := range history {
+		var hooks []HookResult
+		for _, hook := range run.Hooks {
+			hooks = append(hooks, HookResult(hook))
+		}
+		runs = append(runs, BackupRun{
+			JobName:        run.JobName,
+			Succeeded:      run.Succeeded,
+			StartTime:      run.StartTime,
+			CompletionTime: run.CompletionTime,
+			Duration:       run.Duration,
+			Size:           run.Size,
+			Key:            run.Key,
+			Message:        run.Message,
+			Hooks:          hooks,
+		})
+	}
+	return runs
+}
+
+func convertHooksTo(src *BackupHooks) *backupv1.BackupHooks {
+	if src == nil {
+		return nil
+	}
+	dst := &backupv1.BackupHooks{}
+	for _, hook := range src.Pre {
+		dst.Pre = append(dst.Pre, convertHookTo(hook))
+	}
+	for _, hook := range src.Post {
+		dst.Post = append(dst.Post, convertHookTo(hook))
+	}
+	return dst
+}
+
+func convertHookTo(src BackupHook) backupv1.BackupHook {
+	return backupv1.BackupHook{
+		Name:           src.Name,
+		Query:          src.Query,
+		Command:        src.Command,
+		HTTP:           (*backupv1.HTTPHook)(src.HTTP),
+		TimeoutSeconds: src.TimeoutSeconds,
+		OnError:        src.OnError,
+	}
+}
+
+func convertHooksFrom(src *backupv1.BackupHooks) *BackupHooks {
+	if src == nil {
+		return nil
+	}
+	dst := &BackupHooks{}
+	for _, hook := range src.Pre {
+		dst.Pre = append(dst.Pre, convertHookFrom(hook))
+	}
+	for _, hook := range src.Post {
+		dst.Post = append(dst.Post, convertHookFrom(hook))
+	}
+	return dst
+}
+
+func convertHookFrom(src backupv1.BackupHook) BackupHook {
+	return BackupHook{
+		Name:           src.Name,
+		Query:          src.Query,
+		Command:        src.Command,
+		HTTP:           (*HTTPHook)(src.HTTP),
+		TimeoutSeconds: src.TimeoutSeconds,
+		OnError:        src.OnError,
+	}
+}
+
+func convertNotificationsTo(src *Notifications) *backupv1.Notifications {
+	if src == nil {
+		return nil
+	}
+	dst := &backupv1.Notifications{Events: src.Events, StaleAfter: src.StaleAfter}
+	for _, sink := range src.Sinks {
+		converted := backupv1.NotificationSink{Name: sink.Name}
+		if sink.Webhook != nil {
+			converted.Webhook = &backupv1.WebhookSink{
+				URL:          sink.Webhook.URL,
+				URLSecretRef: (*backupv1.SecretKeyReference)(sink.Webhook.URLSecretRef),
+				Headers:      sink.Webhook.Headers,
+				BodyTemplate: sink.Webhook.BodyTemplate,
+			}
+		}
+		if sink.Slack != nil {
+			converted.Slack = &backupv1.SlackSink{
+				URLSecretRef: backupv1.SecretKeyReference(sink.Slack.URLSecretRef),
+				Channel:      sink.Slack.Channel,
+			}
+		}
+		if sink.Email != nil {
+			converted.Email = &backupv1.EmailSink{
+				SMTPAddress:       sink.Email.SMTPAddress,
+				From:              sink.Email.From,
+				To:                sink.Email.To,
+				Username:          sink.Email.Username,
+				PasswordSecretRef: (*backupv1.SecretKeyReference)(sink.Email.PasswordSecretRef),
+			}
+		}
+		dst.Sinks = append(dst.Sinks, converted)
+	}
+	return dst
+}
+
+func convertNotificationsFrom(src *backupv1.Notifications) *Notifications {
+	if src == nil {
+		return nil
+	}
+	dst := &Notifications{Events: src.Events, StaleAfter: src.StaleAfter}
+	for _, sink := range src.Sinks {
+		converted := NotificationSink{Name: sink.Name}
+		if sink.Webhook != nil {
+			converted.Webhook = &WebhookSink{
+				URL:          sink.Webhook.URL,
+				URLSecretRef: (*SecretKeyReference)(sink.Webhook.URLSecretRef),
+				Headers:      sink.Webhook.Headers,
+				BodyTemplate: sink.Webhook.BodyTemplate,
+			}
+		}
+		if sink.Slack != nil {
+			converted.Slack = &SlackSink{
+				URLSecretRef: SecretKeyReference(sink.Slack.URLSecretRef),
+				Channel:      sink.Slack.Channel,
+			}
+		}
+		if sink.Email != nil {
+			converted.Email = &EmailSink{
+				SMTPAddress:       sink.Email.SMTPAddress,
+				From:              sink.Email.From,
+				To:                sink.Email.To,
+				Username:          sink.Email.Username,
+				PasswordSecretRef: (*SecretKeyReference)(sink.Email.PasswordSecretRef),
+			}
+		}
+		dst.Sinks = append(dst.Sinks, converted)
+	}
+	return dst
+}
+
+func convertRecordsTo(records []NotificationRecord) []backupv1.NotificationRecord {
+	var dst []backupv1.NotificationRecord
+	for _, record := range records {
+		dst = append(dst, backupv1.NotificationRecord(record))
+	}
+	return dst
+}
+
+func convertRecordsFrom(records []backupv1.NotificationRecord) []NotificationRecord {
+	var dst []NotificationRecord
+	for _, record := range records {
+		dst = append(dst, NotificationRecord(record))
+	}
+	return dst
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+// ConvertTo converts this BackupRestore (v2) to the Hub version (v1).
+// Nested specs are flattened, revision is written the way restorers parse it.
+func (src *BackupRestore) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*backupv1.BackupRestore)
+
+	db := src.Spec.DbSpec
+	s3 := src.Spec.S3Spec
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = backupv1.BackupRestoreSpec{
+		DatabaseURI:               db.URI,
+		DatabasePort:              db.Port,
+		DatabaseUser:              db.User,
+		DatabasePass:              db.Pass,
+		DatabasePasswordSecretRef: (*backupv1.SecretKeyReference)(db.PasswordSecretRef),
+		DatabaseName:              db.DbName,
+		DatabaseType:              db.DbType,
+		StorageLocationName:       s3.StorageLocationName,
+		S3Endpoint:                s3.Endpoint,
+		S3AccessKey:               s3.Auth.AccessKey,
+		S3SecretKey:               s3.Auth.SecretKey,
+		S3CredentialsSecretRef:    (*backupv1.S3CredentialsSecretRef)(s3.CredentialsSecretRef),
+		S3BucketName:              s3.BucketName,
+		BackupRevision:            src.Spec.Revision.String(),
+		JobControls:               backupv1.JobControls(src.Spec.JobControls),
+		PodTemplate:               (*backupv1.PodTemplateOverride)(src.Spec.PodTemplate),
+	}
+	if source := src.Spec.Source; source != nil {
+		dst.Spec.Source = &backupv1.RestoreSource{
+			BackupRequestName: source.BackupRequest,
+			DatabaseName:      source.DbName,
+		}
+	}
+	dst.Status = backupv1.BackupRestoreStatus(src.Status)
+
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1) to this version (v2).
+func (dst *BackupRestore) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*backupv1.BackupRestore)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = BackupRestoreSpec{
+		DbSpec: DatabaseSpec{
+			URI:               src.Spec.DatabaseURI,
+			Port:              src.Spec.DatabasePort,
+			User:              src.Spec.DatabaseUser,
+			Pass:              src.Spec.DatabasePass,
+			PasswordSecretRef: (*SecretKeyReference)(src.Spec.DatabasePasswordSecretRef),
+			DbName:            src.Spec.DatabaseName,
+			DbType:            src.Spec.DatabaseType,
+		},
+		S3Spec: S3Spec{
+			StorageLocationName: src.Spec.StorageLocationName,
+			Endpoint:            src.Spec.S3Endpoint,
+			Auth: S3Auth{
+				AccessKey: src.Spec.S3AccessKey,
+				SecretKey: src.Spec.S3SecretKey,
+			},
+			CredentialsSecretRef: (*S3CredentialsSecretRef)(src.Spec.S3CredentialsSecretRef),
+			BucketName:           src.Spec.S3BucketName,
+		},
+		Revision:    ParseRevision(src.Spec.BackupRevision),
+		JobControls: JobControls(src.Spec.JobControls),
+		PodTemplate: (*PodTemplateOverride)(src.Spec.PodTemplate),
+	}
+	if source := src.Spec.Source; source != nil {
+		dst.Spec.Source = &RestoreSource{
+			BackupRequest: source.BackupRequestName,
+			DbName:        source.DatabaseName,
+		}
+	}
+	dst.Status = BackupRestoreStatus(src.Status)
+
+	return nil
+}
+
+// ParseRevision parses v1 backupRevision. Selectors and non-negative numbers in canonical
+// form are parsed to fields, anything else is an object key, so String returns revision back.
+func ParseRevision(revision string) RevisionSelector {
+	switch {
+	case revision == backupv1.RevisionLatest:
+		return RevisionSelector{Latest: true}
+	case strings.HasPrefix(revision, backupv1.RevisionNewestPrefix):
+		if newest, ok := parseIndex(strings.TrimPrefix(revision, backupv1.RevisionNewestPrefix)); ok {
+			return RevisionSelector{Newest: &newest}
+		}
+	case strings.HasPrefix(revision, backupv1.RevisionBeforePrefix):
+		before, err := time.Parse(time.RFC3339, strings.TrimPrefix(revision, backupv1.RevisionBeforePrefix))
+		if err == nil && formatBefore(before) == revision {
+			return RevisionSelector{Before: &metav1.Time{Time: before}}
+		}
+	case strings.HasPrefix(revision, backupv1.RevisionBackupRequestPrefix):
+		if name := strings.TrimPrefix(revision, backupv1.RevisionBackupRequestPrefix); name != "" {
+			return RevisionSelector{BackupRequest: name}
+		}
+	default:
+		if index, ok := parseIndex(revision); ok {
+			return RevisionSelector{Index: &index}
+		}
+	}
+	return RevisionSelector{Key: revision}
+}
+
+// parseIndex parses a non-negative number in canonical form.
+func parseIndex(s string) (int64, bool) {
+	index, err := strconv.ParseInt(s, 10, 64)
+	return index, err == nil && index >= 0 && strconv.FormatInt(index, 10) == s
+}
+
+func formatBefore(before time.Time) string {
+	return backupv1.RevisionBeforePrefix + before.UTC().Format(time.RFC3339)
+}
+
+// String formats r as v1 backupRevision.
+func (r RevisionSelector) String() string {
+	switch {
+	case r.Latest:
+		return backupv1.RevisionLatest
+	case r.Newest != nil:
+		return backupv1.RevisionNewestPrefix + strconv.FormatInt(*r.Newest, 10)
+	case r.Before != nil:
+		return formatBefore(r.Before.Time)
+	case r.BackupRequest != "":
+		return backupv1.RevisionBackupRequestPrefix + r.BackupRequest
+	case r.Index != nil:
+		return strconv.FormatInt(*r.Index, 10)
+	}
+	return r.Key
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RevisionSelector selects a backup to restore. Exactly one field must be set.
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type RevisionSelector struct {
+	// Latest selects the newest backup of the database.
+	// +optional
+	Latest bool `json:"latest,omitempty"`
+	// Newest selects N-th newest backup of the database, 0 is the newest.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Newest *int64 `json:"newest,omitempty"`
+	// Before selects the newest backup of the database uploaded at or before the time.
+	// +optional
+	Before *metav1.Time `json:"before,omitempty"`
+	// BackupRequest selects the newest backup made by BackupRequest with the name
+	// in namespace of BackupRestore, taken from its storage unless storage is set.
+	// +optional
+	BackupRequest string `json:"backupRequest,omitempty"`
+	// Index of a backup among backups of the database ordered by key, starting from 0.
+	// Backups of other namespaces are not counted, prefer Newest.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Index *int64 `json:"index,omitempty"`
+	// Key is an S3 object key of a backup. It must be under "<namespace>/" of the
+	// BackupRestore or, for backups made before BackupRequest prefixes, directly under "<database>/".
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// RestoreSource selects backups to restore when they were made of another database. Exactly one field must be set.
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type RestoreSource struct {
+	// BackupRequest restores backups made by BackupRequest with the name in namespace of BackupRestore.
+	// Its storage is used if S3Spec is empty.
+	// +optional
+	BackupRequest string `json:"backupRequest,omitempty"`
+	// DbName restores backups of the database from S3Spec.
+	// +optional
+	DbName string `json:"dbName,omitempty"`
+}
+
+// BackupRestoreSpec defines the desired state of BackupRestore.
+type BackupRestoreSpec struct {
+	// Source selects backups made of another database than DbSpec.
+	// +optional
+	Source *RestoreSource `json:"source,omitempty"`
+	// DbSpec is the target database, it is created if it does not exist.
+	DbSpec DatabaseSpec `json:"dbSpec"`
+	// +optional
+	S3Spec   S3Spec           `json:"s3Spec"`
+	Revision RevisionSelector `json:"revision"`
+
+	JobControls `json:",inline"`
+	// PodTemplate overrides the pod of the restore.
+	// +optional
+	PodTemplate *PodTemplateOverride `json:"podTemplate,omitempty"`
+}
+
+// BackupRestoreStatus defines the observed state of BackupRestore.
+type BackupRestoreStatus struct {
+	// Status is a short summary of the state, kept for compatibility.
+	// Use Conditions to find out the reason of failure.
+	Status string `json:"status,omitempty"`
+	// LastRestoreTime is when the restore Job succeeded.
+	LastRestoreTime *metav1.Time `json:"lastRestoreTime,omitempty"`
+
+	// Phase of the restore Job.
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// JobName is a name of the restore Job in namespace of BackupRestore.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+	// StartTime is when the restore Job was started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is when the restore Job succeeded or failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Message explains why pods of the restore Job failed or do not start.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// NotifiedTime is when RestoreCompleted was sent to sinks of the BackupRequest
+	// backups of which are restored.
+	// +optional
+	NotifiedTime *metav1.Time `json:"notifiedTime,omitempty"`
+
+	// ObservedGeneration is the generation of BackupRestore last processed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions are Ready and AdapterReachable.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// BackupRestore is the Schema for the backuprestores API.
+type BackupRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BackupRestoreSpec   `json:"spec,omitempty"`
+	Status BackupRestoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// BackupRestoreList contains a list of BackupRestore.
+type BackupRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BackupRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BackupRestore{}, &BackupRestoreList{})
+}
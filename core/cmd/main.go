@@ -43,7 +43,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	backupv2 "github.com/oiler-backup/core/core/api/v2"
+	"github.com/oiler-backup/core/core/internal/config"
 	"github.com/oiler-backup/core/core/internal/controller"
+	"github.com/oiler-backup/core/core/internal/notify"
+	webhookbackupv1 "github.com/oiler-backup/core/core/internal/webhook/v1"
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	// +kubebuilder:scaffold:imports
@@ -120,11 +124,13 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(backupv1.AddToScheme(scheme))
+	utilruntime.Must(backupv2.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 
 	metrics.Registry.MustRegister(successfulBackups)
 	metrics.Registry.MustRegister(failedBackups)
 	metrics.Registry.MustRegister(backupsDuration)
+	metrics.Registry.MustRegister(controller.VerificationCollectors()...)
 
 }
 
@@ -220,20 +226,76 @@ func main() {
 		os.Exit(1)
 	}
 
+	cfg, err := config.GetConfig()
+	if err != nil {
+		setupLog.Error(err, "unable to read operator config")
+		os.Exit(1)
+	}
+	sender := notify.Sender{Dialer: notify.Dialer{AllowedNetworks: cfg.NotificationAllowedNetworks}}
+
 	if err = (&controller.BackupRequestReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("backuprequest-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "BackupRequest")
 		os.Exit(1)
 	}
 	if err = (&controller.BackupRestoreReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("backuprestore-controller"),
+		Sender:   sender,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BackupRestore")
+		os.Exit(1)
+	}
+	if err = (&controller.BackupVerificationReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "BackupRestore")
+		setupLog.Error(err, "unable to create controller", "controller", "BackupVerification")
 		os.Exit(1)
 	}
+	if err = (&controller.BackupHistoryReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+		Sender: sender,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BackupHistory")
+		os.Exit(1)
+	}
+	if err = (&controller.BackupStalenessReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+		Sender: sender,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BackupStaleness")
+		os.Exit(1)
+	}
+	if err = (&controller.DatabaseAdapterReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("databaseadapter-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DatabaseAdapter")
+		os.Exit(1)
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhookbackupv1.SetupBackupRequestWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "BackupRequest")
+			os.Exit(1)
+		}
+		if err = webhookbackupv1.SetupBackupRestoreWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "BackupRestore")
+			os.Exit(1)
+		}
+		if err = webhookbackupv1.SetupBackupVerificationWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "BackupVerification")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -1,9 +1,24 @@
 package config
 
-import "github.com/caarlos0/env/v11"
+import (
+	"net/netip"
+
+	"github.com/caarlos0/env/v11"
+)
 
 type Config struct {
 	OperatorNamespace string `env:"OPERATOR_NAMESPACE" envDefault:"oiler-backup-system"`
+
+	// Defaults are applied by the mutating webhook to fields left empty.
+	DefaultSchedule        string `env:"DEFAULT_SCHEDULE" envDefault:"0 0 * * *"`
+	DefaultMaxBackupCount  int64  `env:"DEFAULT_MAX_BACKUP_COUNT" envDefault:"7"`
+	DefaultStorageLocation string `env:"DEFAULT_STORAGE_LOCATION"`
+	DefaultS3Endpoint      string `env:"DEFAULT_S3_ENDPOINT"`
+	DefaultS3BucketName    string `env:"DEFAULT_S3_BUCKET_NAME"`
+
+	// NotificationAllowedNetworks are networks internal to the cluster which notification
+	// sinks may reach anyway, comma-separated, e.g. "10.0.5.0/24".
+	NotificationAllowedNetworks []netip.Prefix `env:"NOTIFICATION_ALLOWED_NETWORKS"`
 }
 
 func GetConfig() (Config, error) {
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// An unsupportedError tells that no adapter can serve a resource:
+// there is no adapter of its database type or the adapter lacks a capability.
+type unsupportedError struct{ error }
+
+func (e unsupportedError) Unwrap() error { return e.error }
+
+// isUnsupported reports whether err is an unsupportedError.
+func isUnsupported(err error) bool {
+	return errors.As(err, &unsupportedError{})
+}
+
+// A databaseAdapter is a gRPC server of an adapter serving a database type.
+type databaseAdapter struct {
+	dbType  string
+	address string
+	// tlsConfig is nil for plaintext connections.
+	tlsConfig *tls.Config
+	// capabilities are operations of the adapter, all of them if empty.
+	capabilities []string
+}
+
+// resolveAdapter returns the adapter of dbType: DatabaseAdapter named dbType or, if there is none,
+// the address of dbType in ConfigMap database-config in namespace. If neither has dbType,
+// the error is an unsupportedError.
+func resolveAdapter(ctx context.Context, r client.Reader, dbType, namespace string) (databaseAdapter, error) {
+	var adapter backupv1.DatabaseAdapter
+	err := r.Get(ctx, client.ObjectKey{Name: dbType}, &adapter)
+	if err == nil {
+		return newDatabaseAdapter(ctx, r, &adapter)
+	}
+	if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return databaseAdapter{}, fmt.Errorf("unable to get DatabaseAdapter %s: %w", dbType, err)
+	}
+
+	dbControllers, err := loadDatabaseConfig(ctx, r, namespace)
+	if apierrors.IsNotFound(err) {
+		dbControllers = nil
+	} else if err != nil {
+		return databaseAdapter{}, err
+	}
+	address, ok := dbControllers[dbType]
+	if !ok {
+		return databaseAdapter{}, unsupportedError{ErrNotSupported(dbType)}
+	}
+	return databaseAdapter{dbType: dbType, address: address}, nil
+}
+
+// newDatabaseAdapter resolves TLS settings of adapter.
+func newDatabaseAdapter(ctx context.Context, r client.Reader, adapter *backupv1.DatabaseAdapter) (databaseAdapter, error) {
+	tlsConfig, err := adapterTLSConfig(ctx, r, adapter.Spec.TLS)
+	if err != nil {
+		return databaseAdapter{}, fmt.Errorf("invalid TLS of DatabaseAdapter %s: %w", adapter.Name, err)
+	}
+	return databaseAdapter{
+		dbType:       adapter.Name,
+		address:      adapter.Spec.Address,
+		tlsConfig:    tlsConfig,
+		capabilities: adapter.Spec.Capabilities,
+	}, nil
+}
+
+// adapterTLSConfig builds a client TLS config of spec. Secrets without namespace
+// are read from the operator namespace. It returns nil if spec is nil.
+func adapterTLSConfig(ctx context.Context, r client.Reader, spec *backupv1.AdapterTLS) (*tls.Config, error) {
+	if spec == nil {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         spec.ServerName,
+		InsecureSkipVerify: spec.InsecureSkipVerify, //nolint:gosec // Explicitly requested by DatabaseAdapter.
+	}
+	if ref := spec.CASecretRef; ref != nil {
+		ca, err := resolveSecretKey(ctx, r, ref, appCfg.OperatorNamespace)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no PEM certificates in key %s of Secret %s", ref.Key, ref.Name)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if ref := spec.CertificateSecretRef; ref != nil {
+		secretRef := &backupv1.SecretKeyReference{Name: ref.Name, Namespace: ref.Namespace, Key: corev1.TLSCertKey}
+		cert, err := resolveSecretKey(ctx, r, secretRef, appCfg.OperatorNamespace)
+		if err != nil {
+			return nil, err
+		}
+		secretRef.Key = corev1.TLSPrivateKeyKey
+		key, err := resolveSecretKey(ctx, r, secretRef, appCfg.OperatorNamespace)
+		if err != nil {
+			return nil, err
+		}
+		certificate, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate in Secret %s: %w", ref.Name, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
+
+// dial returns a connection to the adapter. The caller must close it.
+func (a databaseAdapter) dial() (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if a.tlsConfig != nil {
+		creds = credentials.NewTLS(a.tlsConfig)
+	}
+	conn, err := grpc.NewClient(a.address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", a.address, err)
+	}
+	return conn, nil
+}
+
+// supports returns an unsupportedError if the adapter lacks capability.
+func (a databaseAdapter) supports(capability string) error {
+	if len(a.capabilities) == 0 || slices.Contains(a.capabilities, capability) {
+		return nil
+	}
+	return unsupportedError{fmt.Errorf("adapter of %s does not support %s", a.dbType, capability)}
+}
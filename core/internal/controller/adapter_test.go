@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"context"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+var _ = Describe("Database adapters", func() {
+	const namespace = "oiler-backup-system"
+	ctx := context.Background()
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(backupv1.AddToScheme(scheme)).To(Succeed())
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	})
+
+	databaseConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "database-config", Namespace: namespace},
+		Data:       map[string]string{"postgres": "postgres-from-cm:50051", "mysql": "mysql-from-cm:50051"},
+	}
+	postgresAdapter := func(capabilities ...string) *backupv1.DatabaseAdapter {
+		return &backupv1.DatabaseAdapter{
+			ObjectMeta: metav1.ObjectMeta{Name: "postgres", Generation: 1},
+			Spec:       backupv1.DatabaseAdapterSpec{Address: "postgres-adapter:50051", Capabilities: capabilities},
+		}
+	}
+
+	Context("resolveAdapter", func() {
+		It("Should prefer DatabaseAdapter to database-config", func() {
+			r := fake.NewClientBuilder().WithScheme(scheme).WithObjects(databaseConfig, postgresAdapter()).Build()
+
+			adapter, err := resolveAdapter(ctx, r, "postgres", namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(adapter.address).To(Equal("postgres-adapter:50051"))
+
+			adapter, err = resolveAdapter(ctx, r, "mysql", namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(adapter.address).To(Equal("mysql-from-cm:50051"))
+		})
+
+		It("Should report an unsupported database type without database-config", func() {
+			r := fake.NewClientBuilder().WithScheme(scheme).WithObjects(postgresAdapter()).Build()
+
+			_, err := resolveAdapter(ctx, r, "mongodb", namespace)
+			Expect(isUnsupported(err)).To(BeTrue())
+			Expect(err).To(MatchError("database mongodb is not supported"))
+		})
+
+		It("Should check capabilities of DatabaseAdapter", func() {
+			r := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(postgresAdapter(backupv1.AdapterCapabilityBackup)).Build()
+
+			adapter, err := resolveAdapter(ctx, r, "postgres", namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(adapter.supports(backupv1.AdapterCapabilityBackup)).To(Succeed())
+			err = adapter.supports(backupv1.AdapterCapabilityPurge)
+			Expect(isUnsupported(err)).To(BeTrue())
+			Expect(err).To(MatchError("adapter of postgres does not support Purge"))
+		})
+
+		It("Should assume all capabilities of database-config adapters", func() {
+			r := fake.NewClientBuilder().WithScheme(scheme).WithObjects(databaseConfig).Build()
+
+			adapter, err := resolveAdapter(ctx, r, "postgres", namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(adapter.supports(backupv1.AdapterCapabilityPurge)).To(Succeed())
+		})
+	})
+
+	Context("DatabaseAdapterReconciler", func() {
+		// serve starts a gRPC server with the health service on a local port and returns its address.
+		serve := func(healthy bool) string {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			server := grpc.NewServer()
+			healthServer := health.NewServer()
+			if !healthy {
+				healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+			}
+			healthpb.RegisterHealthServer(server, healthServer)
+			go func() { _ = server.Serve(lis) }()
+			DeferCleanup(server.Stop)
+			return lis.Addr().String()
+		}
+		probe := func(adapter *backupv1.DatabaseAdapter, objs ...client.Object) (*backupv1.DatabaseAdapter, *record.FakeRecorder, reconcile.Result) {
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(append(objs, adapter)...).WithStatusSubresource(adapter).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &DatabaseAdapterReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+
+			result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(adapter)})
+			Expect(err).NotTo(HaveOccurred())
+
+			probed := &backupv1.DatabaseAdapter{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(adapter), probed)).To(Succeed())
+			return probed, recorder, result
+		}
+
+		It("Should report a serving adapter as reachable", func() {
+			adapter := postgresAdapter()
+			adapter.Spec.Address = serve(true)
+			adapter.Spec.ProbeInterval = &metav1.Duration{Duration: time.Minute}
+
+			probed, recorder, result := probe(adapter)
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+			Expect(probed.Status.LastProbeTime).NotTo(BeNil())
+			Expect(probed.Status.ObservedGeneration).To(Equal(int64(1)))
+			Expect(meta.IsStatusConditionTrue(probed.Status.Conditions, TypeReachable)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal AdapterResponded ")))
+		})
+
+		It("Should report a not serving adapter as unreachable", func() {
+			adapter := postgresAdapter()
+			adapter.Spec.Address = serve(false)
+
+			probed, _, result := probe(adapter)
+			Expect(result.RequeueAfter).To(Equal(defaultProbeInterval))
+			condition := meta.FindStatusCondition(probed.Status.Conditions, TypeReachable)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(HaveSuffix("is NOT_SERVING"))
+		})
+
+		It("Should not repeat the event while the adapter stays reachable", func() {
+			adapter := postgresAdapter()
+			adapter.Spec.Address = serve(true)
+			adapter.Status.Conditions = []metav1.Condition{probeAdapter(ctx, databaseAdapter{address: adapter.Spec.Address})}
+			adapter.Status.Conditions[0].LastTransitionTime = metav1.Now()
+
+			_, recorder, _ := probe(adapter)
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("Should report an invalid CA", func() {
+			adapter := postgresAdapter()
+			adapter.Spec.TLS = &backupv1.AdapterTLS{
+				CASecretRef: &backupv1.SecretKeyReference{Name: "adapter-ca", Namespace: namespace, Key: "ca.crt"},
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "adapter-ca", Namespace: namespace},
+				Data:       map[string][]byte{"ca.crt": []byte("not a certificate")},
+			}
+
+			probed, recorder, _ := probe(adapter, secret)
+			condition := meta.FindStatusCondition(probed.Status.Conditions, TypeReachable)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(ReasonCredentialsUnavailable))
+			Expect(condition.Message).To(ContainSubstring("no PEM certificates in key ca.crt of Secret adapter-ca"))
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning CredentialsUnavailable ")))
+		})
+	})
+})
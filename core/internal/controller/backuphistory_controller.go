@@ -0,0 +1,332 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	"github.com/oiler-backup/core/core/internal/notify"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// backupHistoryLimit is a number of runs kept in status of BackupRequest.
+const backupHistoryLimit = 10
+
+// BackupHistoryReconciler records runs of CronJobs created for BackupRequests
+// to their status and creates a Backup for each uploaded backup.
+type BackupHistoryReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Sender sends notifications about finished runs.
+	Sender notify.Sender
+}
+
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=backuprequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=backuprequests/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=backups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=backupstoragelocations,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="batch",resources=jobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+func (r *BackupHistoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx).WithValues("job", req.NamespacedName)
+	var job batchv1.Job
+	err := r.Get(ctx, req.NamespacedName, &job)
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, "Unable to get Job object")
+		return ctrl.Result{}, err
+	}
+
+	owner := metav1.GetControllerOf(&job)
+	if owner == nil {
+		return ctrl.Result{}, nil
+	}
+	backupRequest, err := r.backupRequestForJob(ctx, job.Namespace, owner)
+	if err != nil {
+		log.Error(err, "Unable to find BackupRequest of Job")
+		return ctrl.Result{}, err
+	}
+	if backupRequest == nil {
+		return ctrl.Result{}, nil
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(job.Namespace), client.MatchingLabels{batchv1.JobNameLabel: job.Name}); err != nil {
+		log.Error(err, "Unable to list pods of Job")
+		return ctrl.Result{}, err
+	}
+
+	status := backupRequest.Status.DeepCopy()
+	if manualRun := status.ManualRun; manualRun != nil && manualRun.JobName == job.Name {
+		updateManualRun(manualRun, &job, pods.Items)
+	}
+
+	run, finished := backupRun(&job, pods.Items)
+	if finished {
+		if run.Succeeded && run.Key != "" {
+			report := readRunReport(pods.Items, backuperContainerName)
+			if err := r.ensureBackup(ctx, backupRequest, run, report); err != nil {
+				log.Error(err, "Unable to create Backup")
+				return ctrl.Result{}, err
+			}
+			if err := r.deleteExpiredBackups(ctx, backupRequest, run, report); err != nil {
+				log.Error(err, "Unable to delete Backups of deleted backups")
+				return ctrl.Result{}, err
+			}
+		}
+		// Runs already in history were notified about, e.g. before the operator restarted.
+		recorded := slices.ContainsFunc(status.History, func(recorded backupv1.BackupRun) bool {
+			return recorded.JobName == run.JobName && recorded.Succeeded == run.Succeeded
+		})
+		recordRun(status, run, backupRequest.Generation)
+		if !recorded {
+			notifyRun(ctx, r, r.Sender, backupRequest, status, run)
+		}
+	}
+	if equality.Semantic.DeepEqual(status, &backupRequest.Status) {
+		return ctrl.Result{}, nil
+	}
+
+	backupRequest.Status = *status
+	if err := r.Status().Update(ctx, backupRequest); err != nil {
+		log.Error(err, "Unable to update BackupRequest status")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Recorded backup run", "backuprequest", client.ObjectKeyFromObject(backupRequest), "finished", finished)
+	return ctrl.Result{}, nil
+}
+
+// backupRequestForJob returns BackupRequest which runs Job through owner or nil.
+// Scheduled runs are owned by CronJob, manual runs by BackupRequest itself.
+func (r *BackupHistoryReconciler) backupRequestForJob(
+	ctx context.Context,
+	namespace string,
+	owner *metav1.OwnerReference,
+) (*backupv1.BackupRequest, error) {
+	if owner.Kind != "BackupRequest" {
+		return r.backupRequestForCronJob(ctx, namespace, owner.Name)
+	}
+
+	var backupRequest backupv1.BackupRequest
+	err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: owner.Name}, &backupRequest)
+	if apierrors.IsNotFound(err) || (err == nil && backupRequest.UID != owner.UID) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to get BackupRequest: %w", err)
+	}
+	return &backupRequest, nil
+}
+
+// backupRequestForCronJob returns BackupRequest which created CronJob name or nil.
+func (r *BackupHistoryReconciler) backupRequestForCronJob(ctx context.Context, namespace, name string) (*backupv1.BackupRequest, error) {
+	var backupRequests backupv1.BackupRequestList
+	if err := r.List(ctx, &backupRequests, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("unable to list BackupRequests: %w", err)
+	}
+
+	for i, br := range backupRequests.Items {
+		if br.Status.CronJobData.Name == name && br.Status.CronJobData.Namespace == namespace {
+			return &backupRequests.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// ensureBackup creates Backup describing successful run of backupRequest.
+// Backup is named after the Job of the run.
+func (r *BackupHistoryReconciler) ensureBackup(
+	ctx context.Context,
+	backupRequest *backupv1.BackupRequest,
+	run backupv1.BackupRun,
+	report runReport,
+) error {
+	name := client.ObjectKey{Namespace: backupRequest.Namespace, Name: run.JobName}
+	err := r.Get(ctx, name, &backupv1.Backup{})
+	if err == nil || !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	storage, err := resolveBackupRequestStorage(ctx, r, backupRequest)
+	if err != nil {
+		return err
+	}
+
+	backup := &backupv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name.Name,
+			Namespace: name.Namespace,
+			Labels:    map[string]string{backupv1.BackupRequestLabel: backupRequest.Name},
+		},
+		Spec: backupv1.BackupSpec{
+			BackupRequestName:   backupRequest.Name,
+			JobName:             run.JobName,
+			DatabaseType:        backupRequest.Spec.DbSpec.DbType,
+			DatabaseVersion:     report.DatabaseVersion,
+			ToolVersion:         report.ToolVersion,
+			StorageLocationName: backupRequest.Spec.S3Spec.StorageLocationName,
+			Endpoint:            storage.endpoint,
+			BucketName:          storage.bucketName,
+			Key:                 run.Key,
+			Size:                run.Size,
+			Checksum:            report.Checksum,
+			StartTime:           run.StartTime,
+			CompletionTime:      run.CompletionTime,
+		},
+	}
+	if err := controllerutil.SetControllerReference(backupRequest, backup, r.Scheme); err != nil {
+		return err
+	}
+
+	if err := r.Create(ctx, backup); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to create Backup %s: %w", name, err)
+	}
+	return nil
+}
+
+// deleteExpiredBackups deletes Backups of backupRequest whose objects were deleted by retention
+// of run. Only Backups stored in the same bucket as the backup of run are deleted.
+func (r *BackupHistoryReconciler) deleteExpiredBackups(
+	ctx context.Context,
+	backupRequest *backupv1.BackupRequest,
+	run backupv1.BackupRun,
+	report runReport,
+) error {
+	if report.DeletedOmitted > 0 {
+		log.FromContext(ctx).Info("Keys deleted by retention did not fit the report, their Backups are kept",
+			"count", report.DeletedOmitted)
+	}
+	if len(report.Deleted) == 0 {
+		return nil
+	}
+
+	var uploaded backupv1.Backup
+	if err := r.Get(ctx, client.ObjectKey{Namespace: backupRequest.Namespace, Name: run.JobName}, &uploaded); err != nil {
+		return fmt.Errorf("unable to get Backup %s: %w", run.JobName, err)
+	}
+	var backups backupv1.BackupList
+	if err := r.List(ctx, &backups, client.InNamespace(backupRequest.Namespace),
+		client.MatchingLabels{backupv1.BackupRequestLabel: backupRequest.Name}); err != nil {
+		return fmt.Errorf("unable to list Backups: %w", err)
+	}
+	for i := range backups.Items {
+		backup := &backups.Items[i]
+		if backup.Spec.Endpoint != uploaded.Spec.Endpoint || backup.Spec.BucketName != uploaded.Spec.BucketName ||
+			!slices.Contains(report.Deleted, backup.Spec.Key) {
+			continue
+		}
+		if err := r.Delete(ctx, backup); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("unable to delete Backup %s: %w", backup.Name, err)
+		}
+	}
+	return nil
+}
+
+// recordRun adds run to status, keeping at most backupHistoryLimit newest runs,
+// and updates last backup times and LastBackupSucceeded condition.
+func recordRun(status *backupv1.BackupRequestStatus, run backupv1.BackupRun, generation int64) {
+	index := slices.IndexFunc(status.History, func(recorded backupv1.BackupRun) bool { return recorded.JobName == run.JobName })
+	if index >= 0 {
+		status.History[index] = run
+	} else {
+		status.History = append(status.History, run)
+	}
+	slices.SortStableFunc(status.History, func(a, b backupv1.BackupRun) int {
+		return finishedAt(b).Compare(finishedAt(a).Time)
+	})
+	if len(status.History) > backupHistoryLimit {
+		status.History = status.History[:backupHistoryLimit]
+	}
+
+	if run.CompletionTime != nil {
+		last := &status.LastFailedBackupTime
+		if run.Succeeded {
+			last = &status.LastBackupTime
+		}
+		if *last == nil || (*last).Before(run.CompletionTime) {
+			*last = run.CompletionTime
+		}
+	}
+
+	latest := status.History[0]
+	condition := newCondition(TypeLastBackupSucceeded, metav1.ConditionTrue, ReasonBackupSucceeded,
+		fmt.Sprintf("Backup %s is uploaded by Job %s", latest.Key, latest.JobName))
+	if !latest.Succeeded {
+		condition = newCondition(TypeLastBackupSucceeded, metav1.ConditionFalse, ReasonBackupFailed,
+			fmt.Sprintf("Job %s failed: %s", latest.JobName, latest.Message))
+	}
+	setConditions(&status.Conditions, generation, condition)
+}
+
+// updateManualRun updates manualRun by state of its job.
+func updateManualRun(manualRun *backupv1.ManualRun, job *batchv1.Job, pods []corev1.Pod) {
+	manualRun.Phase = jobPhase(job, pods)
+	manualRun.StartTime = job.Status.StartTime
+	manualRun.CompletionTime = jobFinishTime(job)
+	manualRun.Message = ""
+	if manualRun.Phase == backupv1.RunPhaseSucceeded {
+		return
+	}
+
+	var messages []string
+	if condition, finished := jobFinished(job); finished && condition.Message != "" {
+		messages = append(messages, condition.Message)
+	}
+	if message := podsMessage(pods); message != "" {
+		messages = append(messages, message)
+	}
+	manualRun.Message = strings.Join(messages, ": ")
+}
+
+func finishedAt(run backupv1.BackupRun) metav1.Time {
+	if run.CompletionTime == nil {
+		return metav1.Time{}
+	}
+	return *run.CompletionTime
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BackupHistoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&batchv1.Job{}, builder.WithPredicates(predicate.NewPredicateFuncs(isBackupRun))).
+		Named("backuphistory").
+		Complete(r)
+}
+
+// isBackupRun tells whether obj is a Job created by a CronJob or a manual run of BackupRequest.
+func isBackupRun(obj client.Object) bool {
+	owner := metav1.GetControllerOf(obj)
+	return owner != nil && (owner.Kind == "CronJob" || owner.Kind == "BackupRequest") && !isPurgeJob(obj)
+}
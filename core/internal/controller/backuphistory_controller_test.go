@@ -0,0 +1,291 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+var _ = Describe("BackupHistory Controller", func() {
+	ctx := context.Background()
+	start := metav1.Unix(1000, 0)
+	finishedJob := func(name string, conditionType batchv1.JobConditionType, finish metav1.Time) *batchv1.Job {
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: batchv1.JobStatus{
+				StartTime: &start,
+				Conditions: []batchv1.JobCondition{{
+					Type:               conditionType,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: finish,
+					Message:            "Job has reached the specified backoff limit",
+				}},
+			},
+		}
+		if conditionType == batchv1.JobComplete {
+			job.Status.CompletionTime = &finish
+		}
+		return job
+	}
+	terminatedPod := func(exitCode int32, message string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-pod"},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: backuperContainerName,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Message: message},
+					},
+				}},
+			},
+		}
+	}
+
+	Context("When describing a run", func() {
+		It("should skip unfinished Job", func() {
+			_, finished := backupRun(&batchv1.Job{}, nil)
+			Expect(finished).To(BeFalse())
+		})
+
+		It("should read report of successful backuper", func() {
+			job := finishedJob("backup-1", batchv1.JobComplete, metav1.Unix(1060, 0))
+			pods := []corev1.Pod{terminatedPod(0, `{"key":"db/2025-05-01-00-00-00-backup.sql","size":2048}`)}
+
+			run, finished := backupRun(job, pods)
+			Expect(finished).To(BeTrue())
+			Expect(run.Succeeded).To(BeTrue())
+			Expect(run.Key).To(Equal("db/2025-05-01-00-00-00-backup.sql"))
+			Expect(run.Size).To(Equal(int64(2048)))
+			Expect(run.Duration.Duration).To(Equal(time.Minute))
+			Expect(run.Message).To(BeEmpty())
+		})
+
+		It("should explain failed run", func() {
+			job := finishedJob("backup-1", batchv1.JobFailed, metav1.Unix(1060, 0))
+			pods := []corev1.Pod{terminatedPod(1, "Failed to perform backup")}
+
+			run, finished := backupRun(job, pods)
+			Expect(finished).To(BeTrue())
+			Expect(run.Succeeded).To(BeFalse())
+			Expect(run.Key).To(BeEmpty())
+			Expect(run.Message).To(Equal("Job has reached the specified backoff limit: " +
+				"pod backup-pod: container backup-job exited with code 1: : Failed to perform backup"))
+		})
+	})
+
+	Context("When a backup is uploaded", func() {
+		It("should create Backup owned by BackupRequest once", func() {
+			scheme := runtime.NewScheme()
+			Expect(backupv1.AddToScheme(scheme)).To(Succeed())
+			br := &backupv1.BackupRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "team-a", UID: "br-uid"},
+				Spec: backupv1.BackupRequestSpec{
+					DbSpec: backupv1.DatabaseSpec{DbType: "postgres"},
+					S3Spec: backupv1.S3Spec{Endpoint: "s3.example.com", BucketName: "backups"},
+				},
+			}
+			reconciler := &BackupHistoryReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(br).Build(),
+				Scheme: scheme,
+			}
+			job := finishedJob("backup-1", batchv1.JobComplete, metav1.Unix(1060, 0))
+			pods := []corev1.Pod{terminatedPod(0,
+				`{"key":"db/backup.sql","size":10,"checksum":"sha256:abc","databaseVersion":"14.10","toolVersion":"pg_dump (PostgreSQL) 14.10"}`)}
+			run, _ := backupRun(job, pods)
+			report := readRunReport(pods, backuperContainerName)
+
+			Expect(reconciler.ensureBackup(ctx, br, run, report)).To(Succeed())
+			Expect(reconciler.ensureBackup(ctx, br, run, report)).To(Succeed())
+
+			var backups backupv1.BackupList
+			Expect(reconciler.List(ctx, &backups, client.MatchingLabels{backupv1.BackupRequestLabel: "example"})).To(Succeed())
+			Expect(backups.Items).To(HaveLen(1))
+			backup := backups.Items[0]
+			Expect(backup.Name).To(Equal("backup-1"))
+			Expect(metav1.IsControlledBy(&backup, br)).To(BeTrue())
+			Expect(backup.Spec).To(Equal(backupv1.BackupSpec{
+				BackupRequestName: "example",
+				JobName:           "backup-1",
+				DatabaseType:      "postgres",
+				DatabaseVersion:   "14.10",
+				ToolVersion:       "pg_dump (PostgreSQL) 14.10",
+				Endpoint:          "s3.example.com",
+				BucketName:        "backups",
+				Key:               "db/backup.sql",
+				Size:              10,
+				Checksum:          "sha256:abc",
+				StartTime:         run.StartTime,
+				CompletionTime:    run.CompletionTime,
+			}))
+		})
+	})
+
+	Context("When retention deletes backups", func() {
+		It("should delete Backups of deleted keys in the same bucket", func() {
+			scheme := runtime.NewScheme()
+			Expect(backupv1.AddToScheme(scheme)).To(Succeed())
+			br := &backupv1.BackupRequest{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "team-a"}}
+			backup := func(name, bucket, key string) *backupv1.Backup {
+				return &backupv1.Backup{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: "team-a",
+						Labels:    map[string]string{backupv1.BackupRequestLabel: "example"},
+					},
+					Spec: backupv1.BackupSpec{Endpoint: "s3.example.com", BucketName: bucket, Key: key},
+				}
+			}
+			reconciler := &BackupHistoryReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(br,
+					backup("backup-3", "backups", "team-a/example/db/3-backup.sql"),
+					backup("backup-2", "backups", "team-a/example/db/2-backup.sql"),
+					backup("backup-1", "backups", "team-a/example/db/1-backup.sql"),
+					backup("backup-old", "old-backups", "team-a/example/db/1-backup.sql"),
+				).Build(),
+				Scheme: scheme,
+			}
+			report := readRunReport([]corev1.Pod{terminatedPod(0,
+				`{"key":"team-a/example/db/3-backup.sql","deleted":["team-a/example/db/1-backup.sql"]}`)}, backuperContainerName)
+
+			Expect(reconciler.deleteExpiredBackups(ctx, br, backupv1.BackupRun{JobName: "backup-3"}, report)).To(Succeed())
+
+			var backups backupv1.BackupList
+			Expect(reconciler.List(ctx, &backups)).To(Succeed())
+			var names []string
+			for _, backup := range backups.Items {
+				names = append(names, backup.Name)
+			}
+			Expect(names).To(ConsistOf("backup-3", "backup-2", "backup-old"))
+		})
+	})
+
+	Context("When a manual run progresses", func() {
+		It("should follow phase of Job", func() {
+			manualRun := &backupv1.ManualRun{RequestedAt: "1", JobName: "backup-manual", Phase: backupv1.RunPhasePending}
+			pod := corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+			updateManualRun(manualRun, &batchv1.Job{Status: batchv1.JobStatus{StartTime: &start}}, []corev1.Pod{pod})
+			Expect(manualRun.Phase).To(Equal(backupv1.RunPhaseRunning))
+			Expect(manualRun.StartTime).To(Equal(&start))
+			Expect(manualRun.CompletionTime).To(BeNil())
+
+			updateManualRun(manualRun, finishedJob("backup-manual", batchv1.JobFailed, metav1.Unix(1060, 0)),
+				[]corev1.Pod{terminatedPod(1, "Failed to perform backup")})
+			Expect(manualRun.Phase).To(Equal(backupv1.RunPhaseFailed))
+			Expect(manualRun.CompletionTime.Unix()).To(Equal(int64(1060)))
+			Expect(manualRun.Message).To(Equal("Job has reached the specified backoff limit: " +
+				"pod backup-pod: container backup-job exited with code 1: : Failed to perform backup"))
+			Expect(manualRun.RequestedAt).To(Equal("1"))
+		})
+
+		It("should record finished manual run to history", func() {
+			scheme := runtime.NewScheme()
+			Expect(backupv1.AddToScheme(scheme)).To(Succeed())
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			br := &backupv1.BackupRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "team-a", UID: "br-uid"},
+				Status: backupv1.BackupRequestStatus{
+					ManualRun: &backupv1.ManualRun{RequestedAt: "1", JobName: "backup-manual", Phase: backupv1.RunPhasePending},
+				},
+			}
+			job := finishedJob("backup-manual", batchv1.JobComplete, metav1.Unix(1060, 0))
+			job.Namespace = br.Namespace
+			Expect(controllerutil.SetControllerReference(br, job, scheme)).To(Succeed())
+			Expect(isBackupRun(job)).To(BeTrue())
+			pod := terminatedPod(0, `{"key":"db/backup.sql","size":10}`)
+			pod.Namespace = br.Namespace
+			pod.Labels = map[string]string{batchv1.JobNameLabel: job.Name}
+			reconciler := &BackupHistoryReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).
+					WithObjects(br, job, &pod).WithStatusSubresource(br).Build(),
+				Scheme: scheme,
+			}
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(job)})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(br), br)).To(Succeed())
+			Expect(br.Status.ManualRun.Phase).To(Equal(backupv1.RunPhaseSucceeded))
+			Expect(br.Status.ManualRun.Message).To(BeEmpty())
+			Expect(br.Status.History).To(ConsistOf(HaveField("JobName", "backup-manual")))
+			Expect(reconciler.Get(ctx, client.ObjectKey{Namespace: "team-a", Name: "backup-manual"}, &backupv1.Backup{})).To(Succeed())
+		})
+	})
+
+	Context("When recording a run", func() {
+		run := func(name string, succeeded bool, finish int64) backupv1.BackupRun {
+			completion := metav1.Unix(finish, 0)
+			return backupv1.BackupRun{JobName: name, Succeeded: succeeded, CompletionTime: &completion, Key: name + ".sql"}
+		}
+
+		It("should keep newest runs first", func() {
+			status := &backupv1.BackupRequestStatus{}
+			for i := range backupHistoryLimit + 2 {
+				recordRun(status, run(fmt.Sprintf("backup-%02d", i), true, int64(1000+i)), 3)
+			}
+			recordRun(status, run("backup-old", false, 500), 3)
+
+			Expect(status.History).To(HaveLen(backupHistoryLimit))
+			Expect(status.History[0].JobName).To(Equal("backup-11"))
+			Expect(status.History[backupHistoryLimit-1].JobName).To(Equal("backup-02"))
+			Expect(status.History).NotTo(ContainElement(HaveField("JobName", "backup-old")))
+			Expect(status.LastBackupTime.Unix()).To(Equal(int64(1011)))
+			Expect(status.LastFailedBackupTime.Unix()).To(Equal(int64(500)))
+		})
+
+		It("should not record the same run twice", func() {
+			status := &backupv1.BackupRequestStatus{}
+			recordRun(status, run("backup-1", true, 1000), 1)
+			recordRun(status, run("backup-1", true, 1000), 1)
+
+			Expect(status.History).To(HaveLen(1))
+		})
+
+		It("should set LastBackupSucceeded by the latest run", func() {
+			status := &backupv1.BackupRequestStatus{}
+			recordRun(status, run("backup-1", true, 1000), 2)
+			Expect(meta.IsStatusConditionTrue(status.Conditions, TypeLastBackupSucceeded)).To(BeTrue())
+
+			failed := run("backup-2", false, 2000)
+			failed.Message = "Job has reached the specified backoff limit"
+			recordRun(status, failed, 2)
+
+			condition := meta.FindStatusCondition(status.Conditions, TypeLastBackupSucceeded)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(ReasonBackupFailed))
+			Expect(condition.Message).To(ContainSubstring("backup-2"))
+			Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+			Expect(status.LastBackupTime.Unix()).To(Equal(int64(1000)))
+		})
+	})
+})
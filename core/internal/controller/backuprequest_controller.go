@@ -25,17 +25,22 @@ import (
 	pb "github.com/oiler-backup/base/proto"
 	backupv1 "github.com/oiler-backup/core/core/api/v1"
 	config "github.com/oiler-backup/core/core/internal/config"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 var (
@@ -50,14 +55,20 @@ var (
 // BackupRequestReconciler reconciles a BackupRequest object
 type BackupRequestReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=backup.oiler.backup,resources=backuprequests,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=backup.oiler.backup,resources=backuprequests/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=backup.oiler.backup,resources=backuprequests/finalizers,verbs=update
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=backupstoragelocations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=databaseadapters,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="batch",resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="batch",resources=jobs,verbs=get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *BackupRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	cjExists := false
@@ -71,17 +82,102 @@ func (r *BackupRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	dbControllers, err := loadDatabaseConfig(ctx, r, appCfg.OperatorNamespace)
-	if err != nil {
+	if !backupRequest.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(&backupRequest, backupRequestFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		if !mustPurge(&backupRequest) {
+			log.Info("Releasing BackupRequest, backups are retained")
+			return ctrl.Result{}, r.removeFinalizer(ctx, &backupRequest)
+		}
+		if backupRequest.Status.PurgeJobName != "" {
+			return r.trackPurge(ctx, &backupRequest)
+		}
+	} else if controllerutil.AddFinalizer(&backupRequest, backupRequestFinalizer) {
+		if err := r.Update(ctx, &backupRequest); err != nil {
+			log.Error(err, "Unable to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	adapter, err := resolveAdapter(ctx, r, backupRequest.Spec.DbSpec.DbType, appCfg.OperatorNamespace)
+	if isUnsupported(err) {
+		log.Error(err, "Make sure to create DatabaseAdapter or update database-config cm")
+		innerErr := r.setFailed(ctx, req.NamespacedName, ReasonUnsupportedDatabase, err,
+			failedCondition(TypeAdapterReachable, ReasonUnsupportedDatabase, err),
+		)
+		if innerErr != nil {
+			return ctrl.Result{}, innerErr
+		}
+		return ctrl.Result{}, err
+	} else if err != nil {
 		log.Error(err, "Failed to load config")
+		innerErr := r.setFailed(ctx, req.NamespacedName, ReasonDatabaseConfigUnavailable, err)
+		if innerErr != nil {
+			return ctrl.Result{}, innerErr
+		}
+		return ctrl.Result{}, err
+	}
+
+	storage, err := resolveBackupRequestStorage(ctx, r, &backupRequest)
+	if err != nil {
+		log.Error(err, "Failed to resolve storage location")
+		innerErr := r.setFailed(ctx, req.NamespacedName, ReasonStorageLocationUnavailable, err)
+		if innerErr != nil {
+			return ctrl.Result{}, innerErr
+		}
+		return ctrl.Result{}, err
+	}
+
+	overlay, err := prepareCredentials(ctx, r.Client, r.Scheme, &backupRequest,
+		credentialsSecretName("br", backupRequest.Name), backupRequest.Spec.DbSpec.PasswordSecretRef, storage,
+	)
+	if err != nil {
+		log.Error(err, "Failed to prepare credentials")
+		innerErr := r.setFailed(ctx, req.NamespacedName, ReasonCredentialsUnavailable, err)
+		if innerErr != nil {
+			return ctrl.Result{}, innerErr
+		}
 		return ctrl.Result{}, err
 	}
 
-	controllerAddress, exists := dbControllers[backupRequest.Spec.DbSpec.DbType]
-	if !exists {
-		err := ErrNotSupported(backupRequest.Spec.DbSpec.DbType)
-		log.Error(err, "Make sure to update database-config cm")
-		innerErr := r.setFailed(ctx, req.NamespacedName)
+	// Failed runs report the tail of backuper log, see BackupHistoryReconciler.
+	overlay = overlay.withLogsOnError().withSuspend(backupRequest.Spec.Suspend).
+		withTimeZone(backupRequest.Spec.TimeZone).withRetention(backupRequest.Spec.Retention).
+		withJobControls(backupRequest.Spec.JobControls).withCronJobControls(backupRequest.Spec.CronJobControls).
+		withPodTemplate(backupRequest.Spec.PodTemplate).withBackupPrefix(&backupRequest)
+	hooks, err := hooksEnv(backupRequest.Spec.Hooks)
+	if err != nil {
+		log.Error(err, "Failed to prepare hooks")
+		innerErr := r.setFailed(ctx, req.NamespacedName, ReasonCronJobFailed, err)
+		if innerErr != nil {
+			return ctrl.Result{}, innerErr
+		}
+		return ctrl.Result{}, err
+	}
+	if hooks != "" {
+		overlay = overlay.withEnv(envHooks, hooks)
+	}
+
+	if !backupRequest.DeletionTimestamp.IsZero() {
+		if err := r.startPurge(ctx, adapter, &backupRequest, storage, overlay); err != nil {
+			log.Error(err, "Failed to purge backups")
+			conditions := []metav1.Condition{}
+			if condition, ok := adapterCondition(adapter.address, err); ok {
+				conditions = append(conditions, condition)
+			}
+			innerErr := r.setFailed(ctx, req.NamespacedName, ReasonPurgeFailed, err, conditions...)
+			if innerErr != nil {
+				return ctrl.Result{}, innerErr
+			}
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if err := adapter.supports(backupv1.AdapterCapabilityBackup); err != nil {
+		log.Error(err, "Adapter cannot back up")
+		innerErr := r.setFailed(ctx, req.NamespacedName, ReasonUnsupportedDatabase, err)
 		if innerErr != nil {
 			return ctrl.Result{}, innerErr
 		}
@@ -89,30 +185,59 @@ func (r *BackupRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	if backupRequest.Status.Status == SUCCESS {
-		err := r.updateCronJob(ctx, controllerAddress, &backupRequest)
+		_, err := r.updateCronJob(ctx, adapter, &backupRequest, storage, overlay)
 		if err != nil {
+			log.Error(err, "Failed to update CronJob")
+			reason, conditions := cronJobFailure(adapter.address, err)
+			innerErr := r.setFailed(ctx, req.NamespacedName, reason, err, conditions...)
+			if innerErr != nil {
+				return ctrl.Result{}, innerErr
+			}
+			return ctrl.Result{}, err
+		}
+
+		applied := newCondition(TypeScheduleApplied, metav1.ConditionTrue, ReasonCronJobUpdated,
+			fmt.Sprintf("CronJob %s/%s is updated to generation %d",
+				backupRequest.Status.CronJobData.Namespace, backupRequest.Status.CronJobData.Name, backupRequest.Generation))
+		setConditions(&backupRequest.Status.Conditions, backupRequest.Generation,
+			adapterReachable(adapter.address), applied, scheduledCondition(backupRequest.Spec.Suspend),
+		)
+		recordCondition(r.Recorder, &backupRequest, applied)
+		setSuspended(&backupRequest.Status, backupRequest.Spec.Suspend)
+		backupRequest.Status.AppliedGeneration = backupRequest.Generation
+		r.runNowIfRequested(ctx, adapter, &backupRequest, storage, overlay)
+		backupRequest.Status.ObservedGeneration = backupRequest.Generation
+		if err := r.Status().Update(ctx, &backupRequest); err != nil {
+			log.Error(err, "Unable to update BackupRequest status")
 			return ctrl.Result{}, err
 		}
 		return ctrl.Result{}, nil
 	}
 
 	backupRequest.Status.Status = IN_PROGRESS
+	setConditions(&backupRequest.Status.Conditions, backupRequest.Generation,
+		newCondition(TypeReady, metav1.ConditionUnknown, ReasonReconciling, "Creating CronJob"),
+	)
 	if err := r.Status().Update(ctx, &backupRequest); err != nil {
 		log.Error(err, "Unable to update BackupRequest status")
-		innerErr := r.setFailed(ctx, req.NamespacedName)
+		innerErr := r.setFailed(ctx, req.NamespacedName, ReasonStatusUpdateFailed, err)
 		if innerErr != nil {
 			return ctrl.Result{}, innerErr
 		}
 		return ctrl.Result{}, err
 	}
 
-	cronJob, err := r.delegateToController(ctx, controllerAddress, &backupRequest)
+	cronJob, err := r.delegateToController(ctx, adapter, &backupRequest, storage, overlay)
+	if err == nil || errors.Is(err, ErrAlreadyExists) {
+		recordCondition(r.Recorder, &backupRequest, adapterReachable(adapter.address))
+	}
 	if errors.Is(err, ErrAlreadyExists) {
 		log.Info("CronJob for BackupRequest already exists", "name", backupRequest.Name)
 		cjExists = true
 	} else if err != nil {
 		log.Error(err, "Cannot delegate to controller")
-		innerErr := r.setFailed(ctx, req.NamespacedName)
+		reason, conditions := cronJobFailure(adapter.address, err)
+		innerErr := r.setFailed(ctx, req.NamespacedName, reason, err, conditions...)
 		if innerErr != nil {
 			return ctrl.Result{}, innerErr
 		}
@@ -130,7 +255,9 @@ func (r *BackupRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 		if err := r.Update(ctx, cronJob); err != nil {
 			log.Error(err, "Failed to update cronJob")
-			innerErr := r.setFailed(ctx, req.NamespacedName)
+			innerErr := r.setFailed(ctx, req.NamespacedName, ReasonCronJobFailed, err,
+				failedCondition(TypeScheduleApplied, ReasonCronJobFailed, err),
+			)
 			if innerErr != nil {
 				return ctrl.Result{}, innerErr
 			}
@@ -139,13 +266,30 @@ func (r *BackupRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	backupRequest.Status.Status = SUCCESS
-	backupRequest.Status.CronJobData = backupv1.CreatedCronJobData{
-		Name:      cronJob.Name,
-		Namespace: cronJob.Namespace,
+	if !cjExists {
+		backupRequest.Status.CronJobData = backupv1.CreatedCronJobData{
+			Name:      cronJob.Name,
+			Namespace: cronJob.Namespace,
+		}
 	}
+	applied := newCondition(TypeScheduleApplied, metav1.ConditionTrue, ReasonCronJobCreated,
+		fmt.Sprintf("CronJob %s/%s is created", backupRequest.Status.CronJobData.Namespace, backupRequest.Status.CronJobData.Name))
+	setConditions(&backupRequest.Status.Conditions, backupRequest.Generation,
+		adapterReachable(adapter.address), applied, scheduledCondition(backupRequest.Spec.Suspend),
+	)
+	recordCondition(r.Recorder, &backupRequest, applied)
+	setSuspended(&backupRequest.Status, backupRequest.Spec.Suspend)
+	backupRequest.Status.AppliedGeneration = backupRequest.Generation
+	if meta.FindStatusCondition(backupRequest.Status.Conditions, TypeLastBackupSucceeded) == nil {
+		setConditions(&backupRequest.Status.Conditions, backupRequest.Generation,
+			newCondition(TypeLastBackupSucceeded, metav1.ConditionUnknown, ReasonNoBackupYet, "No backup has run yet"),
+		)
+	}
+	r.runNowIfRequested(ctx, adapter, &backupRequest, storage, overlay)
+	backupRequest.Status.ObservedGeneration = backupRequest.Generation
 	if err := r.Status().Update(ctx, &backupRequest); err != nil {
 		log.Error(err, "Unable to update BackupRequest status")
-		innerErr := r.setFailed(ctx, req.NamespacedName)
+		innerErr := r.setFailed(ctx, req.NamespacedName, ReasonStatusUpdateFailed, err)
 		if innerErr != nil {
 			return ctrl.Result{}, innerErr
 		}
@@ -156,18 +300,29 @@ func (r *BackupRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	return ctrl.Result{}, nil
 }
 
-func (r *BackupRequestReconciler) setFailed(ctx context.Context, nsName types.NamespacedName) error {
+// setFailed sets Ready condition to False with reason and message of cause.
+// Additional conditions are set alongside. Status of BackupRequest whose CronJob
+// was already created stays SUCCESS, so next reconciliation updates the CronJob.
+func (r *BackupRequestReconciler) setFailed(
+	ctx context.Context,
+	nsName types.NamespacedName,
+	reason string,
+	cause error,
+	conditions ...metav1.Condition,
+) error {
 	log := log.FromContext(ctx).WithValues("set-failed", nsName)
 	var backupRequest backupv1.BackupRequest
 	if err := r.Get(ctx, nsName, &backupRequest); err != nil {
 		return err
 	}
 
-	if backupRequest.Status.Status == SUCCESS {
-		return nil
+	ready := failedCondition(TypeReady, reason, cause)
+	setConditions(&backupRequest.Status.Conditions, backupRequest.Generation, append(conditions, ready)...)
+	recordCondition(r.Recorder, &backupRequest, ready)
+	backupRequest.Status.ObservedGeneration = backupRequest.Generation
+	if backupRequest.Status.Status != SUCCESS {
+		backupRequest.Status.Status = FAILURE
 	}
-
-	backupRequest.Status.Status = FAILURE
 	log.Info("Setting BackupRequest failed")
 	if err := r.Status().Update(ctx, &backupRequest); err != nil {
 		log.Error(err, "Failed to set failed status on br")
@@ -178,16 +333,51 @@ func (r *BackupRequestReconciler) setFailed(ctx context.Context, nsName types.Na
 	return nil
 }
 
-func (r *BackupRequestReconciler) delegateToController(ctx context.Context, controllerAddress string, backupRequest *backupv1.BackupRequest) (*batchv1.CronJob, error) {
-	conn, err := grpc.NewClient(
-		controllerAddress,
-		grpc.WithTransportCredentials(
-			insecure.NewCredentials(),
-		),
-	)
+// scheduledCondition returns Ready condition of BackupRequest whose CronJob is applied.
+func scheduledCondition(suspend bool) metav1.Condition {
+	if suspend {
+		return newCondition(TypeReady, metav1.ConditionTrue, ReasonSuspended, "Backups are suspended")
+	}
+	return newCondition(TypeReady, metav1.ConditionTrue, ReasonReconciled, "Backups are scheduled")
+}
+
+// setSuspended records whether CronJob is suspended. SuspendedSince is kept
+// while BackupRequest stays suspended.
+func setSuspended(status *backupv1.BackupRequestStatus, suspend bool) {
+	switch {
+	case !suspend:
+		status.SuspendedSince = nil
+	case !status.Suspended || status.SuspendedSince == nil:
+		now := metav1.Now()
+		status.SuspendedSince = &now
+	}
+	status.Suspended = suspend
+}
 
+// cronJobFailure returns reason and conditions describing err returned while
+// creating or updating CronJob. Unreachable adapter is reported as the reason.
+func cronJobFailure(controllerAddress string, err error) (string, []metav1.Condition) {
+	reason := ReasonCronJobFailed
+	conditions := []metav1.Condition{failedCondition(TypeScheduleApplied, ReasonCronJobFailed, err)}
+	if condition, ok := adapterCondition(controllerAddress, err); ok {
+		conditions = append(conditions, condition)
+		if condition.Status == metav1.ConditionFalse {
+			reason = condition.Reason
+		}
+	}
+	return reason, conditions
+}
+
+func (r *BackupRequestReconciler) delegateToController(
+	ctx context.Context,
+	adapter databaseAdapter,
+	backupRequest *backupv1.BackupRequest,
+	storage storageLocation,
+	overlay workloadOverlay,
+) (*batchv1.CronJob, error) {
+	conn, err := adapter.dial()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to %s: %w", controllerAddress, err)
+		return nil, err
 	}
 	defer func() {
 		err := conn.Close()
@@ -197,25 +387,19 @@ func (r *BackupRequestReconciler) delegateToController(ctx context.Context, cont
 	}()
 	client := pb.NewBackupServiceClient(conn)
 
-	req := &pb.BackupRequest{
-		DbUri:          backupRequest.Spec.DbSpec.URI,
-		DbPort:         int64(backupRequest.Spec.DbSpec.Port),
-		DbUser:         backupRequest.Spec.DbSpec.User,
-		DbPass:         backupRequest.Spec.DbSpec.Pass,
-		DbName:         backupRequest.Spec.DbSpec.DbName,
-		DatabaseType:   backupRequest.Spec.DbSpec.DbType,
-		Schedule:       backupRequest.Spec.Schedule,
-		S3Endpoint:     backupRequest.Spec.S3Spec.Endpoint,
-		S3AccessKey:    backupRequest.Spec.S3Spec.Auth.AccessKey,
-		S3SecretKey:    backupRequest.Spec.S3Spec.Auth.SecretKey,
-		S3BucketName:   backupRequest.Spec.S3Spec.BucketName,
-		CoreAddr:       os.Getenv("CORE_ADDR"),
-		MaxBackupCount: backupRequest.Spec.MaxBackupCount,
+	req := buildBackupRequest(backupRequest, storage)
+
+	if !overlay.isEmpty() {
+		patch, err := overlay.cronJobPatch()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build overlay: %w", err)
+		}
+		ctx = withOverlay(ctx, patch)
 	}
 
 	resp, err := client.Backup(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to invoke backup method %s: %w", controllerAddress, err)
+		return nil, fmt.Errorf("failed to invoke backup method %s: %w", adapter.address, err)
 	}
 	if resp.Status == "Exists" {
 		return nil, ErrAlreadyExists
@@ -236,41 +420,27 @@ func (r *BackupRequestReconciler) delegateToController(ctx context.Context, cont
 	return &cronJob, nil
 }
 
-func (r *BackupRequestReconciler) updateCronJob(ctx context.Context, controllerAddress string, backupRequest *backupv1.BackupRequest) error {
-	conn, err := grpc.NewClient(
-		controllerAddress,
-		grpc.WithTransportCredentials(
-			insecure.NewCredentials(),
-		),
-	)
-
+// updateCronJob asks adapter to update CronJob of backupRequest.
+func (r *BackupRequestReconciler) updateCronJob(
+	ctx context.Context,
+	adapter databaseAdapter,
+	backupRequest *backupv1.BackupRequest,
+	storage storageLocation,
+	overlay workloadOverlay,
+) (*pb.BackupResponse, error) {
+	conn, err := adapter.dial()
 	if err != nil {
-		return fmt.Errorf("failed to connect to %s: %w", controllerAddress, err)
+		return nil, err
 	}
 	defer func() {
-		err := conn.Close()
-		if err != nil {
-			panic(err)
+		if err := conn.Close(); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to close connection to adapter", "address", adapter.address)
 		}
 	}()
 
 	client := pb.NewBackupServiceClient(conn)
 
-	br := &pb.BackupRequest{
-		DbUri:          backupRequest.Spec.DbSpec.URI,
-		DbPort:         int64(backupRequest.Spec.DbSpec.Port),
-		DbUser:         backupRequest.Spec.DbSpec.User,
-		DbPass:         backupRequest.Spec.DbSpec.Pass,
-		DbName:         backupRequest.Spec.DbSpec.DbName,
-		DatabaseType:   backupRequest.Spec.DbSpec.DbType,
-		Schedule:       backupRequest.Spec.Schedule,
-		S3Endpoint:     backupRequest.Spec.S3Spec.Endpoint,
-		S3AccessKey:    backupRequest.Spec.S3Spec.Auth.AccessKey,
-		S3SecretKey:    backupRequest.Spec.S3Spec.Auth.SecretKey,
-		S3BucketName:   backupRequest.Spec.S3Spec.BucketName,
-		CoreAddr:       os.Getenv("CORE_ADDR"),
-		MaxBackupCount: backupRequest.Spec.MaxBackupCount,
-	}
+	br := buildBackupRequest(backupRequest, storage)
 
 	req := pb.UpdateBackupRequest{
 		Request:          br,
@@ -278,12 +448,54 @@ func (r *BackupRequestReconciler) updateCronJob(ctx context.Context, controllerA
 		CronjobNamespace: backupRequest.Status.CronJobData.Namespace,
 	}
 
-	_, err = client.Update(ctx, &req)
-	if err != nil {
-		return err
+	if !overlay.isEmpty() {
+		patch, err := overlay.cronJobPatch()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build overlay: %w", err)
+		}
+		ctx = withOverlay(ctx, patch)
 	}
 
-	return nil
+	return client.Update(ctx, &req)
+}
+
+// resolveBackupRequestStorage resolves S3 storage of backupRequest.
+func resolveBackupRequestStorage(ctx context.Context, r client.Reader, backupRequest *backupv1.BackupRequest) (storageLocation, error) {
+	s3Spec := backupRequest.Spec.S3Spec
+	return resolveStorageLocation(ctx, r, s3Spec.StorageLocationName, storageLocation{
+		endpoint:             s3Spec.Endpoint,
+		bucketName:           s3Spec.BucketName,
+		accessKey:            s3Spec.Auth.AccessKey,
+		secretKey:            s3Spec.Auth.SecretKey,
+		credentialsRef:       s3Spec.CredentialsSecretRef,
+		credentialsNamespace: backupRequest.Namespace,
+	})
+}
+
+// buildBackupRequest converts backupRequest stored in storage to gRPC request.
+// Credentials referenced by Secrets are not passed to adapter.
+func buildBackupRequest(backupRequest *backupv1.BackupRequest, storage storageLocation) *pb.BackupRequest {
+	dbPass := backupRequest.Spec.DbSpec.Pass
+	if backupRequest.Spec.DbSpec.PasswordSecretRef != nil {
+		dbPass = ""
+	}
+	s3AccessKey, s3SecretKey := storage.plainKeys()
+
+	return &pb.BackupRequest{
+		DbUri:          backupRequest.Spec.DbSpec.URI,
+		DbPort:         int64(backupRequest.Spec.DbSpec.Port),
+		DbUser:         backupRequest.Spec.DbSpec.User,
+		DbPass:         dbPass,
+		DbName:         backupRequest.Spec.DbSpec.DbName,
+		DatabaseType:   backupRequest.Spec.DbSpec.DbType,
+		Schedule:       backupRequest.Spec.Schedule,
+		S3Endpoint:     storage.endpoint,
+		S3AccessKey:    s3AccessKey,
+		S3SecretKey:    s3SecretKey,
+		S3BucketName:   storage.bucketName,
+		CoreAddr:       os.Getenv("CORE_ADDR"),
+		MaxBackupCount: backupRequest.Spec.MaxBackupCount,
+	}
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -295,8 +507,78 @@ func (r *BackupRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	}
 
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&backupv1.BackupRequest{}).
+		// RunNowAnnotation does not change generation.
+		For(&backupv1.BackupRequest{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{},
+				predicate.NewPredicateFuncs(isBeingDeleted)),
+		)).
+		Owns(&batchv1.Job{}, builder.WithPredicates(predicate.NewPredicateFuncs(isPurgeJob))).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForSecret),
+		).
+		Watches(
+			&backupv1.BackupStorageLocation{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForStorageLocation),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Named("backuprequest").
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
 		Complete(r)
 }
+
+// requestsForSecret maps Secret to BackupRequests reading credentials from it
+// directly or through BackupStorageLocation. Rotated credentials are copied
+// to managed Secrets, so CronJobs pick them up on next run.
+func (r *BackupRequestReconciler) requestsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	log := log.FromContext(ctx).WithValues("secret", client.ObjectKeyFromObject(secret))
+
+	var locations backupv1.BackupStorageLocationList
+	if err := r.List(ctx, &locations); err != nil {
+		log.Error(err, "Unable to list BackupStorageLocations")
+		return nil
+	}
+	referencingLocations := map[string]bool{}
+	for _, location := range locations.Items {
+		ref := location.Spec.CredentialsSecretRef
+		if isSameSecret(ref.Name, ref.Namespace, appCfg.OperatorNamespace, secret.GetName(), secret.GetNamespace()) {
+			referencingLocations[location.Name] = true
+		}
+	}
+
+	var backupRequests backupv1.BackupRequestList
+	if err := r.List(ctx, &backupRequests); err != nil {
+		log.Error(err, "Unable to list BackupRequests")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, br := range backupRequests.Items {
+		dbRef := br.Spec.DbSpec.PasswordSecretRef
+		s3Ref := br.Spec.S3Spec.CredentialsSecretRef
+		switch {
+		case dbRef != nil && isSameSecret(dbRef.Name, dbRef.Namespace, br.Namespace, secret.GetName(), secret.GetNamespace()),
+			s3Ref != nil && isSameSecret(s3Ref.Name, s3Ref.Namespace, br.Namespace, secret.GetName(), secret.GetNamespace()),
+			referencingLocations[br.Spec.S3Spec.StorageLocationName]:
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&br)})
+		}
+	}
+
+	return requests
+}
+
+// requestsForStorageLocation maps BackupStorageLocation to BackupRequests using it.
+func (r *BackupRequestReconciler) requestsForStorageLocation(ctx context.Context, location client.Object) []reconcile.Request {
+	var backupRequests backupv1.BackupRequestList
+	if err := r.List(ctx, &backupRequests); err != nil {
+		log.FromContext(ctx).Error(err, "Unable to list BackupRequests")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, br := range backupRequests.Items {
+		if br.Spec.S3Spec.StorageLocationName == location.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&br)})
+		}
+	}
+
+	return requests
+}
@@ -24,9 +24,11 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -43,8 +45,9 @@ var _ = Describe("BackupRequest Controller", func() {
 		_ = backupv1.AddToScheme(scheme)
 
 		reconciler = &BackupRequestReconciler{
-			Client: k8sClient,
-			Scheme: scheme,
+			Client:   k8sClient,
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(10),
 		}
 		appCfg.OperatorNamespace = "oiler-backup-system"
 	})
@@ -116,8 +119,11 @@ var _ = Describe("BackupRequest Controller", func() {
 			br := &backupv1.BackupRequest{}
 			if err := k8sClient.Get(ctx, nsName, br); err == nil {
 				Expect(k8sClient.Delete(ctx, br)).To(Succeed())
+				By("Releasing finalizer of BackupRequest retaining its backups")
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: nsName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(apierrors.IsNotFound(k8sClient.Get(ctx, nsName, br))).To(BeTrue())
 			}
-
 		})
 
 		It("should fail if database type is unsupported", func() {
@@ -148,6 +154,101 @@ var _ = Describe("BackupRequest Controller", func() {
 			err = k8sClient.Get(ctx, req.NamespacedName, br)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(br.Status.Status).To(Equal(FAILURE))
+
+			ready := meta.FindStatusCondition(br.Status.Conditions, TypeReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(ReasonUnsupportedDatabase))
+			Expect(ready.Message).To(ContainSubstring("database unknown is not supported"))
+			Expect(ready.ObservedGeneration).To(Equal(br.Generation))
+
+			adapter := meta.FindStatusCondition(br.Status.Conditions, TypeAdapterReachable)
+			Expect(adapter).NotTo(BeNil())
+			Expect(adapter.Status).To(Equal(metav1.ConditionFalse))
+
+			Expect(reconciler.Recorder.(*record.FakeRecorder).Events).To(Receive(Equal(
+				"Warning UnsupportedDatabase database unknown is not supported")))
+		})
+
+		It("should fail if password secret is missing", func() {
+			br := &backupv1.BackupRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "missing-secret",
+					Namespace: "default",
+				},
+				Spec: backupv1.BackupRequestSpec{
+					DbSpec: backupv1.DatabaseSpec{
+						DbType: "postgres",
+						PasswordSecretRef: &backupv1.SecretKeyReference{
+							Name: "db-credentials",
+							Key:  "password",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, br)).To(Succeed())
+
+			req := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "missing-secret",
+					Namespace: "default",
+				},
+			}
+
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unable to get Secret"))
+
+			err = k8sClient.Get(ctx, req.NamespacedName, br)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(br.Status.Status).To(Equal(FAILURE))
+
+			ready := meta.FindStatusCondition(br.Status.Conditions, TypeReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(ReasonCredentialsUnavailable))
+			Expect(ready.Message).To(ContainSubstring("unable to get Secret"))
+			Expect(ready.ObservedGeneration).To(Equal(br.Generation))
+		})
+
+		It("should fail if storage location is missing", func() {
+			br := &backupv1.BackupRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "missing-storage",
+					Namespace: "default",
+				},
+				Spec: backupv1.BackupRequestSpec{
+					DbSpec: backupv1.DatabaseSpec{
+						DbType: "postgres",
+					},
+					S3Spec: backupv1.S3Spec{
+						StorageLocationName: "missing",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, br)).To(Succeed())
+
+			req := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "missing-storage",
+					Namespace: "default",
+				},
+			}
+
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unable to get BackupStorageLocation missing"))
+
+			err = k8sClient.Get(ctx, req.NamespacedName, br)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(br.Status.Status).To(Equal(FAILURE))
+
+			ready := meta.FindStatusCondition(br.Status.Conditions, TypeReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(ReasonStorageLocationUnavailable))
+			Expect(ready.Message).To(ContainSubstring("unable to get BackupStorageLocation missing"))
+			Expect(ready.ObservedGeneration).To(Equal(br.Generation))
 		})
 
 		It("should handle failed grpc call", func() {
@@ -159,6 +260,38 @@ var _ = Describe("BackupRequest Controller", func() {
 			br := &backupv1.BackupRequest{}
 			Expect(k8sClient.Get(ctx, nsName, br)).To(Succeed())
 			Expect(br.Status.Status).To(Equal(FAILURE))
+
+			ready := meta.FindStatusCondition(br.Status.Conditions, TypeReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(ReasonAdapterUnreachable))
+			Expect(meta.IsStatusConditionFalse(br.Status.Conditions, TypeAdapterReachable)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(br.Status.Conditions, TypeScheduleApplied)).To(BeTrue())
+			Expect(br.Status.ObservedGeneration).To(Equal(br.Generation))
+		})
+	})
+
+	Context("When BackupRequest is suspended", func() {
+		It("should keep time it was suspended at", func() {
+			status := &backupv1.BackupRequestStatus{}
+			setSuspended(status, true)
+			Expect(status.Suspended).To(BeTrue())
+			since := status.SuspendedSince
+			Expect(since).NotTo(BeNil())
+
+			setSuspended(status, true)
+			Expect(status.SuspendedSince).To(BeIdenticalTo(since))
+
+			setSuspended(status, false)
+			Expect(status.Suspended).To(BeFalse())
+			Expect(status.SuspendedSince).To(BeNil())
+		})
+
+		It("should report suspended schedule as ready", func() {
+			condition := scheduledCondition(true)
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(ReasonSuspended))
+			Expect(scheduledCondition(false).Reason).To(Equal(ReasonReconciled))
 		})
 	})
 })
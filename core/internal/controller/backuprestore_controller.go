@@ -23,31 +23,42 @@ import (
 	"os"
 
 	pb "github.com/oiler-backup/base/proto"
-	grpc "google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	"github.com/oiler-backup/core/core/internal/notify"
 )
 
 // BackupRestoreReconciler reconciles a BackupRestore object
 type BackupRestoreReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Sender sends RestoreCompleted notifications.
+	Sender notify.Sender
 }
 
 // +kubebuilder:rbac:groups=backup.oiler.backup,resources=backuprestores,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=backup.oiler.backup,resources=backuprestores/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=backup.oiler.backup,resources=backuprestores/finalizers,verbs=update
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=backupstoragelocations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=databaseadapters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=backuprequests,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="batch",resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *BackupRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	jExists := false
@@ -57,63 +68,138 @@ func (r *BackupRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	err := r.Get(ctx, req.NamespacedName, &backupRestore)
 	if apierrors.IsNotFound(err) {
 		return ctrl.Result{}, nil
-	} else if backupRestore.Status.Status != "" {
-		return ctrl.Result{}, nil
 	} else if err != nil {
 		log.Error(err, "Unable to get BackupRequest object")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	dbControllers, err := loadDatabaseConfig(ctx, r, appCfg.OperatorNamespace)
-	if err != nil {
-		log.Error(err, "Failed to load config")
-		return ctrl.Result{}, err
+	switch {
+	case backupRestore.Status.Phase == backupv1.RestorePhaseSucceeded,
+		backupRestore.Status.Phase == backupv1.RestorePhaseFailed:
+		return ctrl.Result{}, nil
+	case backupRestore.Status.JobName != "":
+		return r.trackJob(ctx, &backupRestore)
+	case backupRestore.Status.Status != "":
+		return ctrl.Result{}, nil
+	}
+
+	adapter, adapterErr := resolveAdapter(ctx, r, backupRestore.Spec.DatabaseType, appCfg.OperatorNamespace)
+	if adapterErr != nil && !isUnsupported(adapterErr) {
+		log.Error(adapterErr, "Failed to load config")
+		return ctrl.Result{}, adapterErr
 	}
 
 	backupRestore.Status.Status = IN_PROGRESS
+	backupRestore.Status.Phase = backupv1.RestorePhasePending
+	backupRestore.Status.ObservedGeneration = backupRestore.Generation
+	setConditions(&backupRestore.Status.Conditions, backupRestore.Generation,
+		newCondition(TypeReady, metav1.ConditionUnknown, ReasonReconciling, "Creating restore Job"),
+	)
 	if err := r.Status().Update(ctx, &backupRestore); err != nil {
 		log.Error(err, "Unable to update BackupRequest status")
-		r.mustSetFailed(ctx, req.NamespacedName)
+		r.mustSetFailed(ctx, req.NamespacedName, ReasonStatusUpdateFailed, err)
+		return ctrl.Result{}, err
+	}
+
+	if adapterErr == nil {
+		adapterErr = adapter.supports(backupv1.AdapterCapabilityRestore)
+	}
+	if adapterErr != nil {
+		log.Error(adapterErr, "Make sure to create DatabaseAdapter or update database-config cm")
+		r.mustSetFailed(ctx, req.NamespacedName, ReasonUnsupportedDatabase, adapterErr,
+			failedCondition(TypeAdapterReachable, ReasonUnsupportedDatabase, adapterErr),
+		)
+		return ctrl.Result{}, adapterErr
+	}
+
+	source, err := resolveSource(ctx, r.Client, &backupRestore)
+	if err != nil {
+		log.Error(err, "Failed to resolve source of backups")
+		r.mustSetFailed(ctx, req.NamespacedName, ReasonSourceUnavailable, err)
+		return ctrl.Result{}, err
+	}
+
+	revisionSource, ok, err := revisionSource(ctx, r.Client, &backupRestore)
+	if err != nil {
+		log.Error(err, "Failed to resolve backup revision")
+		r.mustSetFailed(ctx, req.NamespacedName, ReasonRevisionUnresolved, err)
+		return ctrl.Result{}, err
+	} else if ok {
+		source = revisionSource
+	}
+
+	storage, err := r.resolveStorage(ctx, &backupRestore, source)
+	if err != nil {
+		log.Error(err, "Failed to resolve storage location")
+		r.mustSetFailed(ctx, req.NamespacedName, ReasonStorageLocationUnavailable, err)
+		return ctrl.Result{}, err
+	}
+
+	overlay, err := prepareCredentials(ctx, r.Client, r.Scheme, &backupRestore,
+		credentialsSecretName("brs", backupRestore.Name), backupRestore.Spec.DatabasePasswordSecretRef, storage,
+	)
+	if err != nil {
+		log.Error(err, "Failed to prepare credentials")
+		r.mustSetFailed(ctx, req.NamespacedName, ReasonCredentialsUnavailable, err)
 		return ctrl.Result{}, err
 	}
 
-	controllerAddress, exists := dbControllers[backupRestore.Spec.DatabaseType]
-	if !exists {
-		err := ErrNotSupported(backupRestore.Spec.DatabaseType)
-		log.Error(err, "Make sure to update database-config cm")
+	revision, err := resolveRevision(ctx, r.Client, &backupRestore, source, storage)
+	if err != nil {
+		log.Error(err, "Failed to resolve backup revision")
+		r.mustSetFailed(ctx, req.NamespacedName, ReasonRevisionUnresolved, err)
 		return ctrl.Result{}, err
 	}
 
-	job, err := r.delegateToController(ctx, controllerAddress, &backupRestore)
+	overlay = overlay.withLogsOnError().withJobControls(backupRestore.Spec.JobControls).
+		withPodTemplate(backupRestore.Spec.PodTemplate)
+	if source.isClone(&backupRestore) {
+		overlay = overlay.withEnv(envSourceDbName, source.databaseName)
+	}
+	overlay = overlay.withBackupScope(backupRestore.Namespace, source.backupRequestName())
+
+	job, err := r.delegateToController(ctx, adapter, &backupRestore, revision, storage, overlay)
 	if errors.Is(err, ErrAlreadyExists) {
 		log.Info("Job for BackupRestore %s already exists", "name", backupRestore.Name)
 		jExists = true //nolint:ineffassign
 		return ctrl.Result{}, nil
 	} else if err != nil {
 		log.Error(err, "Cannot delegate to controller")
+		reason, conditions := ReasonJobFailed, []metav1.Condition{}
+		if condition, ok := adapterCondition(adapter.address, err); ok {
+			conditions = append(conditions, condition)
+			if condition.Status == metav1.ConditionFalse {
+				reason = condition.Reason
+			}
+		}
+		r.mustSetFailed(ctx, req.NamespacedName, reason, err, conditions...)
 		return ctrl.Result{}, err
 	}
 
 	if !jExists {
-		job.OwnerReferences = append(job.OwnerReferences, metav1.OwnerReference{
-			APIVersion:         backupRestore.APIVersion,
-			Kind:               backupRestore.Kind,
-			Name:               backupRestore.Name,
-			UID:                backupRestore.UID,
-			BlockOwnerDeletion: func() *bool { b := true; return &b }(),
-		})
+		// Controller reference lets the Job wake up reconciliation of BackupRestore.
+		if err := controllerutil.SetControllerReference(&backupRestore, job, r.Scheme); err != nil {
+			log.Error(err, "Failed to set owner of job")
+			r.mustSetFailed(ctx, req.NamespacedName, ReasonJobFailed, err)
+			return ctrl.Result{}, err
+		}
 
 		if err := r.Update(ctx, job); err != nil {
 			log.Error(err, "Failed to update job")
-			r.mustSetFailed(ctx, req.NamespacedName)
+			r.mustSetFailed(ctx, req.NamespacedName, ReasonJobFailed, err)
 			return ctrl.Result{}, err
 		}
 	}
 
-	backupRestore.Status.Status = SUCCESS
+	backupRestore.Status.JobName = job.Name
+	created := newCondition(TypeReady, metav1.ConditionUnknown, ReasonJobCreated,
+		fmt.Sprintf("Restore Job %s/%s is created", job.Namespace, job.Name))
+	setConditions(&backupRestore.Status.Conditions, backupRestore.Generation, adapterReachable(adapter.address), created)
+	recordCondition(r.Recorder, &backupRestore, adapterReachable(adapter.address))
+	recordCondition(r.Recorder, &backupRestore, created)
 	if err := r.Status().Update(ctx, &backupRestore); err != nil {
 		log.Error(err, "Unable to update BackupRestore status")
-		r.mustSetFailed(ctx, req.NamespacedName)
+		r.mustSetFailed(ctx, req.NamespacedName, ReasonStatusUpdateFailed, err)
 		return ctrl.Result{}, err
 	}
 
@@ -121,7 +207,111 @@ func (r *BackupRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	return ctrl.Result{}, nil
 }
 
-func (r *BackupRestoreReconciler) mustSetFailed(ctx context.Context, nsName types.NamespacedName) {
+// trackJob moves backupRestore through phases of its restore Job.
+// Success is reported only after the restorer exits with 0.
+func (r *BackupRestoreReconciler) trackJob(ctx context.Context, backupRestore *backupv1.BackupRestore) (ctrl.Result, error) {
+	nsName := client.ObjectKeyFromObject(backupRestore)
+	jobName := types.NamespacedName{Namespace: backupRestore.Namespace, Name: backupRestore.Status.JobName}
+	log := log.FromContext(ctx).WithValues("job", jobName)
+
+	var job batchv1.Job
+	err := r.Get(ctx, jobName, &job)
+	if apierrors.IsNotFound(err) {
+		err := fmt.Errorf("restore Job %s was deleted before it finished", jobName)
+		log.Error(err, "Restore Job is lost")
+		r.mustSetFailed(ctx, nsName, ReasonRestoreFailed, err)
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, "Unable to get restore Job")
+		return ctrl.Result{}, err
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(job.Namespace), client.MatchingLabels{batchv1.JobNameLabel: job.Name}); err != nil {
+		log.Error(err, "Unable to list pods of restore Job")
+		return ctrl.Result{}, err
+	}
+
+	status := &backupRestore.Status
+	status.Phase = jobPhase(&job, pods.Items)
+	status.StartTime = job.Status.StartTime
+	status.Message = podsMessage(pods.Items)
+	status.ObservedGeneration = backupRestore.Generation
+
+	var ready metav1.Condition
+	switch status.Phase {
+	case backupv1.RestorePhaseSucceeded:
+		status.Status = SUCCESS
+		status.CompletionTime = jobFinishTime(&job)
+		status.LastRestoreTime = status.CompletionTime
+		ready = newCondition(TypeReady, metav1.ConditionTrue, ReasonRestoreSucceeded,
+			fmt.Sprintf("Restore Job %s succeeded", jobName))
+	case backupv1.RestorePhaseFailed:
+		status.Status = FAILURE
+		status.CompletionTime = jobFinishTime(&job)
+		condition, _ := jobFinished(&job)
+		message := fmt.Sprintf("Restore Job %s failed: %s", jobName, condition.Message)
+		if status.Message != "" {
+			message += ": " + status.Message
+		}
+		ready = newCondition(TypeReady, metav1.ConditionFalse, ReasonRestoreFailed, message)
+	case backupv1.RestorePhaseRunning:
+		ready = newCondition(TypeReady, metav1.ConditionUnknown, ReasonRestoreRunning,
+			fmt.Sprintf("Restore Job %s is running", jobName))
+	default:
+		message := fmt.Sprintf("Restore Job %s is pending", jobName)
+		if status.Message != "" {
+			message += ": " + status.Message
+		}
+		ready = newCondition(TypeReady, metav1.ConditionUnknown, ReasonRestorePending, message)
+	}
+	setConditions(&status.Conditions, backupRestore.Generation, ready)
+	finished := status.Phase == backupv1.RestorePhaseSucceeded || status.Phase == backupv1.RestorePhaseFailed
+	if finished {
+		recordCondition(r.Recorder, backupRestore, ready)
+	}
+	if finished && status.NotifiedTime == nil {
+		r.notifyRestore(ctx, backupRestore, ready.Message)
+	}
+
+	if err := r.Status().Update(ctx, backupRestore); err != nil {
+		log.Error(err, "Unable to update BackupRestore status")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Restore Job is observed", "phase", status.Phase)
+	return ctrl.Result{}, nil
+}
+
+// notifyRestore sends RestoreCompleted with message to sinks of the BackupRequest
+// backups of which are restored, if it has any, and records it to status.
+func (r *BackupRestoreReconciler) notifyRestore(ctx context.Context, backupRestore *backupv1.BackupRestore, message string) {
+	log := log.FromContext(ctx)
+	backupRequest, err := notifiedBackupRequest(ctx, r, backupRestore)
+	if err != nil {
+		log.Error(err, "Unable to find BackupRequest to notify")
+		return
+	}
+	if backupRequest == nil {
+		return
+	}
+
+	n := newNotification(backupRequest, backupv1.NotificationRestoreCompleted, backupRestore.Name, message)
+	if err := deliver(ctx, r, r.Sender, backupRequest, n); err != nil {
+		log.Error(err, "Failed to send notification", "event", n.Event)
+	}
+	backupRestore.Status.NotifiedTime = &metav1.Time{Time: n.Time}
+}
+
+// mustSetFailed sets Ready condition to False with reason and message of cause.
+// Additional conditions are set alongside.
+func (r *BackupRestoreReconciler) mustSetFailed(
+	ctx context.Context,
+	nsName types.NamespacedName,
+	reason string,
+	cause error,
+	conditions ...metav1.Condition,
+) {
 	log := log.FromContext(ctx).WithValues("set-failed", nsName)
 	var backupRestore backupv1.BackupRestore
 	if err := r.Get(ctx, nsName, &backupRestore); err != nil {
@@ -133,6 +323,11 @@ func (r *BackupRestoreReconciler) mustSetFailed(ctx context.Context, nsName type
 	}
 
 	backupRestore.Status.Status = FAILURE
+	backupRestore.Status.Phase = backupv1.RestorePhaseFailed
+	backupRestore.Status.ObservedGeneration = backupRestore.Generation
+	ready := failedCondition(TypeReady, reason, cause)
+	setConditions(&backupRestore.Status.Conditions, backupRestore.Generation, append(conditions, ready)...)
+	recordCondition(r.Recorder, &backupRestore, ready)
 	log.Info("Setting BackupRestore failed")
 	if err := r.Status().Update(ctx, &backupRestore); err != nil {
 		log.Error(err, "Failed to set failed status on br")
@@ -142,15 +337,59 @@ func (r *BackupRestoreReconciler) mustSetFailed(ctx context.Context, nsName type
 	log.Info("Successfully updated BackupRequest status to failed state")
 }
 
-func (r *BackupRestoreReconciler) delegateToController(ctx context.Context, controllerAddress string, backupRestore *backupv1.BackupRestore) (*batchv1.Job, error) {
-	conn, err := grpc.NewClient(
-		controllerAddress,
-		grpc.WithTransportCredentials(
-			insecure.NewCredentials(),
-		),
-	)
+func (r *BackupRestoreReconciler) delegateToController(
+	ctx context.Context,
+	adapter databaseAdapter,
+	backupRestore *backupv1.BackupRestore,
+	revision string,
+	storage storageLocation,
+	overlay workloadOverlay,
+) (*batchv1.Job, error) {
+	dbPass := backupRestore.Spec.DatabasePass
+	if backupRestore.Spec.DatabasePasswordSecretRef != nil {
+		dbPass = ""
+	}
+	s3AccessKey, s3SecretKey := storage.plainKeys()
+
+	req := &pb.BackupRestore{
+		DbUri:          backupRestore.Spec.DatabaseURI,
+		DbPort:         int64(backupRestore.Spec.DatabasePort),
+		DbUser:         backupRestore.Spec.DatabaseUser,
+		DbPass:         dbPass,
+		DbName:         backupRestore.Spec.DatabaseName,
+		DatabaseType:   backupRestore.Spec.DatabaseType,
+		S3Endpoint:     storage.endpoint,
+		S3AccessKey:    s3AccessKey,
+		S3SecretKey:    s3SecretKey,
+		S3BucketName:   storage.bucketName,
+		BackupRevision: revision,
+		CoreAddr:       os.Getenv("CORE_ADDR"),
+	}
+
+	name, err := requestRestore(ctx, adapter, req, overlay)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to %s: %w", controllerAddress, err)
+		return nil, err
+	}
+
+	var job batchv1.Job
+	err = r.Get(ctx, name, &job)
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// requestRestore asks adapter to create a restore Job and returns its name.
+func requestRestore(
+	ctx context.Context,
+	adapter databaseAdapter,
+	req *pb.BackupRestore,
+	overlay workloadOverlay,
+) (types.NamespacedName, error) {
+	conn, err := adapter.dial()
+	if err != nil {
+		return types.NamespacedName{}, err
 	}
 	defer func() {
 		err := conn.Close()
@@ -161,48 +400,56 @@ func (r *BackupRestoreReconciler) delegateToController(ctx context.Context, cont
 
 	client := pb.NewBackupServiceClient(conn)
 
-	req := &pb.BackupRestore{
-		DbUri:          backupRestore.Spec.DatabaseURI,
-		DbPort:         int64(backupRestore.Spec.DatabasePort),
-		DbUser:         backupRestore.Spec.DatabaseUser,
-		DbPass:         backupRestore.Spec.DatabasePass,
-		DbName:         backupRestore.Spec.DatabaseName,
-		DatabaseType:   backupRestore.Spec.DatabaseType,
-		S3Endpoint:     backupRestore.Spec.S3Endpoint,
-		S3AccessKey:    backupRestore.Spec.S3AccessKey,
-		S3SecretKey:    backupRestore.Spec.S3SecretKey,
-		S3BucketName:   backupRestore.Spec.S3BucketName,
-		BackupRevision: backupRestore.Spec.BackupRevision,
-		CoreAddr:       os.Getenv("CORE_ADDR"),
+	if !overlay.isEmpty() {
+		patch, err := overlay.jobPatch()
+		if err != nil {
+			return types.NamespacedName{}, fmt.Errorf("failed to build overlay: %w", err)
+		}
+		ctx = withOverlay(ctx, patch)
 	}
 
 	resp, err := client.Restore(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to invoke restore method %s: %w", controllerAddress, err)
+		return types.NamespacedName{}, fmt.Errorf("failed to invoke restore method %s: %w", adapter.address, err)
 	}
 	if resp.Status == "Exists" {
-		return nil, ErrAlreadyExists
+		return types.NamespacedName{}, ErrAlreadyExists
 	}
 
 	log.FromContext(ctx).Info(resp.String())
 
-	name := types.NamespacedName{
+	return types.NamespacedName{
 		Namespace: resp.JobNamespace,
 		Name:      resp.JobName,
-	}
-	var job batchv1.Job
-	err = r.Get(ctx, name, &job)
-	if err != nil {
-		return nil, err
-	}
+	}, nil
+}
 
-	return &job, nil
+// resolveStorage resolves S3 storage of backupRestore.
+// Storage of source BackupRequest is used if backupRestore sets none.
+func (r *BackupRestoreReconciler) resolveStorage(
+	ctx context.Context,
+	backupRestore *backupv1.BackupRestore,
+	source restoreSource,
+) (storageLocation, error) {
+	if source.backupRequest != nil && !hasStorage(backupRestore) {
+		return resolveBackupRequestStorage(ctx, r, source.backupRequest)
+	}
+	spec := backupRestore.Spec
+	return resolveStorageLocation(ctx, r, spec.StorageLocationName, storageLocation{
+		endpoint:             spec.S3Endpoint,
+		bucketName:           spec.S3BucketName,
+		accessKey:            spec.S3AccessKey,
+		secretKey:            spec.S3SecretKey,
+		credentialsRef:       spec.S3CredentialsSecretRef,
+		credentialsNamespace: backupRestore.Namespace,
+	})
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *BackupRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&backupv1.BackupRestore{}).
+		Owns(&batchv1.Job{}).
 		Named("backuprestore").
 		Complete(r)
 }
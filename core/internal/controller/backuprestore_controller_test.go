@@ -22,7 +22,9 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -69,8 +71,9 @@ var _ = Describe("BackupRestore Controller", func() {
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &BackupRestoreReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -80,5 +83,40 @@ var _ = Describe("BackupRestore Controller", func() {
 			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
+
+		It("should fail if restore Job is deleted before it finished", func() {
+			nsName := types.NamespacedName{Name: "lost-job", Namespace: "default"}
+			brs := &backupv1.BackupRestore{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      nsName.Name,
+					Namespace: nsName.Namespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, brs)).To(Succeed())
+			brs.Status.Status = IN_PROGRESS
+			brs.Status.Phase = backupv1.RestorePhaseRunning
+			brs.Status.JobName = "restore-lost"
+			Expect(k8sClient.Status().Update(ctx, brs)).To(Succeed())
+
+			controllerReconciler := &BackupRestoreReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: nsName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, nsName, brs)).To(Succeed())
+			Expect(brs.Status.Status).To(Equal(FAILURE))
+			Expect(brs.Status.Phase).To(Equal(backupv1.RestorePhaseFailed))
+			Expect(brs.Status.LastRestoreTime).To(BeNil())
+			ready := meta.FindStatusCondition(brs.Status.Conditions, TypeReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Reason).To(Equal(ReasonRestoreFailed))
+			Expect(ready.Message).To(ContainSubstring("restore-lost"))
+			Expect(controllerReconciler.Recorder.(*record.FakeRecorder).Events).To(Receive(HavePrefix("Warning RestoreFailed ")))
+
+			Expect(k8sClient.Delete(ctx, brs)).To(Succeed())
+		})
 	})
 })
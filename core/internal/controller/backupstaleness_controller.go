@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	"github.com/oiler-backup/core/core/internal/notify"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// BackupStalenessReconciler sends BackupStale about BackupRequests
+// which had no successful backup for longer than notifications allow.
+type BackupStalenessReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Sender sends BackupStale notifications.
+	Sender notify.Sender
+}
+
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=backuprequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=backuprequests/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile waits until the last successful backup of BackupRequest becomes stale and
+// sends BackupStale once per last backup. Suspended BackupRequests are not checked.
+func (r *BackupStalenessReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx).WithValues("backuprequest", req.NamespacedName)
+	var backupRequest backupv1.BackupRequest
+	err := r.Get(ctx, req.NamespacedName, &backupRequest)
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, "Unable to get BackupRequest object")
+		return ctrl.Result{}, err
+	}
+
+	spec := backupRequest.Spec.Notifications
+	if !notifies(spec, backupv1.NotificationBackupStale) || spec.StaleAfter == nil ||
+		backupRequest.Spec.Suspend || !backupRequest.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+	since, deadline := staleDeadline(&backupRequest, spec.StaleAfter.Duration)
+	if now.Before(deadline) {
+		return ctrl.Result{RequeueAfter: deadline.Sub(now)}, nil
+	}
+
+	message := fmt.Sprintf("No successful backup for %s since %s", spec.StaleAfter.Duration, since.UTC().Format(time.RFC3339))
+	n := newNotification(&backupRequest, backupv1.NotificationBackupStale, "", message)
+	if !sendNotification(ctx, r, r.Sender, &backupRequest, &backupRequest.Status, since.UTC().Format(time.RFC3339), n) {
+		return ctrl.Result{}, nil
+	}
+	if err := r.Status().Update(ctx, &backupRequest); err != nil {
+		log.Error(err, "Unable to update BackupRequest status")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Backups are stale", "since", since)
+	return ctrl.Result{}, nil
+}
+
+// staleDeadline returns time of the last successful backup of backupRequest, or its creation
+// if there is none, and when backups become stale after it.
+func staleDeadline(backupRequest *backupv1.BackupRequest, staleAfter time.Duration) (since, deadline time.Time) {
+	since = backupRequest.CreationTimestamp.Time
+	if last := backupRequest.Status.LastBackupTime; last != nil {
+		since = last.Time
+	}
+	return since, since.Add(staleAfter)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Status updates are watched too, so a new backup resets the deadline.
+func (r *BackupStalenessReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&backupv1.BackupRequest{}).
+		Named("backupstaleness").
+		Complete(r)
+}
@@ -0,0 +1,337 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	pb "github.com/oiler-backup/base/proto"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+// BackupVerificationReconciler reconciles a BackupVerification object
+type BackupVerificationReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=backupverifications,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=backupverifications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=backupverifications/finalizers,verbs=update
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=backuprequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=backupstoragelocations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=databaseadapters,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="batch",resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+// Reconcile starts a verification Job when it is due by schedule and records
+// results of the Job once it finishes. Only one verification runs at a time.
+func (r *BackupVerificationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx).WithValues("backupverification", req.NamespacedName)
+	var verification backupv1.BackupVerification
+
+	err := r.Get(ctx, req.NamespacedName, &verification)
+	if apierrors.IsNotFound(err) {
+		forgetVerification(req.Namespace, req.Name)
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, "Unable to get BackupVerification object")
+		return ctrl.Result{}, err
+	}
+
+	if verification.Status.JobName != "" {
+		return r.trackVerification(ctx, &verification)
+	}
+
+	status := &verification.Status
+	status.ObservedGeneration = verification.Generation
+	if meta.FindStatusCondition(status.Conditions, TypeLastVerificationPassed) == nil {
+		setConditions(&status.Conditions, verification.Generation,
+			newCondition(TypeLastVerificationPassed, metav1.ConditionUnknown, ReasonNoVerificationYet, "No verification has run yet"),
+		)
+	}
+
+	cronSchedule, err := verificationSchedule(&verification)
+	if err != nil {
+		log.Error(err, "Unable to parse schedule")
+		status.NextScheduleTime = nil
+		setConditions(&status.Conditions, verification.Generation, failedCondition(TypeReady, ReasonInvalidSchedule, err))
+		return ctrl.Result{}, r.Status().Update(ctx, &verification)
+	}
+
+	if verification.Spec.Suspend {
+		status.NextScheduleTime = nil
+		setConditions(&status.Conditions, verification.Generation,
+			newCondition(TypeReady, metav1.ConditionTrue, ReasonSuspended, "Verifications are suspended"),
+		)
+		return ctrl.Result{}, r.Status().Update(ctx, &verification)
+	}
+
+	now := time.Now()
+	if next := nextVerification(&verification, cronSchedule); now.Before(next) {
+		status.NextScheduleTime = &metav1.Time{Time: next}
+		setConditions(&status.Conditions, verification.Generation,
+			newCondition(TypeReady, metav1.ConditionTrue, ReasonReconciled, "Verifications are scheduled"),
+		)
+		if err := r.Status().Update(ctx, &verification); err != nil {
+			log.Error(err, "Unable to update BackupVerification status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+	}
+
+	// Missed verifications are not caught up: one runs now and the next one is due by schedule.
+	next := cronSchedule.Next(now)
+	status.LastScheduleTime = &metav1.Time{Time: now}
+	status.NextScheduleTime = &metav1.Time{Time: next}
+
+	jobName, conditions, err := r.startVerification(ctx, &verification)
+	if err != nil {
+		log.Error(err, "Unable to start verification")
+		finishVerification(&verification, backupv1.VerificationRun{
+			CompletionTime: &metav1.Time{Time: now},
+			Message:        fmt.Sprintf("failed to start verification: %s", err),
+		})
+		setConditions(&status.Conditions, verification.Generation, conditions...)
+	} else {
+		log.Info("Verification is started", "job", jobName)
+		status.JobName = jobName
+		setConditions(&status.Conditions, verification.Generation, conditions...)
+	}
+
+	if err := r.Status().Update(ctx, &verification); err != nil {
+		log.Error(err, "Unable to update BackupVerification status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+}
+
+// startVerification asks adapter to restore a backup of BackupRequest of verification
+// into a throwaway database running next to restorer. It returns a name of the Job and
+// conditions describing the outcome.
+func (r *BackupVerificationReconciler) startVerification(
+	ctx context.Context,
+	verification *backupv1.BackupVerification,
+) (string, []metav1.Condition, error) {
+	failed := func(reason string, err error, conditions ...metav1.Condition) (string, []metav1.Condition, error) {
+		return "", append(conditions, failedCondition(TypeReady, reason, err)), err
+	}
+
+	var backupRequest backupv1.BackupRequest
+	brName := types.NamespacedName{Namespace: verification.Namespace, Name: verification.Spec.BackupRequestName}
+	if err := r.Get(ctx, brName, &backupRequest); err != nil {
+		return failed(ReasonSourceUnavailable, fmt.Errorf("unable to get BackupRequest %s: %w", brName, err))
+	}
+	dbSpec := backupRequest.Spec.DbSpec
+
+	database, ok := throwawayDatabases[dbSpec.DbType]
+	if !ok {
+		return failed(ReasonUnsupportedDatabase, ErrNotSupported(dbSpec.DbType))
+	}
+
+	adapter, err := resolveAdapter(ctx, r, dbSpec.DbType, appCfg.OperatorNamespace)
+	if err == nil {
+		err = adapter.supports(backupv1.AdapterCapabilityRestore)
+	}
+	if isUnsupported(err) {
+		return failed(ReasonUnsupportedDatabase, err, failedCondition(TypeAdapterReachable, ReasonUnsupportedDatabase, err))
+	} else if err != nil {
+		return failed(ReasonDatabaseConfigUnavailable, err)
+	}
+
+	storage, err := resolveBackupRequestStorage(ctx, r, &backupRequest)
+	if err != nil {
+		return failed(ReasonStorageLocationUnavailable, err)
+	}
+
+	password, err := randomPassword()
+	if err != nil {
+		return failed(ReasonCredentialsUnavailable, fmt.Errorf("failed to generate password: %w", err))
+	}
+	secretName := credentialsSecretName("bv", verification.Name)
+	overlay, err := prepareVerificationCredentials(ctx, r.Client, r.Scheme, verification, secretName, password, storage)
+	if err != nil {
+		return failed(ReasonCredentialsUnavailable, err)
+	}
+
+	checks, err := checksEnv(verification.Spec.Checks)
+	if err != nil {
+		return failed(ReasonJobFailed, err)
+	}
+	overlay = overlay.withLogsOnError().
+		withEnv(envChecks, checks).
+		withBackupScope(verification.Namespace, backupRequest.Name).
+		withSidecar(database.container(verification.Spec.DatabaseImage, dbSpec.DbName, secretName))
+	if deadline := verification.Spec.ActiveDeadlineSeconds; deadline != nil {
+		overlay = overlay.withActiveDeadline(*deadline)
+	}
+
+	revision := verification.Spec.BackupRevision
+	if revision == "" {
+		revision = backupv1.RevisionLatest
+	}
+	s3AccessKey, s3SecretKey := storage.plainKeys()
+
+	r.deletePreviousJob(ctx, verification)
+
+	name, err := requestRestore(ctx, adapter, &pb.BackupRestore{
+		DbUri:          throwawayHost,
+		DbPort:         int64(database.port),
+		DbUser:         database.user,
+		DbName:         dbSpec.DbName,
+		DatabaseType:   dbSpec.DbType,
+		S3Endpoint:     storage.endpoint,
+		S3AccessKey:    s3AccessKey,
+		S3SecretKey:    s3SecretKey,
+		S3BucketName:   storage.bucketName,
+		BackupRevision: revision,
+		CoreAddr:       os.Getenv("CORE_ADDR"),
+	}, overlay)
+	if err != nil {
+		var conditions []metav1.Condition
+		reason := ReasonJobFailed
+		if condition, ok := adapterCondition(adapter.address, err); ok {
+			conditions = append(conditions, condition)
+			if condition.Status == metav1.ConditionFalse {
+				reason = condition.Reason
+			}
+		}
+		return failed(reason, err, conditions...)
+	}
+
+	var job batchv1.Job
+	if err := r.Get(ctx, name, &job); err != nil {
+		return failed(ReasonJobFailed, err, adapterReachable(adapter.address))
+	}
+	if job.Labels == nil {
+		job.Labels = map[string]string{}
+	}
+	job.Labels[verificationJobLabel] = verification.Name
+	// Controller reference lets the Job wake up reconciliation of BackupVerification.
+	if err := controllerutil.SetControllerReference(verification, &job, r.Scheme); err != nil {
+		return failed(ReasonJobFailed, err, adapterReachable(adapter.address))
+	}
+	if err := r.Update(ctx, &job); err != nil {
+		return failed(ReasonJobFailed, fmt.Errorf("failed to set owner of Job %s: %w", name, err),
+			adapterReachable(adapter.address))
+	}
+
+	return job.Name, []metav1.Condition{
+		adapterReachable(adapter.address),
+		newCondition(TypeReady, metav1.ConditionTrue, ReasonVerificationRunning,
+			fmt.Sprintf("Verification Job %s is running", job.Name)),
+	}, nil
+}
+
+// deletePreviousJob deletes Job of the last run, which is kept until the next run for its logs.
+func (r *BackupVerificationReconciler) deletePreviousJob(ctx context.Context, verification *backupv1.BackupVerification) {
+	lastRun := verification.Status.LastRun
+	if lastRun == nil || lastRun.JobName == "" {
+		return
+	}
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: verification.Namespace, Name: lastRun.JobName}}
+	err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if client.IgnoreNotFound(err) != nil {
+		log.FromContext(ctx).Error(err, "Unable to delete previous verification Job", "job", lastRun.JobName)
+	}
+}
+
+// trackVerification records results of verification Job once it finishes.
+func (r *BackupVerificationReconciler) trackVerification(ctx context.Context, verification *backupv1.BackupVerification) (ctrl.Result, error) {
+	jobName := types.NamespacedName{Namespace: verification.Namespace, Name: verification.Status.JobName}
+	log := log.FromContext(ctx).WithValues("job", jobName)
+
+	var run backupv1.VerificationRun
+	var job batchv1.Job
+	err := r.Get(ctx, jobName, &job)
+	if apierrors.IsNotFound(err) {
+		run = backupv1.VerificationRun{
+			JobName:        jobName.Name,
+			CompletionTime: &metav1.Time{Time: time.Now()},
+			Message:        fmt.Sprintf("verification Job %s was deleted before it finished", jobName),
+		}
+	} else if err != nil {
+		log.Error(err, "Unable to get verification Job")
+		return ctrl.Result{}, err
+	} else {
+		var pods corev1.PodList
+		if err := r.List(ctx, &pods, client.InNamespace(job.Namespace), client.MatchingLabels{batchv1.JobNameLabel: job.Name}); err != nil {
+			log.Error(err, "Unable to list pods of verification Job")
+			return ctrl.Result{}, err
+		}
+		var finished bool
+		if run, finished = verificationRun(&job, pods.Items, verification.Spec.Checks); !finished {
+			return ctrl.Result{}, nil
+		}
+	}
+
+	finishVerification(verification, run)
+	verification.Status.JobName = ""
+	setConditions(&verification.Status.Conditions, verification.Generation,
+		newCondition(TypeReady, metav1.ConditionTrue, ReasonReconciled, "Verifications are scheduled"),
+	)
+	if err := r.Status().Update(ctx, verification); err != nil {
+		log.Error(err, "Unable to update BackupVerification status")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Verification finished", "passed", run.Passed)
+	return ctrl.Result{}, nil
+}
+
+// finishVerification records run as the last one of verification and exports it to metrics.
+func finishVerification(verification *backupv1.BackupVerification, run backupv1.VerificationRun) {
+	status := &verification.Status
+	status.LastRun = &run
+
+	condition := newCondition(TypeLastVerificationPassed, metav1.ConditionTrue, ReasonVerificationPassed,
+		fmt.Sprintf("Backup %s is restored and passed %d checks", run.Key, len(run.Checks)))
+	if run.Passed {
+		status.LastPassedTime = run.CompletionTime
+	} else {
+		condition = newCondition(TypeLastVerificationPassed, metav1.ConditionFalse, ReasonVerificationFailed, run.Message)
+	}
+	setConditions(&status.Conditions, verification.Generation, condition)
+
+	recordVerification(verification, run)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BackupVerificationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&backupv1.BackupVerification{}).
+		Owns(&batchv1.Job{}).
+		Named("backupverification").
+		Complete(r)
+}
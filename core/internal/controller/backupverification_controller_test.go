@@ -0,0 +1,191 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/oiler-backup/core/common/schedule"
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+var _ = Describe("BackupVerification Controller", func() {
+	ctx := context.Background()
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(backupv1.AddToScheme(scheme)).To(Succeed())
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	})
+
+	verificationCreatedAt := func(createdAt time.Time) *backupv1.BackupVerification {
+		return &backupv1.BackupVerification{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "orders",
+				Namespace:         "team-a",
+				UID:               "bv-uid",
+				CreationTimestamp: metav1.NewTime(createdAt),
+			},
+			Spec: backupv1.BackupVerificationSpec{
+				BackupRequestName: "orders",
+				Schedule:          "0 6 * * *",
+			},
+		}
+	}
+	reconcileVerification := func(verification *backupv1.BackupVerification, objs ...client.Object) (reconcile.Result, *backupv1.BackupVerification) {
+		reconciler := &BackupVerificationReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(append(objs, verification)...).WithStatusSubresource(verification).Build(),
+			Scheme: scheme,
+		}
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(verification)})
+		Expect(err).NotTo(HaveOccurred())
+
+		var got backupv1.BackupVerification
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(verification), &got)).To(Succeed())
+		return result, &got
+	}
+
+	It("should wait for the next verification by schedule", func() {
+		result, verification := reconcileVerification(verificationCreatedAt(time.Now()))
+
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(result.RequeueAfter).To(BeNumerically("<=", 24*time.Hour))
+		Expect(verification.Status.NextScheduleTime).NotTo(BeNil())
+		Expect(verification.Status.JobName).To(BeEmpty())
+		Expect(meta.IsStatusConditionTrue(verification.Status.Conditions, TypeReady)).To(BeTrue())
+		condition := meta.FindStatusCondition(verification.Status.Conditions, TypeLastVerificationPassed)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal(ReasonNoVerificationYet))
+	})
+
+	It("should not schedule suspended verifications", func() {
+		verification := verificationCreatedAt(time.Now().Add(-48 * time.Hour))
+		verification.Spec.Suspend = true
+		result, verification := reconcileVerification(verification)
+
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(verification.Status.LastScheduleTime).To(BeNil())
+		Expect(meta.FindStatusCondition(verification.Status.Conditions, TypeReady).Reason).To(Equal(ReasonSuspended))
+	})
+
+	It("should report invalid schedule", func() {
+		verification := verificationCreatedAt(time.Now())
+		verification.Spec.Schedule = "every day"
+		_, verification = reconcileVerification(verification)
+
+		condition := meta.FindStatusCondition(verification.Status.Conditions, TypeReady)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(ReasonInvalidSchedule))
+	})
+
+	It("should expand hashed schedule with namespace and name of verification", func() {
+		createdAt := time.Now()
+		verification := verificationCreatedAt(createdAt)
+		verification.Spec.Schedule = "H H(0-5) * * *"
+		_, verification = reconcileVerification(verification)
+
+		expanded, err := schedule.Expand("H H(0-5) * * *", "team-a/orders")
+		Expect(err).NotTo(HaveOccurred())
+		expected, err := cron.ParseStandard(expanded)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(meta.IsStatusConditionTrue(verification.Status.Conditions, TypeReady)).To(BeTrue())
+		Expect(verification.Status.NextScheduleTime.Time).To(BeTemporally("==", expected.Next(createdAt)))
+	})
+
+	It("should record failed verification if it cannot start", func() {
+		result, verification := reconcileVerification(verificationCreatedAt(time.Now().Add(-48 * time.Hour)))
+
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(verification.Status.LastScheduleTime).NotTo(BeNil())
+		Expect(verification.Status.JobName).To(BeEmpty())
+		Expect(verification.Status.LastRun).NotTo(BeNil())
+		Expect(verification.Status.LastRun.Passed).To(BeFalse())
+		Expect(verification.Status.LastRun.Message).To(ContainSubstring("unable to get BackupRequest team-a/orders"))
+
+		ready := meta.FindStatusCondition(verification.Status.Conditions, TypeReady)
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal(ReasonSourceUnavailable))
+		passed := meta.FindStatusCondition(verification.Status.Conditions, TypeLastVerificationPassed)
+		Expect(passed.Status).To(Equal(metav1.ConditionFalse))
+		Expect(passed.Reason).To(Equal(ReasonVerificationFailed))
+	})
+
+	It("should record results of finished verification Job", func() {
+		verification := verificationCreatedAt(time.Now().Add(-48 * time.Hour))
+		verification.Status.JobName = "restore-1a2b3c4d-orders"
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "restore-1a2b3c4d-orders", Namespace: "team-a"},
+			Status: batchv1.JobStatus{
+				StartTime:      &metav1.Time{Time: time.Now().Add(-time.Minute)},
+				CompletionTime: &metav1.Time{Time: time.Now()},
+				Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+			},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "restore-1a2b3c4d-orders-x7k2p",
+				Namespace: "team-a",
+				Labels:    map[string]string{batchv1.JobNameLabel: job.Name},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: restorerContainerName,
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+						Message: `{"key":"orders/2025-05-01-backup.sql","checks":[{"name":"orders","passed":true,"rows":3,"duration":"2ms"}]}`,
+					}},
+				}},
+			},
+		}
+		_, verification = reconcileVerification(verification, job, pod)
+
+		Expect(verification.Status.JobName).To(BeEmpty())
+		Expect(verification.Status.LastRun).NotTo(BeNil())
+		Expect(verification.Status.LastRun.Passed).To(BeTrue())
+		Expect(verification.Status.LastRun.JobName).To(Equal(job.Name))
+		Expect(verification.Status.LastRun.Checks).To(HaveLen(1))
+		Expect(verification.Status.LastPassedTime).NotTo(BeNil())
+		passed := meta.FindStatusCondition(verification.Status.Conditions, TypeLastVerificationPassed)
+		Expect(passed.Status).To(Equal(metav1.ConditionTrue))
+		Expect(passed.Message).To(Equal("Backup orders/2025-05-01-backup.sql is restored and passed 1 checks"))
+	})
+
+	It("should fail verification whose Job was deleted", func() {
+		verification := verificationCreatedAt(time.Now().Add(-48 * time.Hour))
+		verification.Status.JobName = "restore-1a2b3c4d-orders"
+		_, verification = reconcileVerification(verification)
+
+		Expect(verification.Status.JobName).To(BeEmpty())
+		Expect(verification.Status.LastRun.Passed).To(BeFalse())
+		Expect(verification.Status.LastRun.Message).To(ContainSubstring("was deleted before it finished"))
+	})
+})
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Condition types of BackupRequest, BackupRestore, BackupVerification and DatabaseAdapter.
+const (
+	// TypeReady summarizes other conditions, so `kubectl wait --for=condition=Ready` can be used.
+	TypeReady = "Ready"
+	// TypeAdapterReachable tells whether the database adapter answered the last call.
+	TypeAdapterReachable = "AdapterReachable"
+	// TypeScheduleApplied tells whether the CronJob matches spec of BackupRequest.
+	TypeScheduleApplied = "ScheduleApplied"
+	// TypeLastBackupSucceeded tells whether the last scheduled backup succeeded.
+	TypeLastBackupSucceeded = "LastBackupSucceeded"
+	// TypeLastVerificationPassed tells whether the last verification restored the backup and passed its checks.
+	TypeLastVerificationPassed = "LastVerificationPassed"
+	// TypeReachable tells whether DatabaseAdapter answered the last probe.
+	TypeReachable = "Reachable"
+)
+
+// Condition reasons.
+const (
+	ReasonReconciling                = "Reconciling"
+	ReasonReconciled                 = "Reconciled"
+	ReasonSuspended                  = "Suspended"
+	ReasonDatabaseConfigUnavailable  = "DatabaseConfigUnavailable"
+	ReasonUnsupportedDatabase        = "UnsupportedDatabase"
+	ReasonStorageLocationUnavailable = "StorageLocationUnavailable"
+	ReasonCredentialsUnavailable     = "CredentialsUnavailable"
+	ReasonRevisionUnresolved         = "RevisionUnresolved"
+	ReasonSourceUnavailable          = "SourceUnavailable"
+	ReasonAdapterResponded           = "AdapterResponded"
+	ReasonAdapterUnreachable         = "AdapterUnreachable"
+	ReasonCronJobCreated             = "CronJobCreated"
+	ReasonCronJobUpdated             = "CronJobUpdated"
+	ReasonCronJobFailed              = "CronJobFailed"
+	ReasonJobCreated                 = "JobCreated"
+	ReasonJobFailed                  = "JobFailed"
+	ReasonRestorePending             = "RestorePending"
+	ReasonRestoreRunning             = "RestoreRunning"
+	ReasonRestoreSucceeded           = "RestoreSucceeded"
+	ReasonRestoreFailed              = "RestoreFailed"
+	ReasonStatusUpdateFailed         = "StatusUpdateFailed"
+	ReasonNoBackupYet                = "NoBackupYet"
+	ReasonBackupSucceeded            = "BackupSucceeded"
+	ReasonBackupFailed               = "BackupFailed"
+	ReasonPurging                    = "Purging"
+	ReasonPurgeFailed                = "PurgeFailed"
+	ReasonInvalidSchedule            = "InvalidSchedule"
+	ReasonNoVerificationYet          = "NoVerificationYet"
+	ReasonVerificationRunning        = "VerificationRunning"
+	ReasonVerificationPassed         = "VerificationPassed"
+	ReasonVerificationFailed         = "VerificationFailed"
+)
+
+func newCondition(conditionType string, status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// failedCondition reports conditionType as False with err as a message.
+func failedCondition(conditionType, reason string, err error) metav1.Condition {
+	return newCondition(conditionType, metav1.ConditionFalse, reason, err.Error())
+}
+
+// setConditions sets newConditions observed at generation.
+// LastTransitionTime is changed only if status of condition changes.
+func setConditions(conditions *[]metav1.Condition, generation int64, newConditions ...metav1.Condition) {
+	for _, condition := range newConditions {
+		condition.ObservedGeneration = generation
+		meta.SetStatusCondition(conditions, condition)
+	}
+}
+
+// recordCondition emits an Event of obj with reason and message of condition,
+// so `kubectl describe` shows what the conditions went through. False conditions are warnings.
+func recordCondition(recorder record.EventRecorder, obj runtime.Object, condition metav1.Condition) {
+	eventType := corev1.EventTypeNormal
+	if condition.Status == metav1.ConditionFalse {
+		eventType = corev1.EventTypeWarning
+	}
+	recorder.Event(obj, eventType, condition.Reason, condition.Message)
+}
+
+// adapterCondition returns AdapterReachable condition for err returned by adapter.
+// If err did not come from the gRPC call, ok is false.
+func adapterCondition(controllerAddress string, err error) (condition metav1.Condition, ok bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return metav1.Condition{}, false
+	}
+
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return failedCondition(TypeAdapterReachable, ReasonAdapterUnreachable, err), true
+	default:
+		return adapterReachable(controllerAddress), true
+	}
+}
+
+func adapterReachable(controllerAddress string) metav1.Condition {
+	return newCondition(TypeAdapterReachable, metav1.ConditionTrue, ReasonAdapterResponded,
+		fmt.Sprintf("Adapter %s responded", controllerAddress))
+}
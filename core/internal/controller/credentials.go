@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	envDbPassword = "DB_PASSWORD"
+)
+
+var (
+	ErrSecretKeyNotFound = func(key, name string) error { return fmt.Errorf("key %s not found in secret %s", key, name) }
+)
+
+// resolveSecretKey reads value referenced by ref.
+// Secret is looked up in defaultNamespace if ref has no namespace.
+func resolveSecretKey(ctx context.Context, r client.Reader, ref *backupv1.SecretKeyReference, defaultNamespace string) ([]byte, error) {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	var secret corev1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, &secret); err != nil {
+		return nil, fmt.Errorf("unable to get Secret %s/%s: %w", namespace, ref.Name, err)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, ErrSecretKeyNotFound(ref.Key, ref.Name)
+	}
+
+	return value, nil
+}
+
+// credentialsSecretName returns name of a Secret managed by operator for owner of kind.
+func credentialsSecretName(kind, owner string) string {
+	return fmt.Sprintf("oiler-%s-%s-credentials", kind, owner)
+}
+
+// syncCredentialsSecret creates or updates Secret name in namespace with data
+// and makes owner its controller. Pods generated by adapters read credentials from it.
+func syncCredentialsSecret(
+	ctx context.Context,
+	c client.Client,
+	scheme *runtime.Scheme,
+	owner client.Object,
+	name, namespace string,
+	data map[string][]byte,
+) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, c, secret, func() error {
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = data
+		return controllerutil.SetControllerReference(owner, secret, scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to sync Secret %s/%s: %w", namespace, name, err)
+	}
+
+	return nil
+}
+
+// prepareCredentials resolves password referenced by dbPassRef and S3 keys of storage
+// and copies them to a Secret secretName managed by operator in namespace of owner.
+// Returns overlay which places workload next to owner and makes it read credentials
+// from this Secret instead of plaintext envs.
+func prepareCredentials(
+	ctx context.Context,
+	c client.Client,
+	scheme *runtime.Scheme,
+	owner client.Object,
+	secretName string,
+	dbPassRef *backupv1.SecretKeyReference,
+	storage storageLocation,
+) (workloadOverlay, error) {
+	overlay := workloadOverlay{}.inNamespace(owner.GetNamespace())
+	data := map[string][]byte{}
+
+	if dbPassRef != nil {
+		pass, err := resolveSecretKey(ctx, c, dbPassRef, owner.GetNamespace())
+		if err != nil {
+			return overlay, err
+		}
+		data[envDbPassword] = pass
+	}
+
+	s3Data, err := storage.secretData(ctx, c)
+	if err != nil {
+		return overlay, err
+	}
+	maps.Copy(data, s3Data)
+
+	if len(data) == 0 {
+		return overlay, nil
+	}
+
+	err = syncCredentialsSecret(ctx, c, scheme, owner, secretName, owner.GetNamespace(), data)
+	if err != nil {
+		return overlay, err
+	}
+
+	return overlay.withSecretEnv(secretName, slices.Sorted(maps.Keys(data))...), nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	defaultProbeInterval = 30 * time.Second
+	probeTimeout         = 5 * time.Second
+)
+
+// DatabaseAdapterReconciler probes DatabaseAdapters and reports whether they are reachable.
+type DatabaseAdapterReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=databaseadapters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=backup.oiler.backup,resources=databaseadapters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile probes DatabaseAdapter every spec.probeInterval. Events are emitted
+// only when the adapter becomes reachable or unreachable.
+func (r *DatabaseAdapterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx).WithValues("databaseadapter", req.Name)
+	var adapter backupv1.DatabaseAdapter
+	err := r.Get(ctx, req.NamespacedName, &adapter)
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, "Unable to get DatabaseAdapter object")
+		return ctrl.Result{}, err
+	}
+
+	var reachable metav1.Condition
+	resolved, err := newDatabaseAdapter(ctx, r, &adapter)
+	if err != nil {
+		reachable = failedCondition(TypeReachable, ReasonCredentialsUnavailable, err)
+	} else {
+		reachable = probeAdapter(ctx, resolved)
+	}
+
+	previous := meta.FindStatusCondition(adapter.Status.Conditions, TypeReachable)
+	if previous == nil || previous.Status != reachable.Status || previous.Reason != reachable.Reason {
+		recordCondition(r.Recorder, &adapter, reachable)
+	}
+	setConditions(&adapter.Status.Conditions, adapter.Generation, reachable)
+	adapter.Status.LastProbeTime = &metav1.Time{Time: time.Now()}
+	adapter.Status.ObservedGeneration = adapter.Generation
+	if err := r.Status().Update(ctx, &adapter); err != nil {
+		log.Error(err, "Unable to update DatabaseAdapter status")
+		return ctrl.Result{}, err
+	}
+
+	interval := defaultProbeInterval
+	if adapter.Spec.ProbeInterval != nil && adapter.Spec.ProbeInterval.Duration > 0 {
+		interval = adapter.Spec.ProbeInterval.Duration
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// probeAdapter calls the gRPC health service of adapter. Adapters without
+// the health service are reachable if they answer at all.
+func probeAdapter(ctx context.Context, adapter databaseAdapter) metav1.Condition {
+	conn, err := adapter.dial()
+	if err != nil {
+		return failedCondition(TypeReachable, ReasonAdapterUnreachable, err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to close connection to adapter", "address", adapter.address)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	switch {
+	case status.Code(err) == codes.Unimplemented:
+		// The adapter answered, it just has no health service.
+	case err != nil:
+		return failedCondition(TypeReachable, ReasonAdapterUnreachable,
+			fmt.Errorf("adapter %s is unreachable: %w", adapter.address, err))
+	case resp.GetStatus() != healthpb.HealthCheckResponse_SERVING:
+		return newCondition(TypeReachable, metav1.ConditionFalse, ReasonAdapterUnreachable,
+			fmt.Sprintf("Adapter %s is %s", adapter.address, resp.GetStatus()))
+	}
+	return newCondition(TypeReachable, metav1.ConditionTrue, ReasonAdapterResponded,
+		fmt.Sprintf("Adapter %s responded", adapter.address))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Status updates are ignored, the next probe is requeued instead.
+func (r *DatabaseAdapterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&backupv1.DatabaseAdapter{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("databaseadapter").
+		Complete(r)
+}
@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	serverscommon "github.com/oiler-backup/core/common/servers/backup"
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// backupRequestFinalizer holds BackupRequest until its backups are handled by DeletionPolicy.
+	backupRequestFinalizer = "backup.oiler.backup/cleanup"
+	// purgeJobLabel marks a Job purging backups of BackupRequest named by the value.
+	purgeJobLabel = "backup.oiler.backup/purge"
+)
+
+// mustPurge tells whether backups of deleted backupRequest are purged before it is released.
+// Nothing is purged if CronJob was never created.
+func mustPurge(backupRequest *backupv1.BackupRequest) bool {
+	return backupRequest.Spec.DeletionPolicy == backupv1.DeletionPolicyDelete &&
+		backupRequest.Status.CronJobData.Name != ""
+}
+
+func isBeingDeleted(obj client.Object) bool {
+	return !obj.GetDeletionTimestamp().IsZero()
+}
+
+// isPurgeJob tells whether obj is a Job purging backups.
+func isPurgeJob(obj client.Object) bool {
+	_, ok := obj.GetLabels()[purgeJobLabel]
+	return ok
+}
+
+func (r *BackupRequestReconciler) removeFinalizer(ctx context.Context, backupRequest *backupv1.BackupRequest) error {
+	if !controllerutil.RemoveFinalizer(backupRequest, backupRequestFinalizer) {
+		return nil
+	}
+	return r.Update(ctx, backupRequest)
+}
+
+// startPurge asks adapter to delete CronJob of backupRequest and start a Job
+// purging its backups. The Job is recorded to status of backupRequest.
+func (r *BackupRequestReconciler) startPurge(
+	ctx context.Context,
+	adapter databaseAdapter,
+	backupRequest *backupv1.BackupRequest,
+	storage storageLocation,
+	overlay workloadOverlay,
+) error {
+	if err := adapter.supports(backupv1.AdapterCapabilityPurge); err != nil {
+		return err
+	}
+	resp, err := r.deleteCronJob(ctx, adapter, backupRequest, storage, overlay)
+	if status.Code(err) == codes.Unimplemented {
+		return unsupportedError{fmt.Errorf("adapter %s cannot delete CronJobs, update it: %w", adapter.address, err)}
+	} else if err != nil {
+		return fmt.Errorf("failed to delete CronJob: %w", err)
+	}
+
+	name := types.NamespacedName{
+		Namespace: resp.JobNamespace,
+		Name:      resp.JobName,
+	}
+	var job batchv1.Job
+	if err := r.Get(ctx, name, &job); err != nil {
+		return err
+	}
+	if job.Labels == nil {
+		job.Labels = map[string]string{}
+	}
+	job.Labels[purgeJobLabel] = backupRequest.Name
+	if err := controllerutil.SetControllerReference(backupRequest, &job, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Update(ctx, &job); err != nil {
+		return fmt.Errorf("failed to set owner of Job %s: %w", name, err)
+	}
+
+	backupRequest.Status.PurgeJobName = job.Name
+	setConditions(&backupRequest.Status.Conditions, backupRequest.Generation,
+		adapterReachable(adapter.address),
+		newCondition(TypeReady, metav1.ConditionUnknown, ReasonPurging,
+			fmt.Sprintf("Backups are purged by Job %s", job.Name)),
+	)
+	return r.Status().Update(ctx, backupRequest)
+}
+
+// deleteCronJob asks adapter to delete CronJob of backupRequest and start a Job purging its backups.
+func (r *BackupRequestReconciler) deleteCronJob(
+	ctx context.Context,
+	adapter databaseAdapter,
+	backupRequest *backupv1.BackupRequest,
+	storage storageLocation,
+	overlay workloadOverlay,
+) (*serverscommon.DeleteResponse, error) {
+	conn, err := adapter.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to close connection to adapter", "address", adapter.address)
+		}
+	}()
+
+	request, err := protojson.Marshal(buildBackupRequest(backupRequest, storage))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	if !overlay.isEmpty() {
+		patch, err := overlay.cronJobPatch()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build overlay: %w", err)
+		}
+		ctx = withOverlay(ctx, patch)
+	}
+
+	return serverscommon.NewDeletionClient(conn).Delete(ctx, &serverscommon.DeleteRequest{
+		Request:          request,
+		CronjobName:      backupRequest.Status.CronJobData.Name,
+		CronjobNamespace: backupRequest.Status.CronJobData.Namespace,
+	})
+}
+
+// trackPurge releases backupRequest once its purge Job completes.
+// Failed Job is reported by Ready condition, deleting it starts purge again.
+func (r *BackupRequestReconciler) trackPurge(ctx context.Context, backupRequest *backupv1.BackupRequest) (ctrl.Result, error) {
+	log := log.FromContext(ctx).WithValues("job", backupRequest.Status.PurgeJobName)
+	var job batchv1.Job
+	err := r.Get(ctx, client.ObjectKey{Namespace: backupRequest.Namespace, Name: backupRequest.Status.PurgeJobName}, &job)
+	if apierrors.IsNotFound(err) {
+		log.Info("Purge Job is not found, starting it again")
+		backupRequest.Status.PurgeJobName = ""
+		return ctrl.Result{}, r.Status().Update(ctx, backupRequest)
+	} else if err != nil {
+		log.Error(err, "Unable to get purge Job")
+		return ctrl.Result{}, err
+	}
+
+	condition, finished := jobFinished(&job)
+	if !finished {
+		return ctrl.Result{}, nil
+	}
+	if condition.Type == batchv1.JobComplete {
+		log.Info("Backups are purged, releasing BackupRequest")
+		return ctrl.Result{}, r.removeFinalizer(ctx, backupRequest)
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(job.Namespace), client.MatchingLabels{batchv1.JobNameLabel: job.Name}); err != nil {
+		log.Error(err, "Unable to list pods of purge Job")
+		return ctrl.Result{}, err
+	}
+	message := fmt.Sprintf("Job %s failed: %s", job.Name, condition.Message)
+	if podsMessage := podsMessage(pods.Items); podsMessage != "" {
+		message += ": " + podsMessage
+	}
+	failed := newCondition(TypeReady, metav1.ConditionFalse, ReasonPurgeFailed, message)
+	setConditions(&backupRequest.Status.Conditions, backupRequest.Generation, failed)
+	recordCondition(r.Recorder, backupRequest, failed)
+	return ctrl.Result{}, r.Status().Update(ctx, backupRequest)
+}
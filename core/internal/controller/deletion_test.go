@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"context"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	serverscommon "github.com/oiler-backup/core/common/servers/backup"
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+var _ = Describe("BackupRequest deletion", func() {
+	ctx := context.Background()
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(backupv1.AddToScheme(scheme)).To(Succeed())
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	})
+
+	deletedRequest := func(policy, purgeJobName string) *backupv1.BackupRequest {
+		now := metav1.Now()
+		return &backupv1.BackupRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "example",
+				Namespace:         "team-a",
+				UID:               "br-uid",
+				Finalizers:        []string{backupRequestFinalizer},
+				DeletionTimestamp: &now,
+			},
+			Spec: backupv1.BackupRequestSpec{DeletionPolicy: policy},
+			Status: backupv1.BackupRequestStatus{
+				CronJobData:  backupv1.CreatedCronJobData{Name: "backup-1a2b3c4d-example", Namespace: "team-a"},
+				PurgeJobName: purgeJobName,
+			},
+		}
+	}
+	purgeJob := func(br *backupv1.BackupRequest, conditionType batchv1.JobConditionType) *batchv1.Job {
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "purge-1a2b3c4d-example",
+				Namespace: br.Namespace,
+				Labels:    map[string]string{purgeJobLabel: br.Name},
+			},
+		}
+		if conditionType != "" {
+			job.Status.Conditions = []batchv1.JobCondition{{
+				Type:    conditionType,
+				Status:  corev1.ConditionTrue,
+				Message: "Job has reached the specified backoff limit",
+			}}
+		}
+		Expect(controllerutil.SetControllerReference(br, job, scheme)).To(Succeed())
+		return job
+	}
+	reconcileRequest := func(br *backupv1.BackupRequest, objs ...client.Object) *BackupRequestReconciler {
+		reconciler := &BackupRequestReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(append(objs, br)...).WithStatusSubresource(br).Build(),
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(br)})
+		Expect(err).NotTo(HaveOccurred())
+		return reconciler
+	}
+
+	It("should release BackupRequest retaining backups", func() {
+		br := deletedRequest(backupv1.DeletionPolicyRetain, "")
+		reconciler := reconcileRequest(br)
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, client.ObjectKeyFromObject(br), br))).To(BeTrue())
+	})
+
+	It("should release BackupRequest once backups are purged", func() {
+		br := deletedRequest(backupv1.DeletionPolicyDelete, "purge-1a2b3c4d-example")
+		reconciler := reconcileRequest(br, purgeJob(br, batchv1.JobComplete))
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, client.ObjectKeyFromObject(br), br))).To(BeTrue())
+	})
+
+	It("should wait for running purge Job", func() {
+		br := deletedRequest(backupv1.DeletionPolicyDelete, "purge-1a2b3c4d-example")
+		reconciler := reconcileRequest(br, purgeJob(br, ""))
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(br), br)).To(Succeed())
+		Expect(br.Finalizers).To(ContainElement(backupRequestFinalizer))
+	})
+
+	It("should report failed purge Job", func() {
+		br := deletedRequest(backupv1.DeletionPolicyDelete, "purge-1a2b3c4d-example")
+		reconciler := reconcileRequest(br, purgeJob(br, batchv1.JobFailed))
+
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(br), br)).To(Succeed())
+		Expect(br.Finalizers).To(ContainElement(backupRequestFinalizer))
+		condition := meta.FindStatusCondition(br.Status.Conditions, TypeReady)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(ReasonPurgeFailed))
+		Expect(condition.Message).To(Equal("Job purge-1a2b3c4d-example failed: Job has reached the specified backoff limit"))
+		Expect(reconciler.Recorder.(*record.FakeRecorder).Events).To(Receive(Equal(
+			"Warning PurgeFailed Job purge-1a2b3c4d-example failed: Job has reached the specified backoff limit")))
+	})
+
+	It("should start purge again if Job is lost", func() {
+		br := deletedRequest(backupv1.DeletionPolicyDelete, "purge-1a2b3c4d-example")
+		reconciler := reconcileRequest(br)
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(br), br)).To(Succeed())
+		Expect(br.Status.PurgeJobName).To(BeEmpty())
+	})
+
+	Context("startPurge", func() {
+		// fakeDeletion records overlays of deletion requests.
+		var overlays []string
+		// serve starts a gRPC server on a local port and returns its address.
+		// The deletion service is registered if withDeletion is set.
+		serve := func(withDeletion bool) string {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			server := grpc.NewServer()
+			if withDeletion {
+				serverscommon.RegisterDeletionServer(server, fakeDeletion{overlays: &overlays})
+			}
+			go func() { _ = server.Serve(lis) }()
+			DeferCleanup(server.Stop)
+			return lis.Addr().String()
+		}
+
+		It("should record purge Job started by adapter", func() {
+			br := deletedRequest(backupv1.DeletionPolicyDelete, "")
+			br.Spec.DbSpec.DbName = "shop"
+			job := purgeJob(br, "")
+			job.OwnerReferences, job.Labels = nil, nil
+			r := &BackupRequestReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).
+					WithObjects(br, job).WithStatusSubresource(br).Build(),
+				Scheme: scheme,
+			}
+
+			adapter := databaseAdapter{dbType: "postgres", address: serve(true)}
+			Expect(r.startPurge(ctx, adapter, br, storageLocation{}, workloadOverlay{}.withBackupPrefix(br))).To(Succeed())
+
+			Expect(overlays).To(HaveLen(1))
+			Expect(overlays[0]).To(ContainSubstring(`"value":"team-a/example/shop/"`))
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(br), br)).To(Succeed())
+			Expect(br.Status.PurgeJobName).To(Equal(job.Name))
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(job), job)).To(Succeed())
+			Expect(isPurgeJob(job)).To(BeTrue())
+			Expect(metav1.IsControlledBy(job, br)).To(BeTrue())
+		})
+
+		It("should report adapters without the deletion service", func() {
+			br := deletedRequest(backupv1.DeletionPolicyDelete, "")
+			r := &BackupRequestReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(br).Build(), Scheme: scheme}
+
+			err := r.startPurge(ctx, databaseAdapter{address: serve(false)}, br, storageLocation{}, workloadOverlay{})
+			Expect(isUnsupported(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("cannot delete CronJobs, update it")))
+		})
+	})
+
+	It("should not record purge Job as a backup run", func() {
+		br := deletedRequest(backupv1.DeletionPolicyDelete, "")
+		Expect(isPurgeJob(purgeJob(br, ""))).To(BeTrue())
+		Expect(isBackupRun(purgeJob(br, ""))).To(BeFalse())
+	})
+})
+
+// fakeDeletion answers deletion requests with Job purge-1a2b3c4d-example.
+type fakeDeletion struct {
+	overlays *[]string
+}
+
+func (f fakeDeletion) Delete(ctx context.Context, req *serverscommon.DeleteRequest) (*serverscommon.DeleteResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	*f.overlays = append(*f.overlays, md.Get(overlayMetadataKey)...)
+	return &serverscommon.DeleteResponse{JobName: "purge-1a2b3c4d-example", JobNamespace: req.CronjobNamespace}, nil
+}
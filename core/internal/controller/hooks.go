@@ -0,0 +1,23 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+// envHooks is an env of backuper with JSON of hooks it runs around the dump.
+const envHooks = "HOOKS"
+
+// hooksEnv renders hooks for backuper. It returns an empty string if there are no hooks.
+func hooksEnv(hooks *backupv1.BackupHooks) (string, error) {
+	if hooks == nil || len(hooks.Pre)+len(hooks.Post) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(hooks)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal hooks: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// jobFinished returns the final condition of job, if job has finished.
+func jobFinished(job *batchv1.Job) (*batchv1.JobCondition, bool) {
+	for i, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) &&
+			condition.Status == corev1.ConditionTrue {
+			return &job.Status.Conditions[i], true
+		}
+	}
+	return nil, false
+}
+
+// jobPhase maps state of job and its pods to a phase of BackupRestore or ManualRun.
+func jobPhase(job *batchv1.Job, pods []corev1.Pod) string {
+	if condition, finished := jobFinished(job); finished {
+		if condition.Type == batchv1.JobComplete {
+			return backupv1.RestorePhaseSucceeded
+		}
+		return backupv1.RestorePhaseFailed
+	}
+
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning {
+			return backupv1.RestorePhaseRunning
+		}
+	}
+	return backupv1.RestorePhasePending
+}
+
+// jobFinishTime returns when job succeeded or failed.
+func jobFinishTime(job *batchv1.Job) *metav1.Time {
+	if job.Status.CompletionTime != nil {
+		return job.Status.CompletionTime
+	}
+	if condition, finished := jobFinished(job); finished {
+		return &condition.LastTransitionTime
+	}
+	return nil
+}
+
+// podsMessage collects reasons why pods failed or do not start, ordered by pod name.
+// Containers failed earlier are reported too, since failed pods of a Job may be restarted in place.
+func podsMessage(pods []corev1.Pod) string {
+	pods = slices.Clone(pods)
+	slices.SortFunc(pods, func(a, b corev1.Pod) int { return strings.Compare(a.Name, b.Name) })
+
+	var messages []string
+	for _, pod := range pods {
+		if pod.Status.Reason != "" {
+			messages = append(messages, fmt.Sprintf("pod %s: %s: %s", pod.Name, pod.Status.Reason, pod.Status.Message))
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if message := containerMessage(status); message != "" {
+				messages = append(messages, fmt.Sprintf("pod %s: container %s %s", pod.Name, status.Name, message))
+			}
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+func containerMessage(status corev1.ContainerStatus) string {
+	terminated := status.State.Terminated
+	if terminated == nil || terminated.ExitCode == 0 {
+		terminated = status.LastTerminationState.Terminated
+	}
+	if terminated != nil && terminated.ExitCode != 0 {
+		message := fmt.Sprintf("exited with code %d: %s", terminated.ExitCode, terminated.Reason)
+		if terminated.Message != "" {
+			message += ": " + failureMessage(terminated.Message)
+		}
+		return message
+	}
+
+	waiting := status.State.Waiting
+	if waiting != nil && waiting.Reason != "" && waiting.Reason != "ContainerCreating" && waiting.Reason != "PodInitializing" {
+		return fmt.Sprintf("is waiting: %s: %s", waiting.Reason, waiting.Message)
+	}
+	return ""
+}
+
+// A runReport is written by backuper to its termination message after upload
+// and by restorer after restore.
+type runReport struct {
+	Key             string `json:"key"`
+	Size            int64  `json:"size"`
+	Checksum        string `json:"checksum"`
+	DatabaseVersion string `json:"databaseVersion"`
+	ToolVersion     string `json:"toolVersion"`
+	// Checks are results of verification checks run by restorer.
+	Checks []backupv1.CheckResult `json:"checks"`
+	// Hooks are results of hooks run by backuper.
+	Hooks []backupv1.HookResult `json:"hooks"`
+	// Deleted are keys of backups deleted by retention of backuper.
+	// DeletedOmitted counts keys which did not fit the report.
+	Deleted        []string `json:"deleted"`
+	DeletedOmitted int      `json:"deletedOmitted"`
+	// Error is set by backuper which failed after running hooks.
+	Error string `json:"error"`
+}
+
+// readRunReport reads report of containerName which exited with 0 or, if none did,
+// report of the last failed one. Backupers and restorers not writing a report yield an empty one.
+func readRunReport(pods []corev1.Pod, containerName string) runReport {
+	var failed runReport
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			terminated := status.State.Terminated
+			if status.Name != containerName || terminated == nil {
+				continue
+			}
+			var report runReport
+			if err := json.Unmarshal([]byte(terminated.Message), &report); err != nil {
+				continue
+			}
+			if terminated.ExitCode == 0 {
+				return report
+			}
+			failed = report
+		}
+	}
+	return failed
+}
+
+// failureMessage returns the error of a run report in message, or message itself
+// if it is not a report, e.g. the tail of the log.
+func failureMessage(message string) string {
+	var report runReport
+	if err := json.Unmarshal([]byte(message), &report); err == nil && report.Error != "" {
+		return report.Error
+	}
+	return strings.TrimSpace(message)
+}
+
+// backupRun describes finished job of a backup CronJob. It returns false if job is not finished yet.
+func backupRun(job *batchv1.Job, pods []corev1.Pod) (backupv1.BackupRun, bool) {
+	condition, finished := jobFinished(job)
+	if !finished {
+		return backupv1.BackupRun{}, false
+	}
+
+	run := backupv1.BackupRun{
+		JobName:        job.Name,
+		Succeeded:      condition.Type == batchv1.JobComplete,
+		StartTime:      job.Status.StartTime,
+		CompletionTime: jobFinishTime(job),
+	}
+	if run.StartTime != nil && run.CompletionTime != nil {
+		run.Duration = &metav1.Duration{Duration: run.CompletionTime.Sub(run.StartTime.Time)}
+	}
+
+	report := readRunReport(pods, backuperContainerName)
+	run.Hooks = report.Hooks
+	if run.Succeeded {
+		run.Key = report.Key
+		run.Size = report.Size
+	} else {
+		run.Message = condition.Message
+		if message := podsMessage(pods); message != "" {
+			run.Message += ": " + message
+		}
+	}
+	return run, true
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+var _ = Describe("Restore Job state", func() {
+	finishedJob := func(conditionType batchv1.JobConditionType) *batchv1.Job {
+		return &batchv1.Job{
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobSuspended, Status: corev1.ConditionFalse},
+					{Type: conditionType, Status: corev1.ConditionTrue, Message: "Job has reached the specified backoff limit"},
+				},
+			},
+		}
+	}
+	podInPhase := func(phase corev1.PodPhase) corev1.Pod {
+		return corev1.Pod{Status: corev1.PodStatus{Phase: phase}}
+	}
+
+	DescribeTable("maps Job to phase",
+		func(job *batchv1.Job, pods []corev1.Pod, phase string) {
+			Expect(jobPhase(job, pods)).To(Equal(phase))
+		},
+		Entry("without pods", &batchv1.Job{}, nil, backupv1.RestorePhasePending),
+		Entry("with pending pod", &batchv1.Job{}, []corev1.Pod{podInPhase(corev1.PodPending)}, backupv1.RestorePhasePending),
+		Entry("with running pod", &batchv1.Job{},
+			[]corev1.Pod{podInPhase(corev1.PodFailed), podInPhase(corev1.PodRunning)}, backupv1.RestorePhaseRunning),
+		Entry("complete", finishedJob(batchv1.JobComplete), nil, backupv1.RestorePhaseSucceeded),
+		Entry("failed", finishedJob(batchv1.JobFailed), nil, backupv1.RestorePhaseFailed),
+	)
+
+	It("uses time of Failed condition as finish time", func() {
+		job := finishedJob(batchv1.JobFailed)
+		job.Status.Conditions[1].LastTransitionTime = metav1.Unix(100, 0)
+		Expect(jobFinishTime(job).Unix()).To(Equal(int64(100)))
+		Expect(jobFinishTime(&batchv1.Job{})).To(BeNil())
+	})
+
+	It("collects failures of pods", func() {
+		pods := []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "restore-b"},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name: restorerContainerName,
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 10s"},
+						},
+						LastTerminationState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error", Message: "Faild to restore backup\n"},
+						},
+					}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "restore-a"},
+				Status: corev1.PodStatus{
+					Reason:  "Evicted",
+					Message: "The node was low on resource: memory.",
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "restore-c"},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name: restorerContainerName,
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"},
+						},
+					}},
+				},
+			},
+		}
+
+		Expect(podsMessage(pods)).To(Equal(
+			"pod restore-a: Evicted: The node was low on resource: memory.; " +
+				"pod restore-b: container backup-restore-job exited with code 1: Error: Faild to restore backup",
+		))
+	})
+
+	It("reports waiting containers", func() {
+		pods := []corev1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{Name: "restore-a"},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: restorerContainerName,
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "image not found"},
+					},
+				}},
+			},
+		}}
+
+		Expect(podsMessage(pods)).To(Equal(
+			"pod restore-a: container backup-restore-job is waiting: ImagePullBackOff: image not found",
+		))
+	})
+
+	It("reports hooks and error of failed backup", func() {
+		job := finishedJob(batchv1.JobFailed)
+		job.Name = "backup-1"
+		pods := []corev1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-1-a"},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: backuperContainerName,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error", Message: `{
+							"error":"Failed to backup: pre hook lock failed: timeout",
+							"hooks":[
+								{"name":"lock","phase":"pre","succeeded":false,"duration":"1s","message":"timeout"},
+								{"name":"unlock","phase":"post","succeeded":true,"duration":"5ms"}
+							]
+						}`},
+					},
+				}},
+			},
+		}}
+
+		run, finished := backupRun(job, pods)
+		Expect(finished).To(BeTrue())
+		Expect(run.Succeeded).To(BeFalse())
+		Expect(run.Hooks).To(HaveLen(2))
+		Expect(run.Hooks[0].Phase).To(Equal("pre"))
+		Expect(run.Hooks[1].Succeeded).To(BeTrue())
+		Expect(run.Message).To(Equal("Job has reached the specified backoff limit: " +
+			"pod backup-1-a: container backup-job exited with code 1: Error: Failed to backup: pre hook lock failed: timeout"))
+	})
+})
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"github.com/oiler-backup/core/common/layout"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+// Envs telling adapters where backups are stored, see package layout.
+const (
+	// envBackupPrefix is where backuper stores backups and applies retention.
+	envBackupPrefix = "BACKUP_PREFIX"
+	// envBackupNamespace and envBackupRequestName narrow backups restorer selects a revision among.
+	envBackupNamespace   = "BACKUP_NAMESPACE"
+	envBackupRequestName = "BACKUP_REQUEST_NAME"
+)
+
+// backupPrefix returns where backups of backupRequest are stored.
+func backupPrefix(backupRequest *backupv1.BackupRequest) string {
+	return layout.Prefix(backupRequest.Namespace, backupRequest.Name, backupRequest.Spec.DbSpec.DbName)
+}
+
+// withBackupPrefix makes backuper store backups of backupRequest under its own prefix,
+// so retention and purge never touch backups of other BackupRequests.
+func (o workloadOverlay) withBackupPrefix(backupRequest *backupv1.BackupRequest) workloadOverlay {
+	return o.withEnv(envBackupPrefix, backupPrefix(backupRequest))
+}
+
+// withBackupScope makes restorer select backups made in namespace and, if backupRequestName
+// is set, only by that BackupRequest. Backups of the legacy layout are selected as well.
+func (o workloadOverlay) withBackupScope(namespace, backupRequestName string) workloadOverlay {
+	o = o.withEnv(envBackupNamespace, namespace)
+	if backupRequestName != "" {
+		o = o.withEnv(envBackupRequestName, backupRequestName)
+	}
+	return o
+}
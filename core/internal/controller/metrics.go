@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+var (
+	verificationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "backup_verifications_total",
+			Help: "Total number of finished backup verifications by result",
+		},
+		[]string{"namespace", "verification", "result"},
+	)
+
+	verificationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "backup_verification_duration_seconds",
+			Help:    "Duration of backup verifications in seconds",
+			Buckets: prometheus.ExponentialBuckets(15, 2, 10),
+		},
+		[]string{"namespace", "verification"},
+	)
+
+	verificationLastPassed = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "backup_verification_last_passed_timestamp_seconds",
+			Help: "Time of the last passed backup verification",
+		},
+		[]string{"namespace", "verification"},
+	)
+
+	verificationCheckPassed = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "backup_verification_check_passed",
+			Help: "Whether a check passed in the last backup verification, 1 or 0",
+		},
+		[]string{"namespace", "verification", "check"},
+	)
+
+	verificationCheckRows = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "backup_verification_check_rows",
+			Help: "Number of rows returned by a check in the last backup verification",
+		},
+		[]string{"namespace", "verification", "check"},
+	)
+)
+
+// VerificationCollectors returns metrics of BackupVerifications to be registered by the manager.
+func VerificationCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		verificationsTotal,
+		verificationDuration,
+		verificationLastPassed,
+		verificationCheckPassed,
+		verificationCheckRows,
+	}
+}
+
+// recordVerification exports finished run of verification.
+func recordVerification(verification *backupv1.BackupVerification, run backupv1.VerificationRun) {
+	ns, name := verification.Namespace, verification.Name
+
+	result := "failed"
+	if run.Passed {
+		result = "passed"
+		if run.CompletionTime != nil {
+			verificationLastPassed.WithLabelValues(ns, name).Set(float64(run.CompletionTime.Unix()))
+		}
+	}
+	verificationsTotal.WithLabelValues(ns, name, result).Inc()
+	if run.Duration != nil {
+		verificationDuration.WithLabelValues(ns, name).Observe(run.Duration.Seconds())
+	}
+
+	verificationCheckPassed.DeletePartialMatch(prometheus.Labels{"namespace": ns, "verification": name})
+	verificationCheckRows.DeletePartialMatch(prometheus.Labels{"namespace": ns, "verification": name})
+	for _, check := range run.Checks {
+		passed := 0.0
+		if check.Passed {
+			passed = 1
+		}
+		verificationCheckPassed.WithLabelValues(ns, name, check.Name).Set(passed)
+		verificationCheckRows.WithLabelValues(ns, name, check.Name).Set(float64(check.Rows))
+	}
+}
+
+// forgetVerification drops metrics of deleted verification.
+func forgetVerification(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "verification": name}
+	for _, vec := range []interface{ DeletePartialMatch(prometheus.Labels) int }{
+		verificationsTotal, verificationDuration, verificationLastPassed, verificationCheckPassed, verificationCheckRows,
+	} {
+		vec.DeletePartialMatch(labels)
+	}
+}
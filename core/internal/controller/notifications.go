@@ -0,0 +1,193 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	"github.com/oiler-backup/core/core/internal/notify"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultNotificationEvents are sent if Notifications set no events.
+var defaultNotificationEvents = []string{
+	backupv1.NotificationBackupFailed,
+	backupv1.NotificationBackupStale,
+	backupv1.NotificationRestoreCompleted,
+}
+
+// notifies tells whether spec sends event.
+func notifies(spec *backupv1.Notifications, event string) bool {
+	if spec == nil || len(spec.Sinks) == 0 {
+		return false
+	}
+	events := spec.Events
+	if len(events) == 0 {
+		events = defaultNotificationEvents
+	}
+	return slices.Contains(events, event)
+}
+
+// notificationSinks builds sinks of spec connecting through dialer.
+// URLs and passwords are read from Secrets in namespace.
+func notificationSinks(
+	ctx context.Context,
+	r client.Reader,
+	spec *backupv1.Notifications,
+	namespace string,
+	dialer notify.Dialer,
+) (map[string]notify.Sink, error) {
+	sinks := map[string]notify.Sink{}
+	httpClient := dialer.Client()
+	for _, sink := range spec.Sinks {
+		switch {
+		case sink.Webhook != nil:
+			url := sink.Webhook.URL
+			if ref := sink.Webhook.URLSecretRef; ref != nil {
+				value, err := resolveSecretKey(ctx, r, ref, namespace)
+				if err != nil {
+					return nil, fmt.Errorf("sink %s: %w", sink.Name, err)
+				}
+				url = strings.TrimSpace(string(value))
+			}
+			webhook, err := notify.NewWebhook(url, sink.Webhook.Headers, sink.Webhook.BodyTemplate)
+			if err != nil {
+				return nil, fmt.Errorf("sink %s: %w", sink.Name, err)
+			}
+			webhook.Client = httpClient
+			sinks[sink.Name] = webhook
+		case sink.Slack != nil:
+			url, err := resolveSecretKey(ctx, r, &sink.Slack.URLSecretRef, namespace)
+			if err != nil {
+				return nil, fmt.Errorf("sink %s: %w", sink.Name, err)
+			}
+			sinks[sink.Name] = &notify.Slack{URL: strings.TrimSpace(string(url)), Channel: sink.Slack.Channel, Client: httpClient}
+		case sink.Email != nil:
+			email := &notify.Email{
+				Address:  sink.Email.SMTPAddress,
+				From:     sink.Email.From,
+				To:       sink.Email.To,
+				Username: sink.Email.Username,
+				Dialer:   dialer,
+			}
+			if ref := sink.Email.PasswordSecretRef; ref != nil {
+				password, err := resolveSecretKey(ctx, r, ref, namespace)
+				if err != nil {
+					return nil, fmt.Errorf("sink %s: %w", sink.Name, err)
+				}
+				email.Password = string(password)
+			}
+			sinks[sink.Name] = email
+		}
+	}
+	return sinks, nil
+}
+
+// newNotification returns event of backupRequest about object.
+func newNotification(backupRequest *backupv1.BackupRequest, event, object, message string) notify.Notification {
+	return notify.Notification{
+		Event:         event,
+		Namespace:     backupRequest.Namespace,
+		BackupRequest: backupRequest.Name,
+		Object:        object,
+		Message:       message,
+		Time:          time.Now(),
+	}
+}
+
+// sendNotification sends n about key to sinks of backupRequest and records it to status.
+// A notification about the same key as the recorded one is not sent again. Sinks failed after
+// retries are recorded too, so they are not retried on every reconciliation.
+// It returns false if nothing was sent.
+func sendNotification(
+	ctx context.Context,
+	r client.Reader,
+	sender notify.Sender,
+	backupRequest *backupv1.BackupRequest,
+	status *backupv1.BackupRequestStatus,
+	key string,
+	n notify.Notification,
+) bool {
+	if !notifies(backupRequest.Spec.Notifications, n.Event) {
+		return false
+	}
+	index := slices.IndexFunc(status.Notifications, func(record backupv1.NotificationRecord) bool { return record.Event == n.Event })
+	if index >= 0 && status.Notifications[index].Key == key {
+		return false
+	}
+
+	record := backupv1.NotificationRecord{Event: n.Event, Key: key, SentTime: &metav1.Time{Time: n.Time}}
+	if err := deliver(ctx, r, sender, backupRequest, n); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to send notification", "event", n.Event, "key", key)
+		record.Message = err.Error()
+	}
+
+	if index >= 0 {
+		status.Notifications[index] = record
+	} else {
+		status.Notifications = append(status.Notifications, record)
+	}
+	return true
+}
+
+// deliver sends n to sinks of backupRequest.
+func deliver(ctx context.Context, r client.Reader, sender notify.Sender, backupRequest *backupv1.BackupRequest, n notify.Notification) error {
+	sinks, err := notificationSinks(ctx, r, backupRequest.Spec.Notifications, backupRequest.Namespace, sender.Dialer)
+	if err != nil {
+		return err
+	}
+	return sender.Send(ctx, sinks, n)
+}
+
+// notifyRun sends BackupFailed or BackupSucceeded about finished run.
+func notifyRun(
+	ctx context.Context,
+	r client.Reader,
+	sender notify.Sender,
+	backupRequest *backupv1.BackupRequest,
+	status *backupv1.BackupRequestStatus,
+	run backupv1.BackupRun,
+) {
+	event := backupv1.NotificationBackupFailed
+	message := fmt.Sprintf("Job %s failed: %s", run.JobName, run.Message)
+	if run.Succeeded {
+		event = backupv1.NotificationBackupSucceeded
+		message = fmt.Sprintf("Backup %s of %d bytes is uploaded by Job %s", run.Key, run.Size, run.JobName)
+	}
+	sendNotification(ctx, r, sender, backupRequest, status, run.JobName,
+		newNotification(backupRequest, event, run.JobName, message))
+}
+
+// notifiedBackupRequest returns BackupRequest backups of which backupRestore restores,
+// if it sends RestoreCompleted, or nil.
+func notifiedBackupRequest(ctx context.Context, r client.Reader, backupRestore *backupv1.BackupRestore) (*backupv1.BackupRequest, error) {
+	name, ok := strings.CutPrefix(backupRestore.Spec.BackupRevision, backupv1.RevisionBackupRequestPrefix)
+	if !ok {
+		name = ""
+	}
+	if source := backupRestore.Spec.Source; source != nil && source.BackupRequestName != "" {
+		name = source.BackupRequestName
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	var backupRequest backupv1.BackupRequest
+	err := r.Get(ctx, types.NamespacedName{Namespace: backupRestore.Namespace, Name: name}, &backupRequest)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if !notifies(backupRequest.Spec.Notifications, backupv1.NotificationRestoreCompleted) {
+		return nil, nil
+	}
+	return &backupRequest, nil
+}
@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	"github.com/oiler-backup/core/core/internal/notify"
+)
+
+var _ = Describe("Notifications", func() {
+	ctx := context.Background()
+	// The test server listens on loopback, which sinks may not reach by default.
+	sender := notify.Sender{Attempts: 2, Backoff: time.Millisecond,
+		Dialer: notify.Dialer{AllowedNetworks: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}}}
+	var scheme *runtime.Scheme
+	var received []map[string]string
+	var server *httptest.Server
+	var secret *corev1.Secret
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(backupv1.AddToScheme(scheme)).To(Succeed())
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		received = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			var n map[string]string
+			Expect(json.Unmarshal(data, &n)).To(Succeed())
+			received = append(received, n)
+		}))
+		DeferCleanup(server.Close)
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "hooks", Namespace: "team-a"},
+			Data:       map[string][]byte{"url": []byte(server.URL + "\n")},
+		}
+	})
+
+	backupRequestNotifying := func(events ...string) *backupv1.BackupRequest {
+		return &backupv1.BackupRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "team-a", UID: "br-uid",
+				CreationTimestamp: metav1.Unix(1000, 0)},
+			Spec: backupv1.BackupRequestSpec{
+				Notifications: &backupv1.Notifications{
+					Events:     events,
+					StaleAfter: &metav1.Duration{Duration: 26 * time.Hour},
+					Sinks: []backupv1.NotificationSink{{
+						Name: "ops",
+						Webhook: &backupv1.WebhookSink{
+							URLSecretRef: &backupv1.SecretKeyReference{Name: "hooks", Key: "url"},
+						},
+					}},
+				},
+			},
+		}
+	}
+
+	It("sends failures, staleness and completed restores by default", func() {
+		spec := backupRequestNotifying().Spec.Notifications
+		Expect(notifies(spec, backupv1.NotificationBackupFailed)).To(BeTrue())
+		Expect(notifies(spec, backupv1.NotificationBackupStale)).To(BeTrue())
+		Expect(notifies(spec, backupv1.NotificationRestoreCompleted)).To(BeTrue())
+		Expect(notifies(spec, backupv1.NotificationBackupSucceeded)).To(BeFalse())
+		Expect(notifies(nil, backupv1.NotificationBackupFailed)).To(BeFalse())
+
+		spec = backupRequestNotifying(backupv1.NotificationBackupSucceeded).Spec.Notifications
+		Expect(notifies(spec, backupv1.NotificationBackupSucceeded)).To(BeTrue())
+		Expect(notifies(spec, backupv1.NotificationBackupFailed)).To(BeFalse())
+	})
+
+	It("notifies about a failed run once", func() {
+		br := backupRequestNotifying()
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders-backup-1", Namespace: "team-a"},
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{{
+					Type:    batchv1.JobFailed,
+					Status:  corev1.ConditionTrue,
+					Message: "Job has reached the specified backoff limit",
+				}},
+			},
+		}
+		Expect(controllerutil.SetControllerReference(br, job, scheme)).To(Succeed())
+		reconciler := &BackupHistoryReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(br, job, secret).WithStatusSubresource(br).Build(),
+			Scheme: scheme,
+			Sender: sender,
+		}
+
+		for range 2 {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(job)})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(received).To(HaveLen(1))
+		Expect(received[0]).To(HaveKeyWithValue("event", backupv1.NotificationBackupFailed))
+		Expect(received[0]).To(HaveKeyWithValue("object", "orders-backup-1"))
+		Expect(received[0]).To(HaveKeyWithValue("message",
+			"Job orders-backup-1 failed: Job has reached the specified backoff limit"))
+
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(br), br)).To(Succeed())
+		Expect(br.Status.Notifications).To(HaveLen(1))
+		Expect(br.Status.Notifications[0].Key).To(Equal("orders-backup-1"))
+		Expect(br.Status.Notifications[0].Message).To(BeEmpty())
+	})
+
+	It("records sinks failed after retries", func() {
+		br := backupRequestNotifying()
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		status := &backupv1.BackupRequestStatus{}
+
+		n := newNotification(br, backupv1.NotificationBackupFailed, "orders-backup-1", "failed")
+		Expect(sendNotification(ctx, c, sender, br, status, "orders-backup-1", n)).To(BeTrue())
+		Expect(status.Notifications[0].Message).To(ContainSubstring("sink ops: unable to get Secret team-a/hooks"))
+		Expect(sendNotification(ctx, c, sender, br, status, "orders-backup-1", n)).To(BeFalse())
+	})
+
+	It("notifies about stale backups once per last backup", func() {
+		br := backupRequestNotifying()
+		br.Status.LastBackupTime = &metav1.Time{Time: time.Now().Add(-27 * time.Hour)}
+		reconciler := &BackupStalenessReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(br, secret).WithStatusSubresource(br).Build(),
+			Scheme: scheme,
+			Sender: sender,
+		}
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(br)}
+
+		for range 2 {
+			result, err := reconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+		}
+		Expect(received).To(HaveLen(1))
+		Expect(received[0]).To(HaveKeyWithValue("event", backupv1.NotificationBackupStale))
+
+		Expect(reconciler.Get(ctx, request.NamespacedName, br)).To(Succeed())
+		br.Status.LastBackupTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+		Expect(reconciler.Status().Update(ctx, br)).To(Succeed())
+		result, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically("~", 25*time.Hour, time.Minute))
+		Expect(received).To(HaveLen(1))
+	})
+
+	It("finds BackupRequest of a restore to notify", func() {
+		br := backupRequestNotifying()
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(br).Build()
+		restore := &backupv1.BackupRestore{
+			ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: "team-a"},
+			Spec:       backupv1.BackupRestoreSpec{BackupRevision: backupv1.RevisionBackupRequestPrefix + "orders"},
+		}
+
+		found, err := notifiedBackupRequest(ctx, c, restore)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.Name).To(Equal("orders"))
+
+		restore.Spec.BackupRevision = backupv1.RevisionLatest
+		Expect(notifiedBackupRequest(ctx, c, restore)).To(BeNil())
+		restore.Spec.Source = &backupv1.RestoreSource{BackupRequestName: "orders"}
+		Expect(notifiedBackupRequest(ctx, c, restore)).NotTo(BeNil())
+		restore.Spec.Source = &backupv1.RestoreSource{BackupRequestName: "missing"}
+		Expect(notifiedBackupRequest(ctx, c, restore)).To(BeNil())
+	})
+})
@@ -0,0 +1,257 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"google.golang.org/grpc/metadata"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+const (
+	// overlayMetadataKey is a gRPC metadata key carrying a strategic merge patch
+	// which adapters apply to generated CronJob or Job before creating it.
+	overlayMetadataKey = "x-oiler-overlay"
+	// runOnceMetadataKey asks adapter to create a one-off Job from the template
+	// of backuper CronJob instead of the CronJob itself.
+	runOnceMetadataKey = "x-oiler-run-once"
+
+	backuperContainerName = "backup-job"
+	restorerContainerName = "backup-restore-job"
+)
+
+// A workloadOverlay collects changes of a workload generated by adapter.
+type workloadOverlay struct {
+	namespace                string
+	env                      []corev1.EnvVar
+	terminationMessagePolicy corev1.TerminationMessagePolicy
+	suspend                  *bool
+	timeZone                 string
+	sidecars                 []corev1.Container
+	activeDeadlineSeconds    *int64
+	jobControls              backupv1.JobControls
+	cronJobControls          backupv1.CronJobControls
+	podTemplate              *backupv1.PodTemplateOverride
+}
+
+// inNamespace places workload to namespace instead of the adapter one.
+func (o workloadOverlay) inNamespace(namespace string) workloadOverlay {
+	o.namespace = namespace
+	return o
+}
+
+// withSecretEnv makes envs read their values from secretName.
+// Keys of a Secret must match names of envs.
+func (o workloadOverlay) withSecretEnv(secretName string, envs ...string) workloadOverlay {
+	for _, name := range envs {
+		o.env = append(o.env, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  name,
+				},
+			},
+		})
+	}
+	return o
+}
+
+// withEnv sets env name of the container to value.
+func (o workloadOverlay) withEnv(name, value string) workloadOverlay {
+	o.env = append(o.env, corev1.EnvVar{Name: name, Value: value})
+	return o
+}
+
+// withLogsOnError makes the tail of container log a termination message
+// of a failed container, so the controller can report why it failed.
+func (o workloadOverlay) withLogsOnError() workloadOverlay {
+	o.terminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+	return o
+}
+
+// withSuspend suspends or resumes scheduling of CronJob.
+func (o workloadOverlay) withSuspend(suspend bool) workloadOverlay {
+	o.suspend = &suspend
+	return o
+}
+
+// withTimeZone sets time zone of the schedule of CronJob.
+func (o workloadOverlay) withTimeZone(timeZone string) workloadOverlay {
+	o.timeZone = timeZone
+	return o
+}
+
+// withSidecar runs container next to the workload as a native sidecar:
+// it starts before the workload and is stopped once the workload finishes.
+func (o workloadOverlay) withSidecar(container corev1.Container) workloadOverlay {
+	container.RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+	o.sidecars = append(o.sidecars, container)
+	return o
+}
+
+// withActiveDeadline limits how long a Job may run before it is failed.
+func (o workloadOverlay) withActiveDeadline(seconds int64) workloadOverlay {
+	o.activeDeadlineSeconds = &seconds
+	return o
+}
+
+// withJobControls sets deadline, retries and TTL of Jobs. Unset controls are not changed.
+func (o workloadOverlay) withJobControls(controls backupv1.JobControls) workloadOverlay {
+	if controls.ActiveDeadlineSeconds != nil {
+		o = o.withActiveDeadline(*controls.ActiveDeadlineSeconds)
+	}
+	o.jobControls.BackoffLimit = controls.BackoffLimit
+	o.jobControls.TTLSecondsAfterFinished = controls.TTLSecondsAfterFinished
+	return o
+}
+
+// withCronJobControls sets concurrency, starting deadline and history limits of CronJob.
+func (o workloadOverlay) withCronJobControls(controls backupv1.CronJobControls) workloadOverlay {
+	o.cronJobControls = controls
+	return o
+}
+
+// withPodTemplate merges template into pods of the workload.
+func (o workloadOverlay) withPodTemplate(template *backupv1.PodTemplateOverride) workloadOverlay {
+	o.podTemplate = template
+	return o
+}
+
+func (o workloadOverlay) isEmpty() bool {
+	return o.namespace == "" && len(o.env) == 0 && o.terminationMessagePolicy == "" && o.suspend == nil &&
+		o.timeZone == "" && len(o.sidecars) == 0 && o.activeDeadlineSeconds == nil &&
+		o.jobControls == backupv1.JobControls{} && o.cronJobControls == backupv1.CronJobControls{} &&
+		o.podTemplate == nil
+}
+
+// podTemplatePatch renders patch of a pod template with a single container containerName
+// and sidecars of overlay.
+func (o workloadOverlay) podTemplatePatch(containerName string) map[string]interface{} {
+	container := map[string]interface{}{}
+	if len(o.env) > 0 {
+		container["env"] = o.env
+	}
+	if o.terminationMessagePolicy != "" {
+		container["terminationMessagePolicy"] = o.terminationMessagePolicy
+	}
+
+	spec := map[string]interface{}{}
+	metadata := map[string]interface{}{}
+	if t := o.podTemplate; t != nil {
+		setIfPresent(metadata, "labels", t.Labels)
+		setIfPresent(metadata, "annotations", t.Annotations)
+		setIfPresent(spec, "nodeSelector", t.NodeSelector)
+		setIfPresent(spec, "affinity", t.Affinity)
+		setIfPresent(spec, "tolerations", t.Tolerations)
+		setIfPresent(spec, "priorityClassName", t.PriorityClassName)
+		setIfPresent(spec, "serviceAccountName", t.ServiceAccountName)
+		setIfPresent(spec, "imagePullSecrets", t.ImagePullSecrets)
+		setIfPresent(spec, "securityContext", t.SecurityContext)
+		setIfPresent(spec, "volumes", t.Volumes)
+		setIfPresent(container, "resources", t.Resources)
+		setIfPresent(container, "securityContext", t.ContainerSecurityContext)
+		setIfPresent(container, "volumeMounts", t.VolumeMounts)
+	}
+	if len(container) > 0 {
+		container["name"] = containerName
+		spec["containers"] = []map[string]interface{}{container}
+	}
+	if len(o.sidecars) > 0 {
+		spec["initContainers"] = o.sidecars
+	}
+
+	patch := map[string]interface{}{}
+	if len(metadata) > 0 {
+		patch["metadata"] = metadata
+	}
+	if len(spec) > 0 {
+		patch["spec"] = spec
+	}
+	return patch
+}
+
+// setIfPresent sets key of patch to value unless value is empty.
+func setIfPresent(patch map[string]interface{}, key string, value interface{}) {
+	if !reflect.ValueOf(value).IsZero() {
+		patch[key] = value
+	}
+}
+
+// objectPatch renders patch of a workload with given spec patch.
+func (o workloadOverlay) objectPatch(spec map[string]interface{}) ([]byte, error) {
+	patch := map[string]interface{}{
+		"spec": spec,
+	}
+	if o.namespace != "" {
+		patch["metadata"] = map[string]interface{}{
+			"namespace": o.namespace,
+		}
+	}
+	return json.Marshal(patch)
+}
+
+// jobSpecPatch renders patch of a Job spec with a single container containerName.
+func (o workloadOverlay) jobSpecPatch(containerName string) map[string]interface{} {
+	spec := map[string]interface{}{
+		"template": o.podTemplatePatch(containerName),
+	}
+	if o.activeDeadlineSeconds != nil {
+		spec["activeDeadlineSeconds"] = *o.activeDeadlineSeconds
+	}
+	if o.jobControls.BackoffLimit != nil {
+		spec["backoffLimit"] = *o.jobControls.BackoffLimit
+	}
+	if o.jobControls.TTLSecondsAfterFinished != nil {
+		spec["ttlSecondsAfterFinished"] = *o.jobControls.TTLSecondsAfterFinished
+	}
+	return spec
+}
+
+// cronJobPatch renders overlay as a strategic merge patch of backuper CronJob.
+func (o workloadOverlay) cronJobPatch() ([]byte, error) {
+	spec := map[string]interface{}{
+		"jobTemplate": map[string]interface{}{
+			"spec": o.jobSpecPatch(backuperContainerName),
+		},
+	}
+	if o.suspend != nil {
+		spec["suspend"] = *o.suspend
+	}
+	if o.timeZone != "" {
+		spec["timeZone"] = o.timeZone
+	}
+	controls := o.cronJobControls
+	if controls.ConcurrencyPolicy != "" {
+		spec["concurrencyPolicy"] = controls.ConcurrencyPolicy
+	}
+	if controls.StartingDeadlineSeconds != nil {
+		spec["startingDeadlineSeconds"] = *controls.StartingDeadlineSeconds
+	}
+	if controls.SuccessfulJobsHistoryLimit != nil {
+		spec["successfulJobsHistoryLimit"] = *controls.SuccessfulJobsHistoryLimit
+	}
+	if controls.FailedJobsHistoryLimit != nil {
+		spec["failedJobsHistoryLimit"] = *controls.FailedJobsHistoryLimit
+	}
+	return o.objectPatch(spec)
+}
+
+// jobPatch renders overlay as a strategic merge patch of restorer Job.
+func (o workloadOverlay) jobPatch() ([]byte, error) {
+	return o.objectPatch(o.jobSpecPatch(restorerContainerName))
+}
+
+// withOverlay attaches patch to outgoing gRPC metadata of ctx.
+func withOverlay(ctx context.Context, patch []byte) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, overlayMetadataKey, string(patch))
+}
+
+// withRunOnce asks adapter to run a backup once.
+func withRunOnce(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, runOnceMetadataKey, "true")
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+var _ = Describe("Workload overlay", func() {
+	It("should render suspend of CronJob", func() {
+		patch, err := workloadOverlay{}.withSuspend(true).cronJobPatch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"spec":{"suspend":true,"jobTemplate":{"spec":{"template":{}}}}}`))
+
+		patch, err = workloadOverlay{}.withSuspend(false).cronJobPatch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"spec":{"suspend":false,"jobTemplate":{"spec":{"template":{}}}}}`))
+	})
+
+	It("should render time zone of CronJob", func() {
+		patch, err := workloadOverlay{}.withTimeZone("Europe/Moscow").cronJobPatch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"spec":{"timeZone":"Europe/Moscow","jobTemplate":{"spec":{"template":{}}}}}`))
+	})
+
+	It("should not suspend restore Job", func() {
+		patch, err := workloadOverlay{}.inNamespace("team-a").withSuspend(true).jobPatch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"metadata":{"namespace":"team-a"},"spec":{"template":{}}}`))
+	})
+
+	It("should render container changes", func() {
+		patch, err := workloadOverlay{}.withLogsOnError().cronJobPatch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"containers":[
+			{"name":"backup-job","terminationMessagePolicy":"FallbackToLogsOnError"}
+		]}}}}}}`))
+	})
+
+	It("should render plain envs", func() {
+		patch, err := workloadOverlay{}.withEnv("SOURCE_DB_NAME", "orders").jobPatch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"spec":{"template":{"spec":{"containers":[
+			{"name":"backup-restore-job","env":[{"name":"SOURCE_DB_NAME","value":"orders"}]}
+		]}}}}`))
+	})
+
+	It("should render native sidecars", func() {
+		patch, err := workloadOverlay{}.withSidecar(corev1.Container{Name: "throwaway-database", Image: "postgres:16-alpine"}).jobPatch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"spec":{"template":{"spec":{"initContainers":[
+			{"name":"throwaway-database","image":"postgres:16-alpine","resources":{},"restartPolicy":"Always"}
+		]}}}}`))
+	})
+
+	It("should render deadline of restore Job", func() {
+		patch, err := workloadOverlay{}.withActiveDeadline(600).jobPatch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"spec":{"activeDeadlineSeconds":600,"template":{}}}`))
+	})
+
+	It("should render set rules of retention policy", func() {
+		policy := &backupv1.RetentionPolicy{Daily: 7, Monthly: 12, MaxAge: "365d"}
+		patch, err := workloadOverlay{}.withRetention(policy).cronJobPatch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"containers":[
+			{"name":"backup-job","env":[
+				{"name":"KEEP_DAILY","value":"7"},
+				{"name":"KEEP_MONTHLY","value":"12"},
+				{"name":"MAX_AGE","value":"365d"}
+			]}
+		]}}}}}}`))
+
+		Expect(workloadOverlay{}.withRetention(nil).isEmpty()).To(BeTrue())
+	})
+
+	It("should render backup prefix and scope", func() {
+		backupRequest := &backupv1.BackupRequest{}
+		backupRequest.Namespace, backupRequest.Name = "team-a", "orders"
+		backupRequest.Spec.DbSpec.DbName = "shop"
+		patch, err := workloadOverlay{}.withBackupPrefix(backupRequest).cronJobPatch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"containers":[
+			{"name":"backup-job","env":[{"name":"BACKUP_PREFIX","value":"team-a/orders/shop/"}]}
+		]}}}}}}`))
+
+		patch, err = workloadOverlay{}.withBackupScope("team-a", "").jobPatch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"spec":{"template":{"spec":{"containers":[
+			{"name":"backup-restore-job","env":[{"name":"BACKUP_NAMESPACE","value":"team-a"}]}
+		]}}}}`))
+	})
+
+	It("should render hooks as JSON", func() {
+		env, err := hooksEnv(&backupv1.BackupHooks{
+			Pre: []backupv1.BackupHook{{Name: "checkpoint", Query: "CHECKPOINT"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(env).To(MatchJSON(`{"pre":[{"name":"checkpoint","query":"CHECKPOINT"}]}`))
+
+		Expect(hooksEnv(nil)).To(BeEmpty())
+		Expect(hooksEnv(&backupv1.BackupHooks{})).To(BeEmpty())
+	})
+
+	It("should render controls of backup Jobs and CronJob", func() {
+		patch, err := workloadOverlay{}.
+			withJobControls(backupv1.JobControls{ActiveDeadlineSeconds: ptr.To[int64](3600), BackoffLimit: ptr.To[int32](2)}).
+			withCronJobControls(backupv1.CronJobControls{ConcurrencyPolicy: "Forbid", FailedJobsHistoryLimit: ptr.To[int32](3)}).
+			cronJobPatch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"spec":{
+			"concurrencyPolicy":"Forbid",
+			"failedJobsHistoryLimit":3,
+			"jobTemplate":{"spec":{"activeDeadlineSeconds":3600,"backoffLimit":2,"template":{}}}
+		}}`))
+	})
+
+	It("should render controls of restore Job", func() {
+		patch, err := workloadOverlay{}.withActiveDeadline(600).
+			withJobControls(backupv1.JobControls{TTLSecondsAfterFinished: ptr.To[int32](86400)}).jobPatch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"spec":{"activeDeadlineSeconds":600,"ttlSecondsAfterFinished":86400,"template":{}}}`))
+
+		Expect(workloadOverlay{}.withJobControls(backupv1.JobControls{}).isEmpty()).To(BeTrue())
+	})
+
+	It("should render pod template overrides", func() {
+		template := &backupv1.PodTemplateOverride{
+			Labels:            map[string]string{"team": "a"},
+			NodeSelector:      map[string]string{"node-role": "backup"},
+			Tolerations:       []corev1.Toleration{{Key: "backup", Operator: corev1.TolerationOpExists}},
+			PriorityClassName: "low",
+			Volumes:           []corev1.Volume{{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+			VolumeMounts: []corev1.VolumeMount{{Name: "scratch", MountPath: "/tmp"}},
+		}
+		patch, err := workloadOverlay{}.withPodTemplate(template).cronJobPatch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"spec":{"jobTemplate":{"spec":{"template":{
+			"metadata":{"labels":{"team":"a"}},
+			"spec":{
+				"nodeSelector":{"node-role":"backup"},
+				"tolerations":[{"key":"backup","operator":"Exists"}],
+				"priorityClassName":"low",
+				"volumes":[{"name":"scratch","emptyDir":{}}],
+				"containers":[{
+					"name":"backup-job",
+					"resources":{"requests":{"memory":"1Gi"}},
+					"volumeMounts":[{"name":"scratch","mountPath":"/tmp"}]
+				}]
+			}
+		}}}}}`))
+
+		Expect(workloadOverlay{}.withPodTemplate(nil).isEmpty()).To(BeTrue())
+	})
+})
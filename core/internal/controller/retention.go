@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"strconv"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+// Envs of backuper with rules of RetentionPolicy. MaxBackupCount is sent to
+// adapters as a field of the request and becomes the rule of the newest backups.
+const (
+	envKeepHourly  = "KEEP_HOURLY"
+	envKeepDaily   = "KEEP_DAILY"
+	envKeepWeekly  = "KEEP_WEEKLY"
+	envKeepMonthly = "KEEP_MONTHLY"
+	envKeepYearly  = "KEEP_YEARLY"
+	envMaxAge      = "MAX_AGE"
+)
+
+// withRetention passes rules of policy to backuper. Rules which are not set are omitted,
+// so backupers keep only the newest MaxBackupCount backups without a policy.
+func (o workloadOverlay) withRetention(policy *backupv1.RetentionPolicy) workloadOverlay {
+	if policy == nil {
+		return o
+	}
+	for _, rule := range []struct {
+		env   string
+		count int32
+	}{
+		{envKeepHourly, policy.Hourly},
+		{envKeepDaily, policy.Daily},
+		{envKeepWeekly, policy.Weekly},
+		{envKeepMonthly, policy.Monthly},
+		{envKeepYearly, policy.Yearly},
+	} {
+		if rule.count > 0 {
+			o = o.withEnv(rule.env, strconv.Itoa(int(rule.count)))
+		}
+	}
+	if policy.MaxAge != "" {
+		o = o.withEnv(envMaxAge, policy.MaxAge)
+	}
+	return o
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// revisionDatabaseSep separates a selector of restorer from the database to take backups of.
+const revisionDatabaseSep = "@"
+
+// revisionSource returns BackupRequest referred by revision of backupRestore as the source
+// of its backups, so they are taken from the storage of the BackupRequest. ok is false if
+// revision does not refer to a BackupRequest.
+func revisionSource(ctx context.Context, c client.Reader, backupRestore *backupv1.BackupRestore) (restoreSource, bool, error) {
+	name, ok := strings.CutPrefix(backupRestore.Spec.BackupRevision, backupv1.RevisionBackupRequestPrefix)
+	if !ok {
+		return restoreSource{}, false, nil
+	}
+	source, err := backupRequestSource(ctx, c, backupRestore, name)
+	return source, err == nil, err
+}
+
+// resolveRevision returns revision of backupRestore the way restorer understands it.
+// Reference to BackupRequest, resolved to source by revisionSource, becomes the key of
+// its newest Backup stored in storage or, if there is none, the latest backup of its database.
+func resolveRevision(
+	ctx context.Context,
+	c client.Reader,
+	backupRestore *backupv1.BackupRestore,
+	source restoreSource,
+	storage storageLocation,
+) (string, error) {
+	revision := backupRestore.Spec.BackupRevision
+	if !strings.HasPrefix(revision, backupv1.RevisionBackupRequestPrefix) {
+		return revision, nil
+	}
+
+	var backups backupv1.BackupList
+	if err := c.List(ctx, &backups, client.InNamespace(source.backupRequest.Namespace),
+		client.MatchingLabels{backupv1.BackupRequestLabel: source.backupRequest.Name}); err != nil {
+		return "", fmt.Errorf("failed to list Backups of BackupRequest %s: %w", source.backupRequest.Name, err)
+	}
+	var newest *backupv1.Backup
+	for i := range backups.Items {
+		backup := &backups.Items[i]
+		if backup.Spec.Endpoint != storage.endpoint || backup.Spec.BucketName != storage.bucketName ||
+			backup.Spec.CompletionTime == nil {
+			continue
+		}
+		if newest == nil || newest.Spec.CompletionTime.Before(backup.Spec.CompletionTime) {
+			newest = backup
+		}
+	}
+	if newest != nil {
+		return newest.Spec.Key, nil
+	}
+	return backupv1.RevisionLatest + revisionDatabaseSep + source.databaseName, nil
+}
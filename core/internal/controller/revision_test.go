@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+var _ = Describe("Backup revision", func() {
+	ctx := context.Background()
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(backupv1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	restoreOf := func(revision string) *backupv1.BackupRestore {
+		return &backupv1.BackupRestore{
+			ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: "team-a"},
+			Spec:       backupv1.BackupRestoreSpec{BackupRevision: revision},
+		}
+	}
+
+	DescribeTable("passes other revisions to restorer as is",
+		func(revision string) {
+			c := fake.NewClientBuilder().WithScheme(scheme).Build()
+			backupRestore := restoreOf(revision)
+			_, ok, err := revisionSource(ctx, c, backupRestore)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+			Expect(resolveRevision(ctx, c, backupRestore, restoreSource{}, storageLocation{})).To(Equal(revision))
+		},
+		Entry("latest", "latest"),
+		Entry("newest", "newest:2"),
+		Entry("before", "before:2025-05-01T00:00:00Z"),
+		Entry("index", "0"),
+		Entry("object key", "postgres/2025-05-01-00-00-00-backup.sql"),
+	)
+
+	Context("with BackupRequest", func() {
+		backupRequest := &backupv1.BackupRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "team-a"},
+			Spec:       backupv1.BackupRequestSpec{DbSpec: backupv1.DatabaseSpec{DbType: "postgres", DbName: "orders_db"}},
+		}
+		storage := storageLocation{endpoint: "s3.example.com", bucketName: "backups"}
+		backup := func(name, bucket, key string, completed int64) *backupv1.Backup {
+			return &backupv1.Backup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "team-a",
+					Labels:    map[string]string{backupv1.BackupRequestLabel: "orders"},
+				},
+				Spec: backupv1.BackupSpec{
+					Endpoint:       "s3.example.com",
+					BucketName:     bucket,
+					Key:            key,
+					CompletionTime: &metav1.Time{Time: time.Unix(completed, 0)},
+				},
+			}
+		}
+		resolve := func(objs ...client.Object) string {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objs, backupRequest)...).Build()
+			backupRestore := restoreOf("backupRequest:orders")
+			backupRestore.Spec.DatabaseType = "postgres"
+
+			source, ok, err := revisionSource(ctx, c, backupRestore)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(source.databaseName).To(Equal("orders_db"))
+			Expect(source.backupRequestName()).To(Equal("orders"))
+
+			revision, err := resolveRevision(ctx, c, backupRestore, source, storage)
+			Expect(err).NotTo(HaveOccurred())
+			return revision
+		}
+
+		It("resolves to the newest Backup in the storage of BackupRequest", func() {
+			Expect(resolve(
+				backup("backup-1", "backups", "team-a/orders/orders_db/1-backup.sql", 1000),
+				backup("backup-2", "backups", "team-a/orders/orders_db/2-backup.sql", 2000),
+				backup("backup-3", "old-backups", "team-a/orders/orders_db/3-backup.sql", 3000),
+			)).To(Equal("team-a/orders/orders_db/2-backup.sql"))
+		})
+
+		It("resolves to the latest backup of its database without Backups", func() {
+			Expect(resolve()).To(Equal("latest@orders_db"))
+		})
+	})
+
+	It("fails if BackupRequest is not found in namespace of BackupRestore", func() {
+		backupRequest := &backupv1.BackupRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "team-b"},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(backupRequest).Build()
+
+		_, _, err := revisionSource(ctx, c, restoreOf("backupRequest:orders"))
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("failed to get BackupRequest orders"))
+	})
+})
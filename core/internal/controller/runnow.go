@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/oiler-backup/base/proto"
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// runNowIfRequested starts a one-off backup Job if RunNowAnnotation of backupRequest
+// has a value not handled yet. The run is recorded to status of backupRequest,
+// which is updated by the caller. Failed run does not fail reconciliation,
+// since the schedule is applied anyway.
+func (r *BackupRequestReconciler) runNowIfRequested(
+	ctx context.Context,
+	adapter databaseAdapter,
+	backupRequest *backupv1.BackupRequest,
+	storage storageLocation,
+	overlay workloadOverlay,
+) {
+	requestedAt, requested := backupRequest.Annotations[backupv1.RunNowAnnotation]
+	previous := backupRequest.Status.ManualRun
+	if !requested || (previous != nil && previous.RequestedAt == requestedAt) {
+		return
+	}
+	log := log.FromContext(ctx).WithValues("requestedAt", requestedAt)
+
+	// Finished Job of the previous run is not needed anymore, its Backup is owned by backupRequest.
+	// Running one is left to finish.
+	if previous != nil && previous.JobName != "" &&
+		(previous.Phase == backupv1.RunPhaseSucceeded || previous.Phase == backupv1.RunPhaseFailed) {
+		job := &batchv1.Job{}
+		job.Name = previous.JobName
+		job.Namespace = backupRequest.Namespace
+		err := r.Delete(ctx, job, client.PropagationPolicy("Background"))
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "Failed to delete Job of previous manual run", "job", previous.JobName)
+		}
+	}
+
+	manualRun := &backupv1.ManualRun{
+		RequestedAt: requestedAt,
+		Phase:       backupv1.RunPhasePending,
+	}
+	job, err := r.createManualJob(ctx, adapter, backupRequest, storage, overlay)
+	if err != nil {
+		log.Error(err, "Failed to start manual backup")
+		manualRun.Phase = backupv1.RunPhaseFailed
+		manualRun.Message = err.Error()
+	} else {
+		log.Info("Started manual backup", "job", job.Name)
+		manualRun.JobName = job.Name
+	}
+	backupRequest.Status.ManualRun = manualRun
+}
+
+// createManualJob asks adapter to create a one-off Job from the template of
+// backuper CronJob and makes backupRequest its controller.
+func (r *BackupRequestReconciler) createManualJob(
+	ctx context.Context,
+	adapter databaseAdapter,
+	backupRequest *backupv1.BackupRequest,
+	storage storageLocation,
+	overlay workloadOverlay,
+) (*batchv1.Job, error) {
+	if err := adapter.supports(backupv1.AdapterCapabilityRunNow); err != nil {
+		return nil, err
+	}
+	conn, err := adapter.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to close connection to adapter", "address", adapter.address)
+		}
+	}()
+	client := pb.NewBackupServiceClient(conn)
+
+	req := buildBackupRequest(backupRequest, storage)
+
+	ctx = withRunOnce(ctx)
+	if !overlay.isEmpty() {
+		patch, err := overlay.cronJobPatch()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build overlay: %w", err)
+		}
+		ctx = withOverlay(ctx, patch)
+	}
+
+	resp, err := client.Backup(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to invoke backup method %s: %w", adapter.address, err)
+	}
+	if resp.CronjobName == "" {
+		return nil, fmt.Errorf("adapter failed to create Job: %s", resp.Status)
+	}
+
+	name := types.NamespacedName{
+		Namespace: resp.CronjobNamespace,
+		Name:      resp.CronjobName,
+	}
+	var job batchv1.Job
+	if err := r.Get(ctx, name, &job); err != nil {
+		return nil, err
+	}
+	if err := controllerutil.SetControllerReference(backupRequest, &job, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Update(ctx, &job); err != nil {
+		return nil, fmt.Errorf("failed to set owner of Job %s: %w", name, err)
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+var _ = Describe("Manual backup run", func() {
+	ctx := context.Background()
+	// Nothing listens there, so any call to adapter fails.
+	unreachableAdapter := databaseAdapter{dbType: "postgres", address: "127.0.0.1:1"}
+	reconciler := &BackupRequestReconciler{}
+	backupRequest := func(annotations map[string]string, manualRun *backupv1.ManualRun) *backupv1.BackupRequest {
+		return &backupv1.BackupRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "team-a", Annotations: annotations},
+			Status:     backupv1.BackupRequestStatus{ManualRun: manualRun},
+		}
+	}
+
+	It("should do nothing without annotation", func() {
+		br := backupRequest(nil, nil)
+		reconciler.runNowIfRequested(ctx, unreachableAdapter, br, storageLocation{}, workloadOverlay{})
+		Expect(br.Status.ManualRun).To(BeNil())
+	})
+
+	It("should not run the same request twice", func() {
+		manualRun := &backupv1.ManualRun{RequestedAt: "1", JobName: "backup-manual", Phase: backupv1.RunPhaseRunning}
+		br := backupRequest(map[string]string{backupv1.RunNowAnnotation: "1"}, manualRun.DeepCopy())
+		reconciler.runNowIfRequested(ctx, unreachableAdapter, br, storageLocation{}, workloadOverlay{})
+		Expect(br.Status.ManualRun).To(Equal(manualRun))
+	})
+
+	It("should report adapter failure", func() {
+		manualRun := &backupv1.ManualRun{RequestedAt: "1", JobName: "backup-manual", Phase: backupv1.RunPhaseRunning}
+		br := backupRequest(map[string]string{backupv1.RunNowAnnotation: "2"}, manualRun)
+		reconciler.runNowIfRequested(ctx, unreachableAdapter, br, storageLocation{}, workloadOverlay{})
+		Expect(br.Status.ManualRun.RequestedAt).To(Equal("2"))
+		Expect(br.Status.ManualRun.Phase).To(Equal(backupv1.RunPhaseFailed))
+		Expect(br.Status.ManualRun.JobName).To(BeEmpty())
+		Expect(br.Status.ManualRun.Message).To(ContainSubstring("failed to invoke backup method"))
+	})
+})
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// envSourceDbName tells restorer the database backups of which are restored.
+const envSourceDbName = "SOURCE_DB_NAME"
+
+// A restoreSource describes where backups restored by BackupRestore come from.
+type restoreSource struct {
+	// databaseName is a database backups were made of.
+	databaseName string
+	// backupRequest made the backups, if BackupRestore refers to it.
+	backupRequest *backupv1.BackupRequest
+}
+
+// isClone tells whether backups of another database are restored to the target one.
+func (s restoreSource) isClone(backupRestore *backupv1.BackupRestore) bool {
+	return s.databaseName != backupRestore.Spec.DatabaseName
+}
+
+// backupRequestName returns the name of BackupRequest which made the backups, if it is known.
+func (s restoreSource) backupRequestName() string {
+	if s.backupRequest == nil {
+		return ""
+	}
+	return s.backupRequest.Name
+}
+
+// resolveSource resolves Source of backupRestore.
+// Without Source backups of the target database are restored.
+func resolveSource(ctx context.Context, c client.Reader, backupRestore *backupv1.BackupRestore) (restoreSource, error) {
+	source := restoreSource{databaseName: backupRestore.Spec.DatabaseName}
+	spec := backupRestore.Spec.Source
+	switch {
+	case spec == nil:
+	case spec.BackupRequestName != "":
+		return backupRequestSource(ctx, c, backupRestore, spec.BackupRequestName)
+	case spec.DatabaseName != "":
+		source.databaseName = spec.DatabaseName
+	}
+	return source, nil
+}
+
+// backupRequestSource resolves BackupRequest name in the namespace of backupRestore
+// as the source of backups.
+func backupRequestSource(
+	ctx context.Context,
+	c client.Reader,
+	backupRestore *backupv1.BackupRestore,
+	name string,
+) (restoreSource, error) {
+	var backupRequest backupv1.BackupRequest
+	if err := c.Get(ctx, types.NamespacedName{Namespace: backupRestore.Namespace, Name: name}, &backupRequest); err != nil {
+		return restoreSource{}, fmt.Errorf("failed to get BackupRequest %s: %w", name, err)
+	}
+	if dbType := backupRequest.Spec.DbSpec.DbType; dbType != backupRestore.Spec.DatabaseType {
+		return restoreSource{}, fmt.Errorf("BackupRequest %s backs up %s database, not %s",
+			name, dbType, backupRestore.Spec.DatabaseType)
+	}
+	return restoreSource{databaseName: backupRequest.Spec.DbSpec.DbName, backupRequest: &backupRequest}, nil
+}
+
+// hasStorage tells whether backupRestore sets any of its storage fields.
+func hasStorage(backupRestore *backupv1.BackupRestore) bool {
+	spec := backupRestore.Spec
+	return spec.StorageLocationName != "" || spec.S3Endpoint != "" || spec.S3BucketName != "" ||
+		spec.S3AccessKey != "" || spec.S3SecretKey != "" || spec.S3CredentialsSecretRef != nil
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+var _ = Describe("Restore source", func() {
+	ctx := context.Background()
+	var scheme *runtime.Scheme
+	var backupRequest *backupv1.BackupRequest
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(backupv1.AddToScheme(scheme)).To(Succeed())
+		backupRequest = &backupv1.BackupRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "team-a"},
+			Spec: backupv1.BackupRequestSpec{
+				DbSpec: backupv1.DatabaseSpec{DbName: "orders", DbType: "postgres"},
+				S3Spec: backupv1.S3Spec{
+					Endpoint:   "http://minio:9000",
+					BucketName: "orders-backups",
+					Auth:       backupv1.S3Auth{AccessKey: "key", SecretKey: "secret"},
+				},
+			},
+		}
+	})
+
+	cloneTo := func(dbName string, source *backupv1.RestoreSource) *backupv1.BackupRestore {
+		return &backupv1.BackupRestore{
+			ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: "team-a"},
+			Spec: backupv1.BackupRestoreSpec{
+				DatabaseName: dbName,
+				DatabaseType: "postgres",
+				Source:       source,
+			},
+		}
+	}
+
+	It("restores backups of the target database by default", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		backupRestore := cloneTo("orders", nil)
+
+		source, err := resolveSource(ctx, c, backupRestore)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source.databaseName).To(Equal("orders"))
+		Expect(source.isClone(backupRestore)).To(BeFalse())
+	})
+
+	It("restores backups of another database", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		backupRestore := cloneTo("orders_staging", &backupv1.RestoreSource{DatabaseName: "orders"})
+
+		source, err := resolveSource(ctx, c, backupRestore)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source.databaseName).To(Equal("orders"))
+		Expect(source.backupRequest).To(BeNil())
+		Expect(source.isClone(backupRestore)).To(BeTrue())
+	})
+
+	It("restores backups of BackupRequest with its storage", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(backupRequest).Build()
+		r := &BackupRestoreReconciler{Client: c, Scheme: scheme}
+		backupRestore := cloneTo("orders_staging", &backupv1.RestoreSource{BackupRequestName: "orders"})
+
+		source, err := resolveSource(ctx, c, backupRestore)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source.databaseName).To(Equal("orders"))
+		Expect(source.isClone(backupRestore)).To(BeTrue())
+
+		storage, err := r.resolveStorage(ctx, backupRestore, source)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(storage.endpoint).To(Equal("http://minio:9000"))
+		Expect(storage.bucketName).To(Equal("orders-backups"))
+		accessKey, secretKey := storage.plainKeys()
+		Expect(accessKey).To(Equal("key"))
+		Expect(secretKey).To(Equal("secret"))
+	})
+
+	It("prefers storage of BackupRestore", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(backupRequest).Build()
+		r := &BackupRestoreReconciler{Client: c, Scheme: scheme}
+		backupRestore := cloneTo("orders_staging", &backupv1.RestoreSource{BackupRequestName: "orders"})
+		backupRestore.Spec.S3BucketName = "copied-backups"
+
+		source, err := resolveSource(ctx, c, backupRestore)
+		Expect(err).NotTo(HaveOccurred())
+		storage, err := r.resolveStorage(ctx, backupRestore, source)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(storage.bucketName).To(Equal("copied-backups"))
+		Expect(storage.endpoint).To(BeEmpty())
+	})
+
+	It("fails if BackupRequest is not found", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		_, err := resolveSource(ctx, c, cloneTo("orders", &backupv1.RestoreSource{BackupRequestName: "orders"}))
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("fails if BackupRequest backs up another type of database", func() {
+		backupRequest.Spec.DbSpec.DbType = "mysql"
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(backupRequest).Build()
+
+		_, err := resolveSource(ctx, c, cloneTo("orders", &backupv1.RestoreSource{BackupRequestName: "orders"}))
+		Expect(err).To(MatchError("BackupRequest orders backs up mysql database, not postgres"))
+	})
+})
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	envS3AccessKey = "S3_ACCESS_KEY"
+	envS3SecretKey = "S3_SECRET_KEY"
+
+	defaultAccessKeyKey = "accessKey"
+	defaultSecretKeyKey = "secretKey"
+)
+
+// A storageLocation is an S3-compatible storage resolved from spec of a resource
+// and BackupStorageLocation it refers to.
+type storageLocation struct {
+	endpoint       string
+	bucketName     string
+	accessKey      string
+	secretKey      string
+	credentialsRef *backupv1.S3CredentialsSecretRef
+	// credentialsNamespace is a namespace of credentialsRef if it has none.
+	credentialsNamespace string
+}
+
+// resolveStorageLocation merges inline with BackupStorageLocation locationName.
+// Credentials of inline take precedence. Endpoint and bucket of inline may not override
+// the location, otherwise its credentials would be sent to a storage of the user's choice.
+func resolveStorageLocation(ctx context.Context, r client.Reader, locationName string, inline storageLocation) (storageLocation, error) {
+	if locationName == "" {
+		return inline, nil
+	}
+	if inline.endpoint != "" || inline.bucketName != "" {
+		return storageLocation{}, fmt.Errorf("endpoint and bucket may not override BackupStorageLocation %s", locationName)
+	}
+
+	var bsl backupv1.BackupStorageLocation
+	if err := r.Get(ctx, client.ObjectKey{Name: locationName}, &bsl); err != nil {
+		return storageLocation{}, fmt.Errorf("unable to get BackupStorageLocation %s: %w", locationName, err)
+	}
+
+	resolved := storageLocation{
+		endpoint:             bsl.Spec.Endpoint,
+		bucketName:           bsl.Spec.BucketName,
+		credentialsRef:       &bsl.Spec.CredentialsSecretRef,
+		credentialsNamespace: appCfg.OperatorNamespace,
+	}
+	if inline.credentialsRef != nil {
+		resolved.credentialsRef = inline.credentialsRef
+		resolved.credentialsNamespace = inline.credentialsNamespace
+	} else if inline.accessKey != "" || inline.secretKey != "" {
+		resolved.credentialsRef = nil
+		resolved.accessKey = inline.accessKey
+		resolved.secretKey = inline.secretKey
+	}
+
+	return resolved, nil
+}
+
+// plainKeys returns S3 keys which can be passed to adapter.
+// Keys referenced by Secret are never passed.
+func (s storageLocation) plainKeys() (string, string) {
+	if s.credentialsRef != nil {
+		return "", ""
+	}
+	return s.accessKey, s.secretKey
+}
+
+// secretData resolves S3 keys referenced by s into envs of a managed Secret.
+// Returns nil if s has no reference.
+func (s storageLocation) secretData(ctx context.Context, r client.Reader) (map[string][]byte, error) {
+	ref := s.credentialsRef
+	if ref == nil {
+		return nil, nil
+	}
+
+	accessKey, err := resolveSecretKey(ctx, r, &backupv1.SecretKeyReference{
+		Name:      ref.Name,
+		Namespace: ref.Namespace,
+		Key:       keyOrDefault(ref.AccessKeyKey, defaultAccessKeyKey),
+	}, s.credentialsNamespace)
+	if err != nil {
+		return nil, err
+	}
+	secretKey, err := resolveSecretKey(ctx, r, &backupv1.SecretKeyReference{
+		Name:      ref.Name,
+		Namespace: ref.Namespace,
+		Key:       keyOrDefault(ref.SecretKeyKey, defaultSecretKeyKey),
+	}, s.credentialsNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{
+		envS3AccessKey: accessKey,
+		envS3SecretKey: secretKey,
+	}, nil
+}
+
+func keyOrDefault(key, defaultKey string) string {
+	if key == "" {
+		return defaultKey
+	}
+	return key
+}
+
+// isSameSecret reports whether reference refName in refNamespace points to Secret name in namespace.
+// Reference without namespace points to defaultNamespace.
+func isSameSecret(refName, refNamespace, defaultNamespace, name, namespace string) bool {
+	if refNamespace == "" {
+		refNamespace = defaultNamespace
+	}
+	return refName == name && refNamespace == namespace
+}
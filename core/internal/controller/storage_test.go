@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+var _ = Describe("resolveStorageLocation", func() {
+	ctx := context.Background()
+	var r *fake.ClientBuilder
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(backupv1.AddToScheme(scheme)).To(Succeed())
+		r = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&backupv1.BackupStorageLocation{
+			ObjectMeta: metav1.ObjectMeta{Name: "minio"},
+			Spec: backupv1.BackupStorageLocationSpec{
+				Endpoint:             "http://minio:9000",
+				BucketName:           "backups",
+				CredentialsSecretRef: backupv1.S3CredentialsSecretRef{Name: "minio-credentials"},
+			},
+		})
+	})
+
+	It("Should let inline credentials override BackupStorageLocation", func() {
+		storage, err := resolveStorageLocation(ctx, r.Build(), "minio", storageLocation{accessKey: "key", secretKey: "secret"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(storage.endpoint).To(Equal("http://minio:9000"))
+		Expect(storage.bucketName).To(Equal("backups"))
+		accessKey, secretKey := storage.plainKeys()
+		Expect(accessKey).To(Equal("key"))
+		Expect(secretKey).To(Equal("secret"))
+	})
+
+	It("Should not send credentials of BackupStorageLocation to another storage", func() {
+		for _, inline := range []storageLocation{{endpoint: "http://evil:9000"}, {bucketName: "stolen"}} {
+			_, err := resolveStorageLocation(ctx, r.Build(), "minio", inline)
+			Expect(err).To(MatchError("endpoint and bucket may not override BackupStorageLocation minio"))
+		}
+	})
+})
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/oiler-backup/core/common/schedule"
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// envChecks is an env of restorer with checks it runs after restore.
+	envChecks = "CHECKS"
+	// verificationJobLabel marks a Job verifying backups for BackupVerification named by the value.
+	verificationJobLabel = "backup.oiler.backup/verification"
+	// throwawayContainerName is a name of the sidecar running throwaway database.
+	throwawayContainerName = "throwaway-database"
+	// throwawayHost is where restorer reaches the sidecar, since they share the pod network.
+	throwawayHost = "127.0.0.1"
+)
+
+// A throwawayDatabase describes a database server a backup is restored into for verification.
+// Restorer authenticates as user with password read from envDbPassword.
+type throwawayDatabase struct {
+	image string
+	port  int32
+	user  string
+	// env configures the server to create database dbName. Password is taken from envDbPassword.
+	env func(dbName string) []corev1.EnvVar
+	// probe succeeds once the server accepts connections over TCP.
+	probe []string
+}
+
+var throwawayDatabases = map[string]throwawayDatabase{
+	"postgres": {
+		image: "postgres:16-alpine",
+		port:  5432,
+		user:  "postgres",
+		env: func(dbName string) []corev1.EnvVar {
+			return []corev1.EnvVar{
+				{Name: "POSTGRES_PASSWORD", Value: "$(" + envDbPassword + ")"},
+				{Name: "POSTGRES_DB", Value: dbName},
+			}
+		},
+		probe: []string{"pg_isready", "-h", throwawayHost, "-U", "postgres"},
+	},
+	"mysql": {
+		image: "mysql:8.0",
+		port:  3306,
+		user:  "root",
+		env: func(dbName string) []corev1.EnvVar {
+			return []corev1.EnvVar{
+				{Name: "MYSQL_ROOT_PASSWORD", Value: "$(" + envDbPassword + ")"},
+				{Name: "MYSQL_DATABASE", Value: dbName},
+			}
+		},
+		probe: []string{"mysqladmin", "ping", "-h", throwawayHost, "--silent"},
+	},
+	"mongodb": {
+		image: "mongo:7",
+		port:  27017,
+		user:  "root",
+		env: func(dbName string) []corev1.EnvVar {
+			return []corev1.EnvVar{
+				{Name: "MONGO_INITDB_ROOT_USERNAME", Value: "root"},
+				{Name: "MONGO_INITDB_ROOT_PASSWORD", Value: "$(" + envDbPassword + ")"},
+				{Name: "MONGO_INITDB_DATABASE", Value: dbName},
+			}
+		},
+		probe: []string{"sh", "-c", `mongosh --quiet --host ` + throwawayHost +
+			` -u "$MONGO_INITDB_ROOT_USERNAME" -p "$MONGO_INITDB_ROOT_PASSWORD" --authenticationDatabase admin` +
+			` --eval 'db.adminCommand("ping")'`},
+	},
+}
+
+// container renders the sidecar running database dbName from image.
+// Password is read from envDbPassword of credentialsSecret.
+func (d throwawayDatabase) container(image, dbName, credentialsSecret string) corev1.Container {
+	if image == "" {
+		image = d.image
+	}
+	password := corev1.EnvVar{
+		Name: envDbPassword,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: credentialsSecret},
+				Key:                  envDbPassword,
+			},
+		},
+	}
+	return corev1.Container{
+		Name:  throwawayContainerName,
+		Image: image,
+		Env:   append([]corev1.EnvVar{password}, d.env(dbName)...),
+		Ports: []corev1.ContainerPort{{ContainerPort: d.port}},
+		StartupProbe: &corev1.Probe{
+			ProbeHandler:     corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: d.probe}},
+			PeriodSeconds:    2,
+			FailureThreshold: 150,
+		},
+	}
+}
+
+// randomPassword generates a password of throwaway database.
+func randomPassword() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// prepareVerificationCredentials copies S3 keys of storage and password of throwaway database
+// to a Secret managed by operator. Returns overlay which makes restorer read them from the Secret.
+func prepareVerificationCredentials(
+	ctx context.Context,
+	c client.Client,
+	scheme *runtime.Scheme,
+	verification *backupv1.BackupVerification,
+	secretName, password string,
+	storage storageLocation,
+) (workloadOverlay, error) {
+	overlay := workloadOverlay{}.inNamespace(verification.Namespace)
+
+	data, err := storage.secretData(ctx, c)
+	if err != nil {
+		return overlay, err
+	}
+	if data == nil {
+		data = map[string][]byte{}
+	}
+	data[envDbPassword] = []byte(password)
+
+	err = syncCredentialsSecret(ctx, c, scheme, verification, secretName, verification.Namespace, data)
+	if err != nil {
+		return overlay, err
+	}
+
+	return overlay.withSecretEnv(secretName, slices.Sorted(maps.Keys(data))...), nil
+}
+
+// checksEnv renders checks as CHECKS env of restorer.
+func checksEnv(checks []backupv1.VerificationCheck) (string, error) {
+	type check struct {
+		Name    string `json:"name"`
+		Query   string `json:"query"`
+		MinRows int64  `json:"minRows"`
+	}
+	list := make([]check, 0, len(checks))
+	for _, c := range checks {
+		minRows := int64(1)
+		if c.MinRows != nil {
+			minRows = *c.MinRows
+		}
+		list = append(list, check{Name: c.Name, Query: c.Query, MinRows: minRows})
+	}
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		return "", fmt.Errorf("failed to render checks: %w", err)
+	}
+	return string(data), nil
+}
+
+// verificationSchedule parses schedule of verification with the grammar of BackupRequest schedules.
+// Hashed values are expanded with namespace and name of verification as the seed, so
+// verifications with the same schedule do not restore backups at the same moment.
+func verificationSchedule(verification *backupv1.BackupVerification) (cron.Schedule, error) {
+	if err := schedule.Validate(verification.Spec.Schedule); err != nil {
+		return nil, err
+	}
+	expanded, err := schedule.Expand(verification.Spec.Schedule, verification.Namespace+"/"+verification.Name)
+	if err != nil {
+		return nil, err
+	}
+	return cron.ParseStandard(expanded)
+}
+
+// nextVerification returns when the verification after the last one is due.
+// The first one is due by schedule after verification was created.
+func nextVerification(verification *backupv1.BackupVerification, schedule cron.Schedule) time.Time {
+	last := verification.CreationTimestamp.Time
+	if verification.Status.LastScheduleTime != nil {
+		last = verification.Status.LastScheduleTime.Time
+	}
+	return schedule.Next(last)
+}
+
+// verificationRun describes finished job of verification running checks. It returns false if job
+// is not finished yet. A run passes if the backup is restored and every check passes. A check without
+// a result in the report fails, so a lost or truncated report does not pass the run.
+func verificationRun(job *batchv1.Job, pods []corev1.Pod, checks []backupv1.VerificationCheck) (backupv1.VerificationRun, bool) {
+	condition, finished := jobFinished(job)
+	if !finished {
+		return backupv1.VerificationRun{}, false
+	}
+
+	run := backupv1.VerificationRun{
+		JobName:        job.Name,
+		StartTime:      job.Status.StartTime,
+		CompletionTime: jobFinishTime(job),
+	}
+	if run.StartTime != nil && run.CompletionTime != nil {
+		run.Duration = &metav1.Duration{Duration: run.CompletionTime.Sub(run.StartTime.Time)}
+	}
+
+	if condition.Type != batchv1.JobComplete {
+		run.Message = condition.Message
+		if message := podsMessage(pods); message != "" {
+			run.Message += ": " + message
+		}
+		return run, true
+	}
+
+	report := readRunReport(pods, restorerContainerName)
+	run.Key = report.Key
+	run.Checks = report.Checks
+	for _, check := range checks {
+		if !slices.ContainsFunc(run.Checks, func(result backupv1.CheckResult) bool { return result.Name == check.Name }) {
+			run.Checks = append(run.Checks, backupv1.CheckResult{Name: check.Name, Message: "restorer reported no result"})
+		}
+	}
+
+	var failed []string
+	for _, check := range run.Checks {
+		if !check.Passed {
+			failed = append(failed, check.Name)
+		}
+	}
+	run.Passed = len(failed) == 0
+	if !run.Passed {
+		run.Message = "checks failed: " + strings.Join(failed, ", ")
+	}
+	return run, true
+}
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+var _ = Describe("Backup verification", func() {
+	verificationJob := func(conditionType batchv1.JobConditionType) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "restore-1a2b3c4d-orders"},
+			Status: batchv1.JobStatus{
+				StartTime:      ptr.To(metav1.Unix(100, 0)),
+				CompletionTime: ptr.To(metav1.Unix(160, 0)),
+				Conditions: []batchv1.JobCondition{{
+					Type:    conditionType,
+					Status:  corev1.ConditionTrue,
+					Message: "Job has reached the specified backoff limit",
+				}},
+			},
+		}
+	}
+	restorerPod := func(report string) corev1.Pod {
+		return corev1.Pod{
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: restorerContainerName,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Message: report},
+					},
+				}},
+			},
+		}
+	}
+
+	It("passes checks to restorer with default minimum of rows", func() {
+		checks, err := checksEnv([]backupv1.VerificationCheck{
+			{Name: "orders", Query: "SELECT id FROM orders"},
+			{Name: "audit", Query: "SELECT id FROM audit", MinRows: ptr.To(int64(0))},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checks).To(MatchJSON(`[
+			{"name":"orders","query":"SELECT id FROM orders","minRows":1},
+			{"name":"audit","query":"SELECT id FROM audit","minRows":0}
+		]`))
+
+		checks, err = checksEnv(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checks).To(Equal("[]"))
+	})
+
+	It("schedules the first verification after creation", func() {
+		schedule, err := cron.ParseStandard("0 6 * * *")
+		Expect(err).NotTo(HaveOccurred())
+		verification := &backupv1.BackupVerification{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)},
+		}
+		Expect(nextVerification(verification, schedule)).To(Equal(time.Date(2025, 5, 2, 6, 0, 0, 0, time.UTC)))
+
+		verification.Status.LastScheduleTime = ptr.To(metav1.Date(2025, 5, 3, 6, 0, 1, 0, time.UTC))
+		Expect(nextVerification(verification, schedule)).To(Equal(time.Date(2025, 5, 4, 6, 0, 0, 0, time.UTC)))
+	})
+
+	It("runs throwaway database as a sidecar sharing password with restorer", func() {
+		container := throwawayDatabases["postgres"].container("", "orders", "oiler-bv-orders-credentials")
+		Expect(container.Name).To(Equal(throwawayContainerName))
+		Expect(container.Image).To(Equal("postgres:16-alpine"))
+		Expect(container.Env).To(HaveLen(3))
+		Expect(container.Env[0].Name).To(Equal(envDbPassword))
+		Expect(container.Env[0].ValueFrom.SecretKeyRef.Name).To(Equal("oiler-bv-orders-credentials"))
+		Expect(container.Env[1]).To(Equal(corev1.EnvVar{Name: "POSTGRES_PASSWORD", Value: "$(DB_PASSWORD)"}))
+		Expect(container.Env[2]).To(Equal(corev1.EnvVar{Name: "POSTGRES_DB", Value: "orders"}))
+		Expect(container.StartupProbe.Exec.Command).To(ContainElement("pg_isready"))
+
+		container = throwawayDatabases["mysql"].container("mysql:8.4", "orders", "oiler-bv-orders-credentials")
+		Expect(container.Image).To(Equal("mysql:8.4"))
+	})
+
+	It("knows throwaway database of every supported type", func() {
+		for _, dbType := range []string{"postgres", "mysql", "mongodb"} {
+			Expect(throwawayDatabases).To(HaveKey(dbType))
+		}
+	})
+
+	It("passes run which restored backup and passed checks", func() {
+		pods := []corev1.Pod{restorerPod(`{"key":"orders/2025-05-01-backup.sql","checks":[
+			{"name":"orders","passed":true,"rows":120,"duration":"15ms"}
+		]}`)}
+		run, finished := verificationRun(verificationJob(batchv1.JobComplete), pods, []backupv1.VerificationCheck{{Name: "orders"}})
+		Expect(finished).To(BeTrue())
+		Expect(run.Passed).To(BeTrue())
+		Expect(run.JobName).To(Equal("restore-1a2b3c4d-orders"))
+		Expect(run.Key).To(Equal("orders/2025-05-01-backup.sql"))
+		Expect(run.Duration.Duration).To(Equal(time.Minute))
+		Expect(run.Checks).To(Equal([]backupv1.CheckResult{{Name: "orders", Passed: true, Rows: 120, Duration: "15ms"}}))
+		Expect(run.Message).To(BeEmpty())
+	})
+
+	It("fails run with failed checks", func() {
+		pods := []corev1.Pod{restorerPod(`{"key":"orders/2025-05-01-backup.sql","checks":[
+			{"name":"orders","passed":false,"rows":0,"duration":"15ms","message":"query returned 0 rows, expected at least 1"},
+			{"name":"users","passed":true,"rows":1,"duration":"1ms"},
+			{"name":"audit","passed":false,"rows":0,"duration":"1ms","message":"relation \"audit\" does not exist"}
+		]}`)}
+		run, finished := verificationRun(verificationJob(batchv1.JobComplete), pods,
+			[]backupv1.VerificationCheck{{Name: "orders"}, {Name: "users"}, {Name: "audit"}})
+		Expect(finished).To(BeTrue())
+		Expect(run.Passed).To(BeFalse())
+		Expect(run.Checks).To(HaveLen(3))
+		Expect(run.Message).To(Equal("checks failed: orders, audit"))
+	})
+
+	It("fails run whose report lacks results of checks", func() {
+		pods := []corev1.Pod{restorerPod(`{"key":"orders/2025-05-01-backup.sql","checks":[
+			{"name":"orders","passed":true,"rows":120,"duration":"15ms"}
+		]}`)}
+		checks := []backupv1.VerificationCheck{{Name: "orders"}, {Name: "users"}}
+		run, finished := verificationRun(verificationJob(batchv1.JobComplete), pods, checks)
+		Expect(finished).To(BeTrue())
+		Expect(run.Passed).To(BeFalse())
+		Expect(run.Checks).To(ContainElement(backupv1.CheckResult{Name: "users", Message: "restorer reported no result"}))
+		Expect(run.Message).To(Equal("checks failed: users"))
+
+		run, _ = verificationRun(verificationJob(batchv1.JobComplete), []corev1.Pod{restorerPod("")}, checks)
+		Expect(run.Passed).To(BeFalse())
+		Expect(run.Message).To(Equal("checks failed: orders, users"))
+	})
+
+	It("fails run whose restore failed", func() {
+		run, finished := verificationRun(verificationJob(batchv1.JobFailed), nil, nil)
+		Expect(finished).To(BeTrue())
+		Expect(run.Passed).To(BeFalse())
+		Expect(run.Message).To(Equal("Job has reached the specified backoff limit"))
+	})
+
+	It("waits for running Job", func() {
+		_, finished := verificationRun(&batchv1.Job{}, nil, nil)
+		Expect(finished).To(BeFalse())
+	})
+})
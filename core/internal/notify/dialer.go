@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"syscall"
+)
+
+// sharedAddressSpace is 100.64.0.0/10, which some clusters take pod or service addresses from.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// A Dialer connects sinks to their servers. URLs and addresses of sinks are set by users
+// of BackupRequests, so connections to loopback, link-local, private and other addresses
+// internal to the cluster are refused, including those a public name resolves to.
+// The zero Dialer refuses all of them.
+type Dialer struct {
+	// AllowedNetworks are reachable even though they are internal, e.g. the network
+	// of an SMTP relay in the cluster.
+	AllowedNetworks []netip.Prefix
+}
+
+// DialContext connects to address like net.Dialer, unless it is refused.
+func (d Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: requestTimeout, Control: d.control}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// Client returns an HTTP client connecting through d. Proxies are not used,
+// since the address of a proxy tells nothing about the address of a sink.
+func (d Dialer) Client() *http.Client {
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: &http.Transport{DialContext: d.DialContext, ForceAttemptHTTP2: true},
+	}
+}
+
+// control checks the address a connection is made to, after its name is resolved.
+func (d Dialer) control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if addr := addrPort.Addr().Unmap(); !d.allowed(addr) {
+		return fmt.Errorf("address %s is internal to the cluster and not allowed for notifications", addr)
+	}
+	return nil
+}
+
+// allowed tells whether connections to addr are allowed.
+func (d Dialer) allowed(addr netip.Addr) bool {
+	if slices.ContainsFunc(d.AllowedNetworks, func(network netip.Prefix) bool { return network.Contains(addr) }) {
+		return true
+	}
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
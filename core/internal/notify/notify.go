@@ -0,0 +1,104 @@
+// Package notify sends notifications about backups and restores to HTTP webhooks,
+// Slack-compatible incoming webhooks and email.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// A Notification tells about an event of BackupRequest.
+type Notification struct {
+	Event         string `json:"event"`
+	Namespace     string `json:"namespace"`
+	BackupRequest string `json:"backupRequest"`
+	// Object is a name of the Job or the BackupRestore the notification is about.
+	Object  string    `json:"object,omitempty"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// Subject is a one-line summary of n.
+func (n Notification) Subject() string {
+	return fmt.Sprintf("%s: BackupRequest %s/%s", n.Event, n.Namespace, n.BackupRequest)
+}
+
+// A Sink receives notifications.
+type Sink interface {
+	Send(ctx context.Context, n Notification) error
+}
+
+const (
+	defaultAttempts = 3
+	defaultBackoff  = time.Second
+	defaultTimeout  = 30 * time.Second
+)
+
+// A Sender sends notifications to sinks, retrying failed attempts.
+type Sender struct {
+	// Attempts is how many times a sink is tried, 3 by default.
+	Attempts int
+	// Backoff is a delay before the second attempt, doubled before each next one. 1s by default.
+	Backoff time.Duration
+	// Timeout bounds sending to all sinks with retries, so a slow sink does not block
+	// the reconciliation. 30s by default.
+	Timeout time.Duration
+	// Dialer connects sinks built for the Sender to their servers.
+	Dialer Dialer
+}
+
+// Send sends n to each of sinks by name. The error lists sinks which failed all attempts.
+func (s Sender) Send(ctx context.Context, sinks map[string]Sink, n Notification) error {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(sinks)) {
+		if err := s.send(ctx, sinks[name], n); err != nil {
+			errs = append(errs, fmt.Errorf("sink %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s Sender) send(ctx context.Context, sink Sink, n Notification) error {
+	attempts, backoff := s.Attempts, s.Backoff
+	if attempts <= 0 {
+		attempts = defaultAttempts
+	}
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = sink.Send(ctx, n); err == nil || attempt == attempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// text is a plain-text body of n without the subject.
+func text(n Notification) string {
+	var lines []string
+	if n.Object != "" {
+		lines = append(lines, "Object: "+n.Object)
+	}
+	lines = append(lines, "Time: "+n.Time.UTC().Format(time.RFC3339), "", n.Message)
+	return strings.Join(lines, "\n")
+}
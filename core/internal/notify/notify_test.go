@@ -0,0 +1,198 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/smtp"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// flakySink fails the first failures sends.
+type flakySink struct {
+	failures int
+	sent     int
+}
+
+func (s *flakySink) Send(context.Context, Notification) error {
+	s.sent++
+	if s.sent <= s.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+// blockingSink does not answer until ctx is done.
+type blockingSink struct{}
+
+func (blockingSink) Send(ctx context.Context, _ Notification) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+var _ = Describe("Notifications", func() {
+	ctx := context.Background()
+	n := Notification{
+		Event:         "BackupFailed",
+		Namespace:     "team-a",
+		BackupRequest: "orders",
+		Object:        "orders-backup-1",
+		Message:       "Job orders-backup-1 failed: exited with code 1",
+		Time:          time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	// loopback reaches the test servers, which sinks may not reach by default.
+	loopback := Dialer{AllowedNetworks: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}}
+
+	var requests []*http.Request
+	var bodies []string
+	var server *httptest.Server
+	BeforeEach(func() {
+		requests, bodies = nil, nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			requests, bodies = append(requests, r), append(bodies, string(data))
+			if r.URL.Path == "/broken" {
+				http.Error(w, "no such hook", http.StatusNotFound)
+			}
+		}))
+		DeferCleanup(server.Close)
+	})
+
+	It("retries sinks and reports those failed all attempts", func() {
+		recovering, broken := &flakySink{failures: 2}, &flakySink{failures: 5}
+		sender := Sender{Attempts: 3, Backoff: time.Millisecond}
+
+		err := sender.Send(ctx, map[string]Sink{"recovering": recovering, "broken": broken}, n)
+		Expect(err).To(MatchError("sink broken: connection refused"))
+		Expect(recovering.sent).To(Equal(3))
+		Expect(broken.sent).To(Equal(3))
+	})
+
+	It("gives up on sinks after timeout", func() {
+		blocked := blockingSink{}
+		sender := Sender{Attempts: 3, Backoff: time.Millisecond, Timeout: 10 * time.Millisecond}
+
+		err := sender.Send(ctx, map[string]Sink{"blocked": blocked}, n)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+
+	It("gives up on a silent SMTP server when context is done", func() {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(lis.Close)
+		go func() {
+			// Accept connections and never greet.
+			for {
+				if _, err := lis.Accept(); err != nil {
+					return
+				}
+			}
+		}()
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		err = loopback.sendMail(ctx, lis.Addr().String(), nil, "oiler@example.com", []string{"dba@example.com"}, []byte("test"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("posts notification as JSON by default", func() {
+		webhook, err := NewWebhook(server.URL, map[string]string{"Authorization": "Bearer t"}, "")
+		Expect(err).NotTo(HaveOccurred())
+		webhook.Client = loopback.Client()
+		Expect(webhook.Send(ctx, n)).To(Succeed())
+
+		Expect(requests[0].Header.Get("Authorization")).To(Equal("Bearer t"))
+		Expect(requests[0].Header.Get("Content-Type")).To(Equal("application/json"))
+		Expect(bodies[0]).To(MatchJSON(`{
+			"event":"BackupFailed","namespace":"team-a","backupRequest":"orders","object":"orders-backup-1",
+			"message":"Job orders-backup-1 failed: exited with code 1","time":"2025-05-01T10:00:00Z"
+		}`))
+	})
+
+	It("renders body template", func() {
+		webhook, err := NewWebhook(server.URL, nil, `{"summary":{{ json .Subject }},"text":{{ json .Message }}}`)
+		Expect(err).NotTo(HaveOccurred())
+		webhook.Client = loopback.Client()
+		Expect(webhook.Send(ctx, n)).To(Succeed())
+		Expect(bodies[0]).To(MatchJSON(`{
+			"summary":"BackupFailed: BackupRequest team-a/orders",
+			"text":"Job orders-backup-1 failed: exited with code 1"
+		}`))
+
+		_, err = NewWebhook(server.URL, nil, `{{ .Message`)
+		Expect(err).To(MatchError(ContainSubstring("invalid body template")))
+	})
+
+	It("fails on status codes other than 2xx", func() {
+		webhook, err := NewWebhook(server.URL+"/broken", nil, "")
+		Expect(err).NotTo(HaveOccurred())
+		webhook.Client = loopback.Client()
+		Expect(webhook.Send(ctx, n)).To(MatchError("webhook returned 404 Not Found"))
+	})
+
+	It("posts Slack-compatible payload", func() {
+		slack := &Slack{URL: server.URL, Channel: "#backups", Client: loopback.Client()}
+		Expect(slack.Send(ctx, n)).To(Succeed())
+
+		var payload map[string]string
+		Expect(json.Unmarshal([]byte(bodies[0]), &payload)).To(Succeed())
+		Expect(payload["channel"]).To(Equal("#backups"))
+		Expect(payload["text"]).To(Equal("*BackupFailed: BackupRequest team-a/orders*\n" +
+			"Object: orders-backup-1\nTime: 2025-05-01T10:00:00Z\n\nJob orders-backup-1 failed: exited with code 1"))
+	})
+
+	It("refuses addresses internal to the cluster", func() {
+		webhook, err := NewWebhook(server.URL, nil, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(webhook.Send(ctx, n)).To(MatchError(ContainSubstring("address 127.0.0.1 is internal to the cluster")))
+		Expect(requests).To(BeEmpty())
+
+		email := &Email{Address: server.Listener.Addr().String(), From: "oiler@example.com", To: []string{"dba@example.com"}}
+		Expect(email.Send(ctx, n)).To(MatchError(ContainSubstring("address 127.0.0.1 is internal to the cluster")))
+
+		for _, addr := range []string{"127.0.0.1", "::1", "10.96.0.1", "172.16.0.1", "192.168.1.1", "100.64.0.1",
+			"169.254.169.254", "fe80::1", "fd00::1", "0.0.0.0", "::ffff:10.0.0.1", "224.0.0.1"} {
+			Expect(Dialer{}.allowed(netip.MustParseAddr(addr).Unmap())).To(BeFalse(), addr)
+		}
+		Expect(Dialer{}.allowed(netip.MustParseAddr("93.184.216.34"))).To(BeTrue())
+		Expect(Dialer{}.allowed(netip.MustParseAddr("2606:4700::1111"))).To(BeTrue())
+		Expect(loopback.allowed(netip.MustParseAddr("127.0.0.1"))).To(BeTrue())
+	})
+
+	It("sends email", func() {
+		var addr, from string
+		var to []string
+		var auth smtp.Auth
+		var msg []byte
+		email := &Email{
+			Address:  "smtp.example.com:587",
+			From:     "oiler@example.com",
+			To:       []string{"dba@example.com", "oncall@example.com"},
+			Username: "oiler",
+			Password: "secret",
+			SendMail: func(_ context.Context, a string, au smtp.Auth, f string, t []string, m []byte) error {
+				addr, auth, from, to, msg = a, au, f, t, m
+				return nil
+			},
+		}
+		Expect(email.Send(ctx, n)).To(Succeed())
+
+		Expect(addr).To(Equal("smtp.example.com:587"))
+		Expect(auth).NotTo(BeNil())
+		Expect(from).To(Equal("oiler@example.com"))
+		Expect(to).To(ConsistOf("dba@example.com", "oncall@example.com"))
+		Expect(string(msg)).To(ContainSubstring("To: dba@example.com, oncall@example.com\r\n"))
+		Expect(string(msg)).To(ContainSubstring("Subject: [oiler-backup] BackupFailed: BackupRequest team-a/orders\r\n"))
+		Expect(string(msg)).To(HaveSuffix("\r\n\r\nObject: orders-backup-1\r\nTime: 2025-05-01T10:00:00Z\r\n\r\n" +
+			"Job orders-backup-1 failed: exited with code 1\r\n"))
+	})
+})
@@ -0,0 +1,222 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// requestTimeout bounds a request to a webhook or a conversation with an SMTP server.
+const requestTimeout = 10 * time.Second
+
+// defaultClient posts to webhooks without Client. It refuses addresses internal to the cluster.
+var defaultClient = Dialer{}.Client()
+
+// ParseTemplate parses a template of the body of Webhook. The json function
+// quotes a value for JSON, e.g. {"text": {{ json .Message }}}.
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("body").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Option("missingkey=error").Parse(text)
+}
+
+// A Webhook posts notifications as JSON to URL.
+type Webhook struct {
+	URL     string
+	Headers map[string]string
+	// Body renders the body. Notification is sent as JSON if it is nil.
+	Body   *template.Template
+	Client *http.Client
+}
+
+// NewWebhook returns Webhook rendering bodies with bodyTemplate, if it is set.
+func NewWebhook(url string, headers map[string]string, bodyTemplate string) (*Webhook, error) {
+	webhook := &Webhook{URL: url, Headers: headers}
+	if bodyTemplate != "" {
+		body, err := ParseTemplate(bodyTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid body template: %w", err)
+		}
+		webhook.Body = body
+	}
+	return webhook, nil
+}
+
+func (w *Webhook) Send(ctx context.Context, n Notification) error {
+	var body bytes.Buffer
+	if w.Body == nil {
+		if err := json.NewEncoder(&body).Encode(n); err != nil {
+			return err
+		}
+	} else if err := w.Body.Execute(&body, n); err != nil {
+		return fmt.Errorf("failed to render body: %w", err)
+	}
+	return post(ctx, w.Client, w.URL, w.Headers, body.Bytes())
+}
+
+// A Slack posts notifications to a Slack incoming webhook or a compatible one.
+type Slack struct {
+	URL string
+	// Channel overrides the channel of the webhook.
+	Channel string
+	Client  *http.Client
+}
+
+func (s *Slack) Send(ctx context.Context, n Notification) error {
+	payload := struct {
+		Text    string `json:"text"`
+		Channel string `json:"channel,omitempty"`
+	}{
+		Text:    "*" + n.Subject() + "*\n" + text(n),
+		Channel: s.Channel,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.Client, s.URL, nil, body)
+}
+
+// post posts JSON body to url. Status codes other than 2xx are errors.
+func post(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// The body is not quoted: errors are recorded in the status and must not
+	// expose responses of arbitrary URLs to those who can read it.
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// An Email sends notifications by SMTP. STARTTLS is used if the server supports it.
+type Email struct {
+	// Address is host:port of the SMTP server.
+	Address string
+	From    string
+	To      []string
+	// Username and Password authenticate with PLAIN if Username is set.
+	Username string
+	Password string
+	// Dialer connects to the SMTP server if SendMail is not set.
+	Dialer Dialer
+	// SendMail sends the message, Dialer.sendMail by default.
+	SendMail func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func (e *Email) Send(ctx context.Context, n Notification) error {
+	var auth smtp.Auth
+	if e.Username != "" {
+		host, _, err := net.SplitHostPort(e.Address)
+		if err != nil {
+			return fmt.Errorf("invalid SMTP address %q: %w", e.Address, err)
+		}
+		auth = smtp.PlainAuth("", e.Username, e.Password, host)
+	}
+
+	send := e.SendMail
+	if send == nil {
+		send = e.Dialer.sendMail
+	}
+	return send(ctx, e.Address, auth, e.From, e.To, e.message(n))
+}
+
+// sendMail is smtp.SendMail connecting through d, which gives up when ctx is done or after requestTimeout.
+func (d Dialer) sendMail(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP address %q: %w", addr, err)
+	}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return err
+	}
+	// Closing the connection interrupts the conversation if ctx is canceled before the deadline.
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if a != nil {
+		if err := c.Auth(a); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// message renders n as a plain-text email.
+func (e *Email) message(n Notification) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.To, ", "))
+	fmt.Fprintf(&msg, "Subject: [oiler-backup] %s\r\n", n.Subject())
+	fmt.Fprintf(&msg, "Date: %s\r\n", n.Time.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(text(n), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	return msg.Bytes()
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	"github.com/oiler-backup/core/core/internal/config"
+)
+
+// log is for logging in this package.
+var backuprequestlog = logf.Log.WithName("backuprequest-resource")
+
+// SetupBackupRequestWebhookWithManager registers the webhook for BackupRequest in the manager.
+func SetupBackupRequestWebhookWithManager(mgr ctrl.Manager) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewWebhookManagedBy(mgr).For(&backupv1.BackupRequest{}).
+		WithDefaulter(&BackupRequestCustomDefaulter{defaults: cfg}).
+		WithValidator(&BackupRequestCustomValidator{
+			dbTypes: databaseTypes{reader: mgr.GetAPIReader(), namespace: cfg.OperatorNamespace},
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-backup-oiler-backup-v1-backuprequest,mutating=true,failurePolicy=fail,sideEffects=None,groups=backup.oiler.backup,resources=backuprequests,verbs=create,versions=v1,name=mbackuprequest-v1.kb.io,admissionReviewVersions=v1
+
+// BackupRequestCustomDefaulter struct is responsible for setting default values on the custom resource of the
+// Kind BackupRequest when those are created. Defaults are taken from operator configuration.
+type BackupRequestCustomDefaulter struct {
+	defaults config.Config
+}
+
+var _ webhook.CustomDefaulter = &BackupRequestCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind BackupRequest.
+func (d *BackupRequestCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	backuprequest, ok := obj.(*backupv1.BackupRequest)
+	if !ok {
+		return fmt.Errorf("expected an BackupRequest object but got %T", obj)
+	}
+	backuprequestlog.Info("Defaulting for BackupRequest", "name", backuprequest.GetName())
+
+	spec := &backuprequest.Spec
+	defaultPort(&spec.DbSpec.Port, spec.DbSpec.DbType)
+	if spec.Schedule == "" {
+		spec.Schedule = d.defaults.DefaultSchedule
+	}
+	// Zero would remove every backup including the one just made, so it is treated as unset.
+	if spec.MaxBackupCount == 0 {
+		spec.MaxBackupCount = d.defaults.DefaultMaxBackupCount
+	}
+	defaultS3(d.defaults, &spec.S3Spec.StorageLocationName, &spec.S3Spec.Endpoint, &spec.S3Spec.BucketName)
+
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-backup-oiler-backup-v1-backuprequest,mutating=false,failurePolicy=fail,sideEffects=None,groups=backup.oiler.backup,resources=backuprequests,verbs=create;update,versions=v1,name=vbackuprequest-v1.kb.io,admissionReviewVersions=v1
+
+// BackupRequestCustomValidator struct is responsible for validating the BackupRequest resource
+// when it is created, updated, or deleted.
+type BackupRequestCustomValidator struct {
+	dbTypes databaseTypes
+}
+
+var _ webhook.CustomValidator = &BackupRequestCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type BackupRequest.
+func (v *BackupRequestCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	backuprequest, ok := obj.(*backupv1.BackupRequest)
+	if !ok {
+		return nil, fmt.Errorf("expected a BackupRequest object but got %T", obj)
+	}
+	backuprequestlog.Info("Validation for BackupRequest upon creation", "name", backuprequest.GetName())
+
+	return nil, v.validate(ctx, backuprequest, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type BackupRequest.
+func (v *BackupRequestCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	backuprequest, ok := newObj.(*backupv1.BackupRequest)
+	if !ok {
+		return nil, fmt.Errorf("expected a BackupRequest object for the newObj but got %T", newObj)
+	}
+	old, ok := oldObj.(*backupv1.BackupRequest)
+	if !ok {
+		return nil, fmt.Errorf("expected a BackupRequest object for the oldObj but got %T", oldObj)
+	}
+	backuprequestlog.Info("Validation for BackupRequest upon update", "name", backuprequest.GetName())
+
+	return nil, v.validate(ctx, backuprequest, old)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type BackupRequest.
+func (v *BackupRequestCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks br. On update old is set: immutable fields are compared with it
+// instead of looking up for the adapter again, so removing a database type from
+// database-config does not block edits of existing resources.
+func (v *BackupRequestCustomValidator) validate(ctx context.Context, br, old *backupv1.BackupRequest) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	dbPath := specPath.Child("dbSpec")
+
+	if old == nil {
+		capabilities := []string{backupv1.AdapterCapabilityBackup}
+		if br.Spec.DeletionPolicy == backupv1.DeletionPolicyDelete {
+			capabilities = append(capabilities, backupv1.AdapterCapabilityPurge)
+		}
+		if err := v.dbTypes.validate(ctx, dbPath.Child("dbType"), br.Spec.DbSpec.DbType, capabilities...); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	} else if br.Spec.DbSpec.DbType != old.Spec.DbSpec.DbType {
+		// CronJob is owned by the adapter of the original type.
+		allErrs = append(allErrs, field.Invalid(dbPath.Child("dbType"), br.Spec.DbSpec.DbType, "field is immutable"))
+	}
+	if err := validatePort(dbPath.Child("port"), br.Spec.DbSpec.Port); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if ref := br.Spec.DbSpec.PasswordSecretRef; ref != nil {
+		path := dbPath.Child("passwordSecretRef", "namespace")
+		if err := validateSecretNamespace(path, ref.Namespace, br.Namespace); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	s3Path := specPath.Child("s3Spec")
+	for _, err := range []*field.Error{
+		validateStorageOverride(s3Path.Child("endpoint"), br.Spec.S3Spec.StorageLocationName, br.Spec.S3Spec.Endpoint),
+		validateStorageOverride(s3Path.Child("bucketName"), br.Spec.S3Spec.StorageLocationName, br.Spec.S3Spec.BucketName),
+	} {
+		if err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	if ref := br.Spec.S3Spec.CredentialsSecretRef; ref != nil {
+		path := specPath.Child("s3Spec", "credentialsSecretRef", "namespace")
+		if err := validateSecretNamespace(path, ref.Namespace, br.Namespace); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	if err := validateSchedule(specPath.Child("schedule"), br.Spec.Schedule); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if br.Spec.TimeZone != "" {
+		if err := validateTimeZone(specPath.Child("timeZone"), br.Spec.TimeZone); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	if br.Spec.MaxBackupCount < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("maxBackupCount"), br.Spec.MaxBackupCount,
+			"must be greater than or equal to 0"))
+	}
+	if r := br.Spec.Retention; r != nil && r.MaxAge != "" {
+		if err := validateMaxAge(specPath.Child("retention", "maxAge"), r.MaxAge); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	if h := br.Spec.Hooks; h != nil {
+		allErrs = append(allErrs, validateHooks(specPath.Child("hooks", "pre"), h.Pre, br.Spec.DbSpec.DbType)...)
+		allErrs = append(allErrs, validateHooks(specPath.Child("hooks", "post"), h.Post, br.Spec.DbSpec.DbType)...)
+	}
+	if n := br.Spec.Notifications; n != nil {
+		allErrs = append(allErrs, validateNotifications(specPath.Child("notifications"), n, br.Namespace)...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(backupv1.GroupVersion.WithKind("BackupRequest").GroupKind(), br.Name, allErrs)
+}
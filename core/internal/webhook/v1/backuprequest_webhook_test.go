@@ -0,0 +1,412 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	backupv2 "github.com/oiler-backup/core/core/api/v2"
+	"github.com/oiler-backup/core/core/internal/config"
+)
+
+var _ = Describe("BackupRequest Webhook", func() {
+	var (
+		ctx       context.Context
+		obj       *backupv1.BackupRequest
+		validator BackupRequestCustomValidator
+		defaulter BackupRequestCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &backupv1.BackupRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-br", Namespace: "team-a"},
+			Spec: backupv1.BackupRequestSpec{
+				DbSpec: backupv1.DatabaseSpec{
+					URI:    "localhost",
+					Port:   5432,
+					User:   "user",
+					Pass:   "pass",
+					DbName: "db",
+					DbType: "postgres",
+				},
+				Schedule:       "0 0 * * *",
+				MaxBackupCount: 7,
+			},
+		}
+		validator = BackupRequestCustomValidator{dbTypes: newDatabaseTypes()}
+		defaulter = BackupRequestCustomDefaulter{defaults: config.Config{
+			DefaultSchedule:       "0 3 * * *",
+			DefaultMaxBackupCount: 5,
+			DefaultS3Endpoint:     "http://minio:9000",
+			DefaultS3BucketName:   "backups",
+		}}
+	})
+
+	Context("When creating BackupRequest under Defaulting Webhook", func() {
+		It("Should fill empty fields from DbType and operator defaults", func() {
+			obj.Spec.DbSpec.Port = 0
+			obj.Spec.Schedule = ""
+			obj.Spec.MaxBackupCount = 0
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+
+			Expect(obj.Spec.DbSpec.Port).To(Equal(5432))
+			Expect(obj.Spec.Schedule).To(Equal("0 3 * * *"))
+			Expect(obj.Spec.MaxBackupCount).To(Equal(int64(5)))
+			Expect(obj.Spec.S3Spec.Endpoint).To(Equal("http://minio:9000"))
+			Expect(obj.Spec.S3Spec.BucketName).To(Equal("backups"))
+		})
+
+		It("Should keep fields set by user", func() {
+			obj.Spec.DbSpec.Port = 6432
+			obj.Spec.S3Spec.BucketName = "team-backups"
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+
+			Expect(obj.Spec.DbSpec.Port).To(Equal(6432))
+			Expect(obj.Spec.Schedule).To(Equal("0 0 * * *"))
+			Expect(obj.Spec.MaxBackupCount).To(Equal(int64(7)))
+			Expect(obj.Spec.S3Spec.BucketName).To(Equal("team-backups"))
+		})
+
+		It("Should prefer the default BackupStorageLocation", func() {
+			defaulter.defaults.DefaultStorageLocation = "minio"
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+
+			Expect(obj.Spec.S3Spec.StorageLocationName).To(Equal("minio"))
+			Expect(obj.Spec.S3Spec.Endpoint).To(BeEmpty())
+			Expect(obj.Spec.S3Spec.BucketName).To(BeEmpty())
+		})
+
+		It("Should leave the port of an unknown database type empty", func() {
+			obj.Spec.DbSpec.Port = 0
+			obj.Spec.DbSpec.DbType = "oracle"
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.DbSpec.Port).To(BeZero())
+		})
+	})
+
+	Context("When creating BackupRequest under Validating Webhook", func() {
+		It("Should admit a valid BackupRequest", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should admit a schedule descriptor", func() {
+			obj.Spec.Schedule = "@daily"
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny an invalid schedule", func() {
+			obj.Spec.Schedule = "0 0 * *"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.schedule"))
+		})
+
+		It("Should admit hashed schedules", func() {
+			for _, schedule := range []string{"H * * * *", "H H(1-5) * * *", "H/15 * * * *", "0 H(0-11)/6 * * H"} {
+				obj.Spec.Schedule = schedule
+				Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred(), schedule)
+			}
+		})
+
+		It("Should deny a hashed range out of field bounds", func() {
+			obj.Spec.Schedule = "H(0-99) * * * *"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.schedule"))
+		})
+
+		It("Should admit an IANA time zone", func() {
+			obj.Spec.TimeZone = "Europe/Moscow"
+			obj.Spec.BackoffLimit = ptr.To[int32](2)
+			obj.Spec.ConcurrencyPolicy = "Forbid"
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny an unknown time zone", func() {
+			for _, timeZone := range []string{"Mars/Olympus", "Local"} {
+				obj.Spec.TimeZone = timeZone
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue(), timeZone)
+				Expect(err.Error()).To(ContainSubstring("spec.timeZone"))
+			}
+		})
+
+		It("Should deny schedules CronJob does not accept", func() {
+			for _, schedule := range []string{"@every 5m", "@reboot", "0 0 0 * * *", "TZ=UTC 0 0 * * *", "CRON_TZ=Europe/Helsinki H * * * *", "0 0 H(1-31) * *"} {
+				obj.Spec.Schedule = schedule
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue(), schedule)
+				Expect(err.Error()).To(ContainSubstring("spec.schedule"))
+			}
+		})
+
+		It("Should deny endpoint and bucket overriding BackupStorageLocation", func() {
+			obj.Spec.S3Spec = backupv1.S3Spec{StorageLocationName: "minio", Endpoint: "http://evil:9000", BucketName: "stolen"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.s3Spec.endpoint: Forbidden"))
+			Expect(err.Error()).To(ContainSubstring("spec.s3Spec.bucketName: Forbidden"))
+
+			obj.Spec.S3Spec = backupv1.S3Spec{StorageLocationName: "minio", Auth: backupv1.S3Auth{AccessKey: "key", SecretKey: "secret"}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny a negative maxBackupCount", func() {
+			obj.Spec.MaxBackupCount = -1
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.maxBackupCount"))
+		})
+
+		It("Should admit maxAge in days, weeks or a duration", func() {
+			for _, age := range []string{"30d", "2w", "36h"} {
+				obj.Spec.Retention = &backupv1.RetentionPolicy{Daily: 7, MaxAge: age}
+				Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred(), age)
+			}
+		})
+
+		It("Should deny an invalid maxAge", func() {
+			for _, age := range []string{"month", "0d", "-1h"} {
+				obj.Spec.Retention = &backupv1.RetentionPolicy{MaxAge: age}
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue(), age)
+				Expect(err.Error()).To(ContainSubstring("spec.retention.maxAge"))
+			}
+		})
+
+		It("Should admit hooks running a query, a command or an HTTP request", func() {
+			obj.Spec.Hooks = &backupv1.BackupHooks{
+				Pre: []backupv1.BackupHook{
+					{Name: "checkpoint", Query: "CHECKPOINT"},
+					{Name: "pause", HTTP: &backupv1.HTTPHook{URL: "http://app/pause"}},
+				},
+				Post: []backupv1.BackupHook{{Name: "resume", Command: []string{"curl", "http://app/resume"}}},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny hooks setting none or several actions", func() {
+			obj.Spec.Hooks = &backupv1.BackupHooks{
+				Pre:  []backupv1.BackupHook{{Name: "empty"}},
+				Post: []backupv1.BackupHook{{Name: "both", Query: "UNLOCK TABLES", Command: []string{"true"}}},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.hooks.pre[0]"))
+			Expect(err.Error()).To(ContainSubstring("spec.hooks.post[0]"))
+		})
+
+		It("Should deny query hooks for MongoDB", func() {
+			obj.Spec.DbSpec.DbType = "mongodb"
+			obj.Spec.Hooks = &backupv1.BackupHooks{
+				Pre:  []backupv1.BackupHook{{Name: "fsync", Command: []string{"mongosh", "--eval", "db.fsyncLock()"}}},
+				Post: []backupv1.BackupHook{{Name: "unlock", Query: "db.fsyncUnlock()"}},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.hooks.post[0].query: Forbidden: query hooks are not supported for mongodb"))
+			Expect(err.Error()).NotTo(ContainSubstring("spec.hooks.pre[0]"))
+		})
+
+		It("Should admit notification sinks", func() {
+			obj.Spec.Notifications = &backupv1.Notifications{
+				StaleAfter: &metav1.Duration{Duration: 26 * time.Hour},
+				Sinks: []backupv1.NotificationSink{
+					{Name: "ops", Webhook: &backupv1.WebhookSink{URL: "https://ops.example.com/hooks", BodyTemplate: `{"text":{{ json .Message }}}`}},
+					{Name: "slack", Slack: &backupv1.SlackSink{URLSecretRef: backupv1.SecretKeyReference{Name: "slack", Key: "url"}}},
+					{Name: "dba", Email: &backupv1.EmailSink{SMTPAddress: "smtp.example.com:587", From: "oiler@example.com", To: []string{"dba@example.com"}}},
+				},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny invalid notification sinks", func() {
+			obj.Spec.Notifications = &backupv1.Notifications{
+				Sinks: []backupv1.NotificationSink{
+					{Name: "empty"},
+					{Name: "no-url", Webhook: &backupv1.WebhookSink{BodyTemplate: "{{ .Message"}},
+					{Name: "foreign", Slack: &backupv1.SlackSink{URLSecretRef: backupv1.SecretKeyReference{Name: "slack", Namespace: "other", Key: "url"}}},
+				},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.notifications.sinks[0]"))
+			Expect(err.Error()).To(ContainSubstring("spec.notifications.sinks[1].webhook.url"))
+			Expect(err.Error()).To(ContainSubstring("spec.notifications.sinks[1].webhook.bodyTemplate"))
+			Expect(err.Error()).To(ContainSubstring("spec.notifications.sinks[2].slack.urlSecretRef.namespace"))
+		})
+
+		It("Should deny a port out of range", func() {
+			obj.Spec.DbSpec.Port = 70000
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.dbSpec.port"))
+		})
+
+		It("Should deny a database type missing from database-config", func() {
+			obj.Spec.DbSpec.DbType = "oracle"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(`spec.dbSpec.dbType: Unsupported value: "oracle"`))
+		})
+
+		It("Should deny any database type without database-config", func() {
+			validator.dbTypes.reader = newReader()
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("database-config is not found"))
+		})
+
+		It("Should admit a database type served by DatabaseAdapter", func() {
+			obj.Spec.DbSpec.DbType = "mysql"
+			validator.dbTypes.reader = newReader(&backupv1.DatabaseAdapter{
+				ObjectMeta: metav1.ObjectMeta{Name: "mysql"},
+				Spec:       backupv1.DatabaseAdapterSpec{Address: "mysql-adapter:50051"},
+			})
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should list database types of DatabaseAdapters and database-config", func() {
+			obj.Spec.DbSpec.DbType = "oracle"
+			validator.dbTypes.reader = newReader(
+				&backupv1.DatabaseAdapter{ObjectMeta: metav1.ObjectMeta{Name: "mysql"}},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: databaseConfigName, Namespace: operatorNamespace},
+					Data:       map[string]string{"postgres": "adapter.addr"},
+				},
+			)
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(`supported values: "mysql", "postgres"`))
+		})
+
+		It("Should deny the Delete policy if DatabaseAdapter cannot purge", func() {
+			obj.Spec.DeletionPolicy = backupv1.DeletionPolicyDelete
+			validator.dbTypes.reader = newReader(&backupv1.DatabaseAdapter{
+				ObjectMeta: metav1.ObjectMeta{Name: "postgres"},
+				Spec: backupv1.DatabaseAdapterSpec{
+					Address:      "postgres-adapter:50051",
+					Capabilities: []string{backupv1.AdapterCapabilityBackup, backupv1.AdapterCapabilityRestore},
+				},
+			})
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("DatabaseAdapter postgres does not support Purge"))
+		})
+
+		It("Should admit a Secret in the namespace of the BackupRequest", func() {
+			obj.Spec.DbSpec.PasswordSecretRef = &backupv1.SecretKeyReference{Name: "db", Namespace: "team-a", Key: "password"}
+			obj.Spec.S3Spec.CredentialsSecretRef = &backupv1.S3CredentialsSecretRef{Name: "s3"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny a Secret in another namespace", func() {
+			obj.Spec.DbSpec.PasswordSecretRef = &backupv1.SecretKeyReference{Name: "db", Namespace: "team-b", Key: "password"}
+			obj.Spec.S3Spec.CredentialsSecretRef = &backupv1.S3CredentialsSecretRef{Name: "s3", Namespace: "team-b"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.dbSpec.passwordSecretRef.namespace: Forbidden"))
+			Expect(err.Error()).To(ContainSubstring("spec.s3Spec.credentialsSecretRef.namespace: Forbidden"))
+		})
+
+		It("Should report all invalid fields at once", func() {
+			obj.Spec.Schedule = ""
+			obj.Spec.DbSpec.Port = 0
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.(*apierrors.StatusError).Status().Details.Causes).To(HaveLen(2))
+		})
+	})
+
+	Context("When updating BackupRequest under Validating Webhook", func() {
+		It("Should admit a new schedule", func() {
+			newObj := obj.DeepCopy()
+			newObj.Spec.Schedule = "0 12 * * *"
+			Expect(validator.ValidateUpdate(ctx, obj, newObj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny a change of the database type", func() {
+			newObj := obj.DeepCopy()
+			newObj.Spec.DbSpec.DbType = "mysql"
+			_, err := validator.ValidateUpdate(ctx, obj, newObj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("field is immutable"))
+		})
+
+		It("Should admit an update after the database type is removed from database-config", func() {
+			validator.dbTypes.reader = newReader()
+			newObj := obj.DeepCopy()
+			newObj.Spec.MaxBackupCount = 3
+			Expect(validator.ValidateUpdate(ctx, obj, newObj)).Error().NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When converting BackupRequest under Conversion Webhook", func() {
+		It("Should convert to v2 and back without losing fields", func() {
+			obj.Spec.DbSpec.PasswordSecretRef = &backupv1.SecretKeyReference{Name: "db", Key: "password"}
+			obj.Spec.S3Spec = backupv1.S3Spec{
+				StorageLocationName: "minio",
+				BucketName:          "backups",
+				CredentialsSecretRef: &backupv1.S3CredentialsSecretRef{
+					Name: "s3",
+				},
+			}
+			obj.Spec.TimeZone = "Europe/Moscow"
+			obj.Spec.Retention = &backupv1.RetentionPolicy{Hourly: 24, Daily: 7, Weekly: 4, Monthly: 12, MaxAge: "365d"}
+			obj.Spec.Hooks = &backupv1.BackupHooks{
+				Pre:  []backupv1.BackupHook{{Name: "lock", Query: "FLUSH TABLES WITH READ LOCK", OnError: "Fail"}},
+				Post: []backupv1.BackupHook{{Name: "resume", HTTP: &backupv1.HTTPHook{URL: "http://app/resume", Method: "POST"}}},
+			}
+			obj.Spec.Notifications = &backupv1.Notifications{
+				Events:     []string{backupv1.NotificationBackupFailed},
+				StaleAfter: &metav1.Duration{Duration: time.Hour},
+				Sinks: []backupv1.NotificationSink{
+					{Name: "ops", Webhook: &backupv1.WebhookSink{URLSecretRef: &backupv1.SecretKeyReference{Name: "hooks", Key: "url"}}},
+					{Name: "slack", Slack: &backupv1.SlackSink{URLSecretRef: backupv1.SecretKeyReference{Name: "slack", Key: "url"}, Channel: "#db"}},
+					{Name: "dba", Email: &backupv1.EmailSink{SMTPAddress: "smtp:25", From: "a@b", To: []string{"c@d"},
+						PasswordSecretRef: &backupv1.SecretKeyReference{Name: "smtp", Key: "password"}}},
+				},
+			}
+			obj.Status.Notifications = []backupv1.NotificationRecord{{Event: backupv1.NotificationBackupFailed, Key: "backup-1"}}
+			obj.Status.Status = "Success"
+			obj.Status.History = []backupv1.BackupRun{{
+				JobName: "backup-1",
+				Hooks:   []backupv1.HookResult{{Name: "lock", Phase: "pre", Succeeded: true, Duration: "5ms"}},
+			}}
+			obj.Status.CronJobData = backupv1.CreatedCronJobData{Name: "cj", Namespace: "oiler"}
+
+			converted := &backupv2.BackupRequest{}
+			Expect(converted.ConvertFrom(obj)).To(Succeed())
+			Expect(converted.Spec.DbSpec.PasswordSecretRef.Name).To(Equal("db"))
+			Expect(converted.Spec.S3Spec.StorageLocationName).To(Equal("minio"))
+
+			hub := &backupv1.BackupRequest{}
+			Expect(converted.ConvertTo(hub)).To(Succeed())
+			Expect(hub).To(Equal(obj))
+		})
+	})
+})
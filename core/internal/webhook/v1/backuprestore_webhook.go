@@ -0,0 +1,201 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	"github.com/oiler-backup/core/core/internal/config"
+)
+
+// log is for logging in this package.
+var backuprestorelog = logf.Log.WithName("backuprestore-resource")
+
+// SetupBackupRestoreWebhookWithManager registers the webhook for BackupRestore in the manager.
+func SetupBackupRestoreWebhookWithManager(mgr ctrl.Manager) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewWebhookManagedBy(mgr).For(&backupv1.BackupRestore{}).
+		WithDefaulter(&BackupRestoreCustomDefaulter{defaults: cfg}).
+		WithValidator(&BackupRestoreCustomValidator{
+			dbTypes: databaseTypes{reader: mgr.GetAPIReader(), namespace: cfg.OperatorNamespace},
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-backup-oiler-backup-v1-backuprestore,mutating=true,failurePolicy=fail,sideEffects=None,groups=backup.oiler.backup,resources=backuprestores,verbs=create,versions=v1,name=mbackuprestore-v1.kb.io,admissionReviewVersions=v1
+
+// BackupRestoreCustomDefaulter struct is responsible for setting default values on the custom resource of the
+// Kind BackupRestore when those are created. Defaults are taken from operator configuration.
+type BackupRestoreCustomDefaulter struct {
+	defaults config.Config
+}
+
+var _ webhook.CustomDefaulter = &BackupRestoreCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind BackupRestore.
+func (d *BackupRestoreCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	backuprestore, ok := obj.(*backupv1.BackupRestore)
+	if !ok {
+		return fmt.Errorf("expected an BackupRestore object but got %T", obj)
+	}
+	backuprestorelog.Info("Defaulting for BackupRestore", "name", backuprestore.GetName())
+
+	spec := &backuprestore.Spec
+	defaultPort(&spec.DatabasePort, spec.DatabaseType)
+	// Backups of a BackupRequest are read from its storage unless the storage is set explicitly.
+	if !restoresBackupRequest(backuprestore) {
+		defaultS3(d.defaults, &spec.StorageLocationName, &spec.S3Endpoint, &spec.S3BucketName)
+	}
+
+	return nil
+}
+
+// sourceDatabaseName returns the database backups restored by backuprestore were made of,
+// empty if they are made by a BackupRequest.
+func sourceDatabaseName(backuprestore *backupv1.BackupRestore) string {
+	switch source := backuprestore.Spec.Source; {
+	case source == nil:
+		return backuprestore.Spec.DatabaseName
+	case source.BackupRequestName != "":
+		return ""
+	case source.DatabaseName != "":
+		return source.DatabaseName
+	default:
+		return backuprestore.Spec.DatabaseName
+	}
+}
+
+// restoresBackupRequest tells whether backuprestore takes backups of a BackupRequest,
+// by source or by revision.
+func restoresBackupRequest(backuprestore *backupv1.BackupRestore) bool {
+	source := backuprestore.Spec.Source
+	return source != nil && source.BackupRequestName != "" ||
+		strings.HasPrefix(backuprestore.Spec.BackupRevision, backupv1.RevisionBackupRequestPrefix)
+}
+
+// +kubebuilder:webhook:path=/validate-backup-oiler-backup-v1-backuprestore,mutating=false,failurePolicy=fail,sideEffects=None,groups=backup.oiler.backup,resources=backuprestores,verbs=create;update,versions=v1,name=vbackuprestore-v1.kb.io,admissionReviewVersions=v1
+
+// BackupRestoreCustomValidator struct is responsible for validating the BackupRestore resource
+// when it is created, updated, or deleted.
+type BackupRestoreCustomValidator struct {
+	dbTypes databaseTypes
+}
+
+var _ webhook.CustomValidator = &BackupRestoreCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type BackupRestore.
+func (v *BackupRestoreCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	backuprestore, ok := obj.(*backupv1.BackupRestore)
+	if !ok {
+		return nil, fmt.Errorf("expected a BackupRestore object but got %T", obj)
+	}
+	backuprestorelog.Info("Validation for BackupRestore upon creation", "name", backuprestore.GetName())
+
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	if err := v.dbTypes.validate(ctx, specPath.Child("databaseType"), backuprestore.Spec.DatabaseType,
+		backupv1.AdapterCapabilityRestore); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := validatePort(specPath.Child("databasePort"), backuprestore.Spec.DatabasePort); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := validateRevision(specPath.Child("backupRevision"), backuprestore.Spec.BackupRevision); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := validateRevisionKey(specPath.Child("backupRevision"), backuprestore.Spec.BackupRevision,
+		backuprestore.Namespace, sourceDatabaseName(backuprestore)); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if backuprestore.Spec.Source != nil &&
+		strings.HasPrefix(backuprestore.Spec.BackupRevision, backupv1.RevisionBackupRequestPrefix) {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("backupRevision"),
+			"BackupRequest must be set either by revision or by source"))
+	}
+	if ref := backuprestore.Spec.DatabasePasswordSecretRef; ref != nil {
+		path := specPath.Child("databasePasswordSecretRef", "namespace")
+		if err := validateSecretNamespace(path, ref.Namespace, backuprestore.Namespace); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	for _, err := range []*field.Error{
+		validateStorageOverride(specPath.Child("s3Endpoint"), backuprestore.Spec.StorageLocationName, backuprestore.Spec.S3Endpoint),
+		validateStorageOverride(specPath.Child("s3BucketName"), backuprestore.Spec.StorageLocationName, backuprestore.Spec.S3BucketName),
+	} {
+		if err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	if ref := backuprestore.Spec.S3CredentialsSecretRef; ref != nil {
+		path := specPath.Child("s3CredentialsSecretRef", "namespace")
+		if err := validateSecretNamespace(path, ref.Namespace, backuprestore.Namespace); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
+	return nil, backupRestoreInvalid(backuprestore, allErrs)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type BackupRestore.
+// Restore Job is created once, so spec can't be changed afterwards.
+func (v *BackupRestoreCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	backuprestore, ok := newObj.(*backupv1.BackupRestore)
+	if !ok {
+		return nil, fmt.Errorf("expected a BackupRestore object for the newObj but got %T", newObj)
+	}
+	old, ok := oldObj.(*backupv1.BackupRestore)
+	if !ok {
+		return nil, fmt.Errorf("expected a BackupRestore object for the oldObj but got %T", oldObj)
+	}
+	backuprestorelog.Info("Validation for BackupRestore upon update", "name", backuprestore.GetName())
+
+	var allErrs field.ErrorList
+	if !equality.Semantic.DeepEqual(backuprestore.Spec, old.Spec) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"),
+			"spec is immutable, create a new BackupRestore to restore again"))
+	}
+
+	return nil, backupRestoreInvalid(backuprestore, allErrs)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type BackupRestore.
+func (v *BackupRestoreCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func backupRestoreInvalid(brs *backupv1.BackupRestore, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(backupv1.GroupVersion.WithKind("BackupRestore").GroupKind(), brs.Name, allErrs)
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	backupv2 "github.com/oiler-backup/core/core/api/v2"
+	"github.com/oiler-backup/core/core/internal/config"
+)
+
+var _ = Describe("BackupRestore Webhook", func() {
+	var (
+		ctx       context.Context
+		obj       *backupv1.BackupRestore
+		validator BackupRestoreCustomValidator
+		defaulter BackupRestoreCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &backupv1.BackupRestore{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-brs", Namespace: "team-a"},
+			Spec: backupv1.BackupRestoreSpec{
+				DatabaseURI:    "localhost",
+				DatabasePort:   5432,
+				DatabaseUser:   "user",
+				DatabasePass:   "pass",
+				DatabaseName:   "db",
+				DatabaseType:   "postgres",
+				BackupRevision: "0",
+			},
+		}
+		validator = BackupRestoreCustomValidator{dbTypes: newDatabaseTypes()}
+		defaulter = BackupRestoreCustomDefaulter{defaults: config.Config{
+			DefaultStorageLocation: "minio",
+		}}
+	})
+
+	Context("When creating BackupRestore under Defaulting Webhook", func() {
+		It("Should fill the port and the storage location", func() {
+			obj.Spec.DatabasePort = 0
+			obj.Spec.DatabaseType = "mysql"
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+
+			Expect(obj.Spec.DatabasePort).To(Equal(3306))
+			Expect(obj.Spec.StorageLocationName).To(Equal("minio"))
+		})
+
+		It("Should not override inline S3 settings", func() {
+			obj.Spec.S3Endpoint = "http://s3:9000"
+			obj.Spec.S3BucketName = "restore"
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+
+			Expect(obj.Spec.StorageLocationName).To(BeEmpty())
+			Expect(obj.Spec.S3Endpoint).To(Equal("http://s3:9000"))
+		})
+
+		It("Should leave storage of BackupRequest backups to the BackupRequest", func() {
+			obj.Spec.Source = &backupv1.RestoreSource{BackupRequestName: "orders"}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.StorageLocationName).To(BeEmpty())
+
+			obj.Spec.Source = nil
+			obj.Spec.BackupRevision = "backupRequest:orders"
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.StorageLocationName).To(BeEmpty())
+			Expect(obj.Spec.S3Endpoint).To(BeEmpty())
+			Expect(obj.Spec.S3BucketName).To(BeEmpty())
+		})
+	})
+
+	Context("When creating BackupRestore under Validating Webhook", func() {
+		It("Should admit a valid BackupRestore", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny a port out of range", func() {
+			obj.Spec.DatabasePort = 0
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.databasePort"))
+		})
+
+		It("Should deny a database type missing from database-config", func() {
+			obj.Spec.DatabaseType = "oracle"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.databaseType"))
+		})
+
+		It("Should deny a database type whose DatabaseAdapter cannot restore", func() {
+			validator.dbTypes.reader = newReader(&backupv1.DatabaseAdapter{
+				ObjectMeta: metav1.ObjectMeta{Name: "postgres"},
+				Spec: backupv1.DatabaseAdapterSpec{
+					Address:      "postgres-adapter:50051",
+					Capabilities: []string{backupv1.AdapterCapabilityBackup},
+				},
+			})
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("DatabaseAdapter postgres does not support Restore"))
+		})
+
+		It("Should deny endpoint and bucket overriding BackupStorageLocation", func() {
+			obj.Spec.StorageLocationName = "minio"
+			obj.Spec.S3Endpoint = "http://evil:9000"
+			obj.Spec.S3BucketName = "stolen"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.s3Endpoint: Forbidden"))
+			Expect(err.Error()).To(ContainSubstring("spec.s3BucketName: Forbidden"))
+		})
+
+		It("Should deny a Secret in another namespace", func() {
+			obj.Spec.DatabasePasswordSecretRef = &backupv1.SecretKeyReference{Name: "db", Namespace: "team-b", Key: "password"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.databasePasswordSecretRef.namespace: Forbidden"))
+		})
+	})
+
+	Context("When validating backupRevision", func() {
+		DescribeTable("Should admit selectors",
+			func(revision string) {
+				obj.Spec.BackupRevision = revision
+				Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+			},
+			Entry("latest", "latest"),
+			Entry("N-th newest", "newest:1"),
+			Entry("time", "before:2025-05-01T15:00:00+03:00"),
+			Entry("BackupRequest", "backupRequest:orders"),
+		)
+
+		DescribeTable("Should deny malformed selectors",
+			func(revision, message string) {
+				obj.Spec.BackupRevision = revision
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("negative number", "newest:-1", "must be a non-negative number after newest:"),
+			Entry("date without time", "before:2025-05-01", "must be an RFC3339 time after before:"),
+			Entry("invalid name", "backupRequest:Orders", "must be a name of BackupRequest after backupRequest:"),
+			Entry("empty", "", "spec.backupRevision: Required value"),
+		)
+
+		DescribeTable("Should admit object keys of backups of the namespace",
+			func(revision string, source *backupv1.RestoreSource) {
+				obj.Spec.BackupRevision = revision
+				obj.Spec.Source = source
+				Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+			},
+			Entry("of a BackupRequest", "team-a/daily/db/2025-05-01-00-00-00-backup.sql", nil),
+			Entry("made before BackupRequest prefixes", "db/2025-05-01-00-00-00-backup.sql", nil),
+			Entry("of a source database", "orders/2025-05-01-00-00-00-backup.sql",
+				&backupv1.RestoreSource{DatabaseName: "orders"}),
+			Entry("of a source BackupRequest", "team-a/daily/orders/2025-05-01-00-00-00-backup.sql",
+				&backupv1.RestoreSource{BackupRequestName: "daily"}),
+		)
+
+		DescribeTable("Should deny object keys of backups of another namespace",
+			func(revision string, source *backupv1.RestoreSource) {
+				obj.Spec.BackupRevision = revision
+				obj.Spec.Source = source
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				Expect(err.Error()).To(ContainSubstring("spec.backupRevision: Forbidden: object key must be under team-a/"))
+			},
+			Entry("of a BackupRequest", "team-b/daily/db/2025-05-01-00-00-00-backup.sql", nil),
+			Entry("nested under a database named like the namespace", "team-b/daily/team-b/2025-05-01-00-00-00-backup.sql",
+				&backupv1.RestoreSource{DatabaseName: "team-b"}),
+			Entry("of a source BackupRequest", "team-b/daily/orders/2025-05-01-00-00-00-backup.sql",
+				&backupv1.RestoreSource{BackupRequestName: "daily"}),
+			Entry("a negative number", "-1", nil),
+		)
+
+		It("Should deny BackupRequest set both by revision and by source", func() {
+			obj.Spec.BackupRevision = "backupRequest:orders"
+			obj.Spec.Source = &backupv1.RestoreSource{DatabaseName: "orders"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.backupRevision: Forbidden"))
+		})
+	})
+
+	Context("When updating BackupRestore under Validating Webhook", func() {
+		It("Should admit a metadata change", func() {
+			newObj := obj.DeepCopy()
+			newObj.Labels = map[string]string{"team": "db"}
+			Expect(validator.ValidateUpdate(ctx, obj, newObj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny a spec change", func() {
+			newObj := obj.DeepCopy()
+			newObj.Spec.BackupRevision = "1"
+			_, err := validator.ValidateUpdate(ctx, obj, newObj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec is immutable"))
+		})
+	})
+
+	Context("When converting BackupRestore under Conversion Webhook", func() {
+		It("Should nest flat v1 fields", func() {
+			obj.Spec.StorageLocationName = "minio"
+			obj.Spec.S3AccessKey = "key"
+			obj.Spec.TTLSecondsAfterFinished = ptr.To[int32](86400)
+
+			converted := &backupv2.BackupRestore{}
+			Expect(converted.ConvertFrom(obj)).To(Succeed())
+			Expect(converted.Spec.DbSpec.URI).To(Equal("localhost"))
+			Expect(converted.Spec.DbSpec.DbType).To(Equal("postgres"))
+			Expect(converted.Spec.S3Spec.StorageLocationName).To(Equal("minio"))
+			Expect(converted.Spec.S3Spec.Auth.AccessKey).To(Equal("key"))
+			Expect(converted.Spec.Revision.Index).To(HaveValue(BeEquivalentTo(0)))
+			Expect(converted.Spec.TTLSecondsAfterFinished).To(HaveValue(BeEquivalentTo(86400)))
+
+			hub := &backupv1.BackupRestore{}
+			Expect(converted.ConvertTo(hub)).To(Succeed())
+			Expect(hub).To(Equal(obj))
+		})
+
+		It("Should keep source through v2", func() {
+			obj.Spec.Source = &backupv1.RestoreSource{BackupRequestName: "orders"}
+
+			converted := &backupv2.BackupRestore{}
+			Expect(converted.ConvertFrom(obj)).To(Succeed())
+			Expect(converted.Spec.Source).To(Equal(&backupv2.RestoreSource{BackupRequest: "orders"}))
+
+			hub := &backupv1.BackupRestore{}
+			Expect(converted.ConvertTo(hub)).To(Succeed())
+			Expect(hub).To(Equal(obj))
+		})
+
+		DescribeTable("Should keep backupRevision through v2",
+			func(revision string, selector backupv2.RevisionSelector) {
+				obj.Spec.BackupRevision = revision
+
+				converted := &backupv2.BackupRestore{}
+				Expect(converted.ConvertFrom(obj)).To(Succeed())
+				Expect(converted.Spec.Revision).To(Equal(selector))
+
+				hub := &backupv1.BackupRestore{}
+				Expect(converted.ConvertTo(hub)).To(Succeed())
+				Expect(hub.Spec.BackupRevision).To(Equal(revision))
+			},
+			Entry("index", "3", backupv2.RevisionSelector{Index: ptr.To[int64](3)}),
+			Entry("object key", "postgres/2025-05-01.sql", backupv2.RevisionSelector{Key: "postgres/2025-05-01.sql"}),
+			Entry("negative number", "-1", backupv2.RevisionSelector{Key: "-1"}),
+			Entry("number with leading zero", "07", backupv2.RevisionSelector{Key: "07"}),
+			Entry("latest", "latest", backupv2.RevisionSelector{Latest: true}),
+			Entry("N-th newest", "newest:2", backupv2.RevisionSelector{Newest: ptr.To[int64](2)}),
+			Entry("time", "before:2025-05-01T12:00:00Z",
+				backupv2.RevisionSelector{Before: &metav1.Time{Time: time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)}}),
+			Entry("time in other zone", "before:2025-05-01T15:00:00+03:00",
+				backupv2.RevisionSelector{Key: "before:2025-05-01T15:00:00+03:00"}),
+			Entry("BackupRequest", "backupRequest:orders", backupv2.RevisionSelector{BackupRequest: "orders"}),
+		)
+	})
+})
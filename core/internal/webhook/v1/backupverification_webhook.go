@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+// log is for logging in this package.
+var backupverificationlog = logf.Log.WithName("backupverification-resource")
+
+// SetupBackupVerificationWebhookWithManager registers the webhook for BackupVerification in the manager.
+func SetupBackupVerificationWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&backupv1.BackupVerification{}).
+		WithValidator(&BackupVerificationCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-backup-oiler-backup-v1-backupverification,mutating=false,failurePolicy=fail,sideEffects=None,groups=backup.oiler.backup,resources=backupverifications,verbs=create;update,versions=v1,name=vbackupverification-v1.kb.io,admissionReviewVersions=v1
+
+// BackupVerificationCustomValidator struct is responsible for validating the BackupVerification resource
+// when it is created, updated, or deleted.
+type BackupVerificationCustomValidator struct{}
+
+var _ webhook.CustomValidator = &BackupVerificationCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type BackupVerification.
+func (v *BackupVerificationCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	verification, ok := obj.(*backupv1.BackupVerification)
+	if !ok {
+		return nil, fmt.Errorf("expected a BackupVerification object but got %T", obj)
+	}
+	backupverificationlog.Info("Validation for BackupVerification upon creation", "name", verification.GetName())
+
+	return nil, v.validate(verification)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type BackupVerification.
+func (v *BackupVerificationCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	verification, ok := newObj.(*backupv1.BackupVerification)
+	if !ok {
+		return nil, fmt.Errorf("expected a BackupVerification object for the newObj but got %T", newObj)
+	}
+	backupverificationlog.Info("Validation for BackupVerification upon update", "name", verification.GetName())
+
+	return nil, v.validate(verification)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type BackupVerification.
+func (v *BackupVerificationCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks verification. Its schedule follows the grammar of BackupRequest schedules,
+// and its revision selects among backups of the BackupRequest, which is set by backupRequestName.
+func (v *BackupVerificationCustomValidator) validate(verification *backupv1.BackupVerification) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if msgs := validation.IsDNS1123Subdomain(verification.Spec.BackupRequestName); len(msgs) > 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("backupRequestName"),
+			verification.Spec.BackupRequestName, strings.Join(msgs, ", ")))
+	}
+	if err := validateSchedule(specPath.Child("schedule"), verification.Spec.Schedule); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	revisionPath := specPath.Child("backupRevision")
+	revision := verification.Spec.BackupRevision
+	switch {
+	case revision == "":
+		// Defaulted to latest by the CRD.
+	case strings.HasPrefix(revision, backupv1.RevisionBackupRequestPrefix):
+		allErrs = append(allErrs, field.Forbidden(revisionPath, "BackupRequest is set by backupRequestName"))
+	default:
+		for _, err := range []*field.Error{
+			validateRevision(revisionPath, revision),
+			validateRevisionKey(revisionPath, revision, verification.Namespace, ""),
+		} {
+			if err != nil {
+				allErrs = append(allErrs, err)
+			}
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(backupv1.GroupVersion.WithKind("BackupVerification").GroupKind(), verification.Name, allErrs)
+}
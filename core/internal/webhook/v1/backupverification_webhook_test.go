@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+)
+
+var _ = Describe("BackupVerification Webhook", func() {
+	var (
+		ctx       context.Context
+		obj       *backupv1.BackupVerification
+		validator BackupVerificationCustomValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &backupv1.BackupVerification{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "team-a"},
+			Spec: backupv1.BackupVerificationSpec{
+				BackupRequestName: "orders",
+				Schedule:          "0 6 * * 1",
+				BackupRevision:    "latest",
+			},
+		}
+		validator = BackupVerificationCustomValidator{}
+	})
+
+	Context("When creating or updating BackupVerification under Validating Webhook", func() {
+		DescribeTable("Should admit schedules of BackupRequests",
+			func(schedule string) {
+				obj.Spec.Schedule = schedule
+				Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+				Expect(validator.ValidateUpdate(ctx, obj, obj)).Error().NotTo(HaveOccurred())
+			},
+			Entry("five fields", "0 6 * * 1"),
+			Entry("hashed", "H H(0-5) * * H"),
+			Entry("descriptor", "@weekly"),
+		)
+
+		DescribeTable("Should deny invalid schedules",
+			func(schedule string) {
+				obj.Spec.Schedule = schedule
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue(), schedule)
+				Expect(err.Error()).To(ContainSubstring("spec.schedule"))
+
+				_, err = validator.ValidateUpdate(ctx, obj, obj)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue(), schedule)
+			},
+			Entry("free text", "every day"),
+			Entry("hash out of range", "H(0-99) * * * *"),
+			Entry("interval", "@every 1h"),
+			Entry("time zone", "CRON_TZ=UTC 0 6 * * *"),
+		)
+
+		DescribeTable("Should admit revisions of the BackupRequest",
+			func(revision string) {
+				obj.Spec.BackupRevision = revision
+				Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+			},
+			Entry("latest", "latest"),
+			Entry("N-th newest", "newest:1"),
+			Entry("time", "before:2025-05-01T15:00:00Z"),
+			Entry("index", "0"),
+			Entry("object key", "team-a/orders/orders/2025-05-01-00-00-00-backup.sql"),
+		)
+
+		DescribeTable("Should deny invalid revisions",
+			func(revision, message string) {
+				obj.Spec.BackupRevision = revision
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("malformed selector", "newest:-1", "must be a non-negative number after newest:"),
+			Entry("another BackupRequest", "backupRequest:users", "spec.backupRevision: Forbidden: BackupRequest is set by backupRequestName"),
+			Entry("key of another namespace", "team-b/orders/orders/2025-05-01-00-00-00-backup.sql",
+				"spec.backupRevision: Forbidden: object key must be under team-a/"),
+		)
+
+		It("Should deny an invalid BackupRequest name", func() {
+			obj.Spec.BackupRequestName = "Orders"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.backupRequestName"))
+		})
+	})
+})
@@ -0,0 +1,34 @@
+package v1
+
+import "github.com/oiler-backup/core/core/internal/config"
+
+// defaultPorts are well-known ports of the database types served by adapters.
+var defaultPorts = map[string]int{
+	"postgres": 5432,
+	"mysql":    3306,
+	"mongodb":  27017,
+}
+
+func defaultPort(port *int, dbType string) {
+	if *port == 0 {
+		*port = defaultPorts[dbType]
+	}
+}
+
+// defaultS3 fills empty S3 settings from operator-level defaults.
+// Endpoint and bucket are left empty if a BackupStorageLocation is used,
+// since they must not be set together with it, see validateStorageOverride.
+func defaultS3(cfg config.Config, locationName, endpoint, bucketName *string) {
+	if *locationName == "" && *endpoint == "" && *bucketName == "" {
+		*locationName = cfg.DefaultStorageLocation
+	}
+	if *locationName != "" {
+		return
+	}
+	if *endpoint == "" {
+		*endpoint = cfg.DefaultS3Endpoint
+	}
+	if *bucketName == "" {
+		*bucketName = cfg.DefaultS3BucketName
+	}
+}
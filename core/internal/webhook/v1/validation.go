@@ -0,0 +1,282 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	// Time zones are checked against the embedded database, the image may have none.
+	_ "time/tzdata"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oiler-backup/core/common/layout"
+	"github.com/oiler-backup/core/common/retention"
+	"github.com/oiler-backup/core/common/schedule"
+	backupv1 "github.com/oiler-backup/core/core/api/v1"
+	"github.com/oiler-backup/core/core/internal/notify"
+)
+
+const databaseConfigName = "database-config"
+
+// databaseTypes looks up for the supported database types.
+// It must be kept in line with the adapters resolved by the controllers:
+// DatabaseAdapter named after the type or, if there is none, the ConfigMap.
+type databaseTypes struct {
+	reader    client.Reader
+	namespace string
+}
+
+// validate checks that dbType is served by DatabaseAdapter or one of the adapters of database-config,
+// and that the adapter supports capabilities. Adapters of database-config are assumed to support all.
+func (d databaseTypes) validate(ctx context.Context, path *field.Path, dbType string, capabilities ...string) *field.Error {
+	if dbType == "" {
+		return field.Required(path, "database type must be set")
+	}
+
+	var adapter backupv1.DatabaseAdapter
+	err := d.reader.Get(ctx, client.ObjectKey{Name: dbType}, &adapter)
+	if err == nil {
+		for _, capability := range capabilities {
+			if len(adapter.Spec.Capabilities) > 0 && !slices.Contains(adapter.Spec.Capabilities, capability) {
+				return field.Invalid(path, dbType, fmt.Sprintf("DatabaseAdapter %s does not support %s", dbType, capability))
+			}
+		}
+		return nil
+	}
+	if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return field.InternalError(path, fmt.Errorf("unable to get DatabaseAdapter %s: %w", dbType, err))
+	}
+
+	configMap := &corev1.ConfigMap{}
+	configErr := d.reader.Get(ctx, client.ObjectKey{Namespace: d.namespace, Name: databaseConfigName}, configMap)
+	if configErr != nil && !apierrors.IsNotFound(configErr) {
+		return field.InternalError(path, fmt.Errorf("unable to get ConfigMap %s: %w", databaseConfigName, configErr))
+	}
+	if _, ok := configMap.Data[dbType]; ok {
+		return nil
+	}
+
+	var adapters backupv1.DatabaseAdapterList
+	if err := d.reader.List(ctx, &adapters); err != nil && !meta.IsNoMatchError(err) {
+		return field.InternalError(path, fmt.Errorf("unable to list DatabaseAdapters: %w", err))
+	}
+	if len(adapters.Items) == 0 && configErr != nil {
+		return field.Invalid(path, dbType,
+			fmt.Sprintf("no DatabaseAdapter exists and ConfigMap %s/%s is not found, no database type is supported", d.namespace, databaseConfigName))
+	}
+
+	supported := make([]string, 0, len(adapters.Items)+len(configMap.Data))
+	for _, adapter := range adapters.Items {
+		supported = append(supported, adapter.Name)
+	}
+	for name := range configMap.Data {
+		supported = append(supported, name)
+	}
+	slices.Sort(supported)
+	return field.NotSupported(path, dbType, slices.Compact(supported))
+}
+
+func validatePort(path *field.Path, port int) *field.Error {
+	if port < 1 || port > 65535 {
+		return field.Invalid(path, port, "must be between 1 and 65535")
+	}
+	return nil
+}
+
+// validateSchedule checks a cron schedule with the grammar adapters expand it by:
+// the grammar of CronJob with hashed values. Time zones belong to timeZone.
+func validateSchedule(path *field.Path, cronSchedule string) *field.Error {
+	if cronSchedule == "" {
+		return field.Required(path, "cron schedule must be set")
+	}
+	if err := schedule.Validate(cronSchedule); err != nil {
+		return field.Invalid(path, cronSchedule, err.Error())
+	}
+	return nil
+}
+
+// validateTimeZone checks that timeZone is a name of the IANA database as CronJob expects.
+func validateTimeZone(path *field.Path, timeZone string) *field.Error {
+	if timeZone == "Local" {
+		return field.Invalid(path, timeZone, "must be a name of the IANA time zone database")
+	}
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return field.Invalid(path, timeZone, "unknown time zone: "+err.Error())
+	}
+	return nil
+}
+
+// validateStorageOverride forbids value at path to override the endpoint or the bucket of
+// BackupStorageLocation locationName: its credentials must not be sent to another storage.
+func validateStorageOverride(path *field.Path, locationName, value string) *field.Error {
+	if locationName != "" && value != "" {
+		return field.Forbidden(path, fmt.Sprintf("may not be set with BackupStorageLocation %s", locationName))
+	}
+	return nil
+}
+
+// validateSecretNamespace forbids references to Secrets outside namespace,
+// so namespace RBAC is enough to isolate credentials of different teams.
+func validateSecretNamespace(path *field.Path, refNamespace, namespace string) *field.Error {
+	if refNamespace != "" && refNamespace != namespace {
+		return field.Forbidden(path, fmt.Sprintf("Secret must be in namespace %s of the resource", namespace))
+	}
+	return nil
+}
+
+// validateRevision checks selectors of backupRevision. Other revisions are object keys or indexes.
+func validateRevision(path *field.Path, revision string) *field.Error {
+	switch {
+	case revision == "":
+		return field.Required(path, "revision must be set")
+	case strings.HasPrefix(revision, backupv1.RevisionNewestPrefix):
+		n, err := strconv.Atoi(strings.TrimPrefix(revision, backupv1.RevisionNewestPrefix))
+		if err != nil || n < 0 {
+			return field.Invalid(path, revision, "must be a non-negative number after "+backupv1.RevisionNewestPrefix)
+		}
+	case strings.HasPrefix(revision, backupv1.RevisionBeforePrefix):
+		if _, err := time.Parse(time.RFC3339, strings.TrimPrefix(revision, backupv1.RevisionBeforePrefix)); err != nil {
+			return field.Invalid(path, revision, "must be an RFC3339 time after "+backupv1.RevisionBeforePrefix)
+		}
+	case strings.HasPrefix(revision, backupv1.RevisionBackupRequestPrefix):
+		name := strings.TrimPrefix(revision, backupv1.RevisionBackupRequestPrefix)
+		if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+			return field.Invalid(path, revision, "must be a name of BackupRequest after "+
+				backupv1.RevisionBackupRequestPrefix+": "+strings.Join(msgs, ", "))
+		}
+	}
+	return nil
+}
+
+// validateRevisionKey forbids object keys outside backups of namespace, so a team never
+// restores backups of another one. database is the database backups were made of, empty
+// if only the BackupRequest making them is known; then only keys under namespace are allowed.
+// Restorers check keys again against the exact layout.Scope.
+func validateRevisionKey(path *field.Path, revision, namespace, database string) *field.Error {
+	if revision == backupv1.RevisionLatest {
+		return nil
+	}
+	for _, prefix := range []string{
+		backupv1.RevisionNewestPrefix, backupv1.RevisionBeforePrefix, backupv1.RevisionBackupRequestPrefix,
+	} {
+		if strings.HasPrefix(revision, prefix) {
+			return nil
+		}
+	}
+	if n, err := strconv.Atoi(revision); err == nil && n >= 0 {
+		return nil
+	}
+
+	scope := layout.Scope{Namespace: namespace, Database: database}
+	if database == "" {
+		if prefix := namespace + "/"; !strings.HasPrefix(revision, prefix) {
+			return field.Forbidden(path, "object key must be under "+prefix)
+		}
+	} else if !scope.Contains(revision) {
+		return field.Forbidden(path, "object key must be under "+strings.Join(scope.Prefixes(), " or "))
+	}
+	return nil
+}
+
+// validateMaxAge checks age of backups with the parser of backupers.
+// An empty age means no limit there, but here it must be set.
+func validateMaxAge(path *field.Path, age string) *field.Error {
+	d, err := retention.ParseAge(age)
+	if err != nil {
+		return field.Invalid(path, age, err.Error())
+	}
+	if d <= 0 {
+		return field.Invalid(path, age, "must be a positive age like 30d, 2w or 36h")
+	}
+	return nil
+}
+
+// noQueryHooks are database types whose backupers have no session to run queries of hooks in.
+var noQueryHooks = map[string]bool{
+	"mongodb": true,
+}
+
+// validateHooks checks that every hook runs exactly one of a query, a command or an HTTP request,
+// and that queries are run only against databases of dbType supporting them.
+func validateHooks(path *field.Path, hooks []backupv1.BackupHook, dbType string) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, hook := range hooks {
+		if hook.Query != "" && noQueryHooks[dbType] {
+			allErrs = append(allErrs, field.Forbidden(path.Index(i).Child("query"),
+				"query hooks are not supported for "+dbType))
+		}
+		actions := 0
+		if hook.Query != "" {
+			actions++
+		}
+		if len(hook.Command) > 0 {
+			actions++
+		}
+		if hook.HTTP != nil {
+			actions++
+		}
+		if actions != 1 {
+			allErrs = append(allErrs, field.Invalid(path.Index(i), hook.Name,
+				"exactly one of query, command or http must be set"))
+		}
+	}
+	return allErrs
+}
+
+// validateNotifications checks that every sink sets exactly one of webhook, slack or email
+// and that webhooks have a URL and a valid body template.
+func validateNotifications(path *field.Path, spec *backupv1.Notifications, namespace string) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.StaleAfter != nil && spec.StaleAfter.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("staleAfter"), spec.StaleAfter.Duration.String(), "must be positive"))
+	}
+	for i, sink := range spec.Sinks {
+		sinkPath := path.Child("sinks").Index(i)
+		kinds := 0
+		for _, set := range []bool{sink.Webhook != nil, sink.Slack != nil, sink.Email != nil} {
+			if set {
+				kinds++
+			}
+		}
+		if kinds != 1 {
+			allErrs = append(allErrs, field.Invalid(sinkPath, sink.Name, "exactly one of webhook, slack or email must be set"))
+			continue
+		}
+
+		var ref *backupv1.SecretKeyReference
+		var refPath *field.Path
+		switch {
+		case sink.Webhook != nil:
+			webhookPath := sinkPath.Child("webhook")
+			if sink.Webhook.URLSecretRef == nil {
+				if u, err := url.Parse(sink.Webhook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+					allErrs = append(allErrs, field.Invalid(webhookPath.Child("url"), sink.Webhook.URL,
+						"must be an http or https URL, or urlSecretRef must be set"))
+				}
+			}
+			if _, err := notify.ParseTemplate(sink.Webhook.BodyTemplate); err != nil {
+				allErrs = append(allErrs, field.Invalid(webhookPath.Child("bodyTemplate"), sink.Webhook.BodyTemplate, err.Error()))
+			}
+			ref, refPath = sink.Webhook.URLSecretRef, webhookPath.Child("urlSecretRef")
+		case sink.Slack != nil:
+			ref, refPath = &sink.Slack.URLSecretRef, sinkPath.Child("slack", "urlSecretRef")
+		case sink.Email != nil:
+			ref, refPath = sink.Email.PasswordSecretRef, sinkPath.Child("email", "passwordSecretRef")
+		}
+		if ref != nil {
+			if err := validateSecretNamespace(refPath.Child("namespace"), ref.Namespace, namespace); err != nil {
+				allErrs = append(allErrs, err)
+			}
+		}
+	}
+	return allErrs
+}
@@ -3,6 +3,7 @@ module backuper
 go 1.24.2
 
 require (
+	github.com/oiler-backup/core/common v0.0.0
 	github.com/caarlos0/env/v11 v11.3.1
 	github.com/oiler-backup/base v0.0.0-20250523073134-cc72e34a783e
 	github.com/stretchr/testify v1.10.0
@@ -88,3 +89,5 @@ require (
 	google.golang.org/protobuf v1.36.6 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/oiler-backup/core/common => ../../common
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"strings"
 )
 
 // An ErrBackup is required for more verbosity.
@@ -65,3 +66,18 @@ func (b Backuper) Backup(ctx context.Context, secure bool) error {
 	}
 	return nil
 }
+
+// DatabaseVersion is not supported for Mongo Database, since backuper has no driver for it.
+func (b Backuper) DatabaseVersion(ctx context.Context) (string, error) {
+	return "", nil
+}
+
+// ToolVersion returns version of mongodump CLI.
+func (b Backuper) ToolVersion(ctx context.Context) (string, error) {
+	output, err := exec.CommandContext(ctx, "mongodump", "--version").Output()
+	if err != nil { // coverage-ignore
+		return "", buildBackupError("Failed executing mongodump: %+v", err)
+	}
+	version, _, _ := strings.Cut(string(output), "\n")
+	return strings.TrimSpace(version), nil
+}
@@ -3,8 +3,11 @@ package config
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/caarlos0/env/v11"
+	"github.com/oiler-backup/core/common/layout"
+	"github.com/oiler-backup/core/common/retention"
 )
 
 // A Config stores configuraton.
@@ -19,9 +22,22 @@ type Config struct {
 	S3AccessKey  string `env:"S3_ACCESS_KEY,required,notEmpty,unset"`
 	S3SecretKey  string `env:"S3_SECRET_KEY,required,notEmpty,unset"`
 	S3BucketName string `env:"S3_BUCKET_NAME,required,notEmpty"`
+	// BackupPrefix is where backups of the BackupRequest are stored, see package layout.
+	BackupPrefix string `env:"BACKUP_PREFIX"`
 
 	MaxBackupCount int  `env:"MAX_BACKUP_COUNT"`
 	Secure         bool `env:"SECURE" envDefault:"false"` // TLS/SSL Encryption
+	Purge          bool `env:"PURGE" envDefault:"false"`  // Delete all backups instead of making one
+
+	// Grandfather-father-son retention, see package retention.
+	KeepHourly  int    `env:"KEEP_HOURLY"`
+	KeepDaily   int    `env:"KEEP_DAILY"`
+	KeepWeekly  int    `env:"KEEP_WEEKLY"`
+	KeepMonthly int    `env:"KEEP_MONTHLY"`
+	KeepYearly  int    `env:"KEEP_YEARLY"`
+	MaxAge      string `env:"MAX_AGE"` // e.g. 30d, 2w or 36h
+
+	Hooks string `env:"HOOKS"` // JSON of hooks run around backup, see package hooks
 }
 
 // GetConfig reads environment variables, validates them and return Config object or
@@ -35,11 +51,40 @@ func GetConfig() (Config, error) {
 	return cfg, nil
 }
 
+// RetentionPolicy tells which backups are kept after upload.
+// MaxBackupCount keeps the newest backups alongside tiers.
+func (c Config) RetentionPolicy() (retention.Policy, error) {
+	maxAge, err := retention.ParseAge(c.MaxAge)
+	if err != nil {
+		return retention.Policy{}, fmt.Errorf("invalid MAX_AGE: %w", err)
+	}
+	return retention.Policy{
+		Last:    c.MaxBackupCount,
+		Hourly:  c.KeepHourly,
+		Daily:   c.KeepDaily,
+		Weekly:  c.KeepWeekly,
+		Monthly: c.KeepMonthly,
+		Yearly:  c.KeepYearly,
+		MaxAge:  maxAge,
+	}, nil
+}
+
+// Prefix returns where backups are stored. Without BackupPrefix, which older
+// Kubernetes Operator Core does not pass, it is the legacy prefix of the database.
+func (c Config) Prefix() string {
+	if c.BackupPrefix == "" {
+		return layout.LegacyPrefix(c.DbName)
+	}
+	return strings.TrimSuffix(c.BackupPrefix, "/") + "/"
+}
+
 func (c Config) String() string {
 	return fmt.Sprintf("{DbHost: %s, DbPort: %s, DbUser: %s, DbPassword: <unset>, DbName: %s, "+
 		"CoreAddr: %s, S3Endpoint: %s, S3AccessKey: <unset>, S3SecretKey: <unset>, S3BucketName: %s, "+
-		"MaxBackupCount: %d, Secure: %t}",
+		"BackupPrefix: %s, MaxBackupCount: %d, Secure: %t, Purge: %t, KeepHourly: %d, KeepDaily: %d, KeepWeekly: %d, "+
+		"KeepMonthly: %d, KeepYearly: %d, MaxAge: %s}",
 		c.DbHost, c.DbPort, c.DbUser, c.DbName,
 		c.CoreAddr, c.S3Endpoint, c.S3BucketName,
-		c.MaxBackupCount, c.Secure)
+		c.BackupPrefix, c.MaxBackupCount, c.Secure, c.Purge, c.KeepHourly, c.KeepDaily, c.KeepWeekly,
+		c.KeepMonthly, c.KeepYearly, c.MaxAge)
 }
@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
+	"github.com/oiler-backup/core/common/retention"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -19,8 +21,10 @@ func Test_GetConfig_Success(t *testing.T) {
 	t.Setenv("S3_ACCESS_KEY", "access_key")
 	t.Setenv("S3_SECRET_KEY", "secret_key")
 	t.Setenv("S3_BUCKET_NAME", "backup-bucket")
+	t.Setenv("BACKUP_PREFIX", "team-a/daily/mydb/")
 	t.Setenv("MAX_BACKUP_COUNT", "5")
 	t.Setenv("SECURE", "true")
+	t.Setenv("PURGE", "true")
 
 	cfg, err := GetConfig()
 	require.NoError(t, err)
@@ -36,8 +40,10 @@ func Test_GetConfig_Success(t *testing.T) {
 		S3AccessKey:    "access_key",
 		S3SecretKey:    "secret_key",
 		S3BucketName:   "backup-bucket",
+		BackupPrefix:   "team-a/daily/mydb/",
 		MaxBackupCount: 5,
 		Secure:         true,
+		Purge:          true,
 	}
 
 	assert.Equal(t, expected, cfg)
@@ -97,6 +103,12 @@ func Test_GetConfig_DefaultValues(t *testing.T) {
 	assert.False(t, cfg.Secure)
 }
 
+func Test_Prefix(t *testing.T) {
+	assert.Equal(t, "mydb/", Config{DbName: "mydb"}.Prefix(), "legacy prefix without BACKUP_PREFIX")
+	assert.Equal(t, "team-a/daily/mydb/", Config{DbName: "mydb", BackupPrefix: "team-a/daily/mydb/"}.Prefix())
+	assert.Equal(t, "team-a/daily/mydb/", Config{DbName: "mydb", BackupPrefix: "team-a/daily/mydb"}.Prefix())
+}
+
 func Test_String(t *testing.T) {
 	t.Setenv("DB_HOST", "localhost")
 	t.Setenv("DB_PORT", "5432")
@@ -110,13 +122,26 @@ func Test_String(t *testing.T) {
 	t.Setenv("S3_BUCKET_NAME", "backup-bucket")
 	t.Setenv("MAX_BACKUP_COUNT", "5")
 	t.Setenv("SECURE", "true")
+	t.Setenv("KEEP_DAILY", "7")
+	t.Setenv("MAX_AGE", "30d")
 
 	cfg, err := GetConfig()
 	require.NoError(t, err)
 
 	expected := "{DbHost: localhost, DbPort: 5432, DbUser: user, DbPassword: <unset>, " +
 		"DbName: mydb, CoreAddr: http://core:8080, S3Endpoint: s3.example.com, S3AccessKey: <unset>, " +
-		"S3SecretKey: <unset>, S3BucketName: backup-bucket, MaxBackupCount: 5, Secure: true}"
+		"S3SecretKey: <unset>, S3BucketName: backup-bucket, BackupPrefix: , MaxBackupCount: 5, Secure: true, Purge: false, " +
+		"KeepHourly: 0, KeepDaily: 7, KeepWeekly: 0, KeepMonthly: 0, KeepYearly: 0, MaxAge: 30d}"
 	assert.Equal(t, expected, cfg.String())
 
 }
+
+func Test_RetentionPolicy(t *testing.T) {
+	cfg := Config{MaxBackupCount: 3, KeepHourly: 24, KeepDaily: 7, KeepWeekly: 4, KeepMonthly: 12, KeepYearly: 2, MaxAge: "2w"}
+	policy, err := cfg.RetentionPolicy()
+	require.NoError(t, err)
+	assert.Equal(t, retention.Policy{Last: 3, Hourly: 24, Daily: 7, Weekly: 4, Monthly: 12, Yearly: 2, MaxAge: 14 * 24 * time.Hour}, policy)
+
+	_, err = Config{MaxAge: "month"}.RetentionPolicy()
+	assert.ErrorContains(t, err, "invalid MAX_AGE")
+}
@@ -5,6 +5,7 @@ go 1.24.2
 require go.uber.org/zap v1.27.0
 
 require (
+	github.com/oiler-backup/core/common v0.0.0
 	github.com/aws/aws-sdk-go-v2 v1.36.3 // indirect
 	github.com/aws/aws-sdk-go-v2/config v1.29.14 // indirect
 	github.com/aws/aws-sdk-go-v2/service/s3 v1.79.3 // indirect
@@ -12,7 +13,7 @@ require (
 	github.com/golang/protobuf v1.5.4 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/objx v0.5.2 // indirect
-	github.com/stretchr/testify v1.10.0 // indirect
+	github.com/stretchr/testify v1.10.0
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.org/x/net v0.40.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
@@ -42,3 +43,5 @@ require (
 	github.com/caarlos0/env/v11 v11.3.1
 	github.com/oiler-backup/base v0.0.0-20250523223223-14d6ab2fe7f5
 )
+
+replace github.com/oiler-backup/core/common => ../../common
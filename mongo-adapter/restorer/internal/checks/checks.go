@@ -0,0 +1,110 @@
+// Package checks runs queries verifying a restored database.
+package checks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/oiler-backup/core/common/report"
+)
+
+// A Check is a query which must match at least MinRows documents.
+// Query is a JSON object with collection and optional filter,
+// e.g. {"collection": "orders", "filter": {"status": "paid"}}.
+type Check struct {
+	Name    string `json:"name"`
+	Query   string `json:"query"`
+	MinRows int64  `json:"minRows"`
+}
+
+// maxMessage is how many bytes of an error a Result keeps,
+// so results of all checks fit the termination message.
+const maxMessage = 160
+
+// A Result is an outcome of Check.
+type Result struct {
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Rows     int64  `json:"rows"`
+	Duration string `json:"duration"`
+	Message  string `json:"message,omitempty"`
+}
+
+type query struct {
+	Collection string          `json:"collection"`
+	Filter     json.RawMessage `json:"filter"`
+}
+
+// An Exporter prints documents of collection matching filter, one per line.
+type Exporter func(ctx context.Context, collection, filter string) ([]byte, error)
+
+// Parse parses checks from JSON list. Empty string means no checks.
+func Parse(s string) ([]Check, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var checks []Check
+	if err := json.Unmarshal([]byte(s), &checks); err != nil {
+		return nil, fmt.Errorf("failed to parse checks: %w", err)
+	}
+	return checks, nil
+}
+
+// Run runs checks one by one. Failed check does not stop the others.
+func Run(ctx context.Context, export Exporter, checks []Check) []Result {
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		start := time.Now()
+		rows, err := countDocuments(ctx, export, check.Query)
+		results = append(results, evaluate(check, rows, time.Since(start), err))
+	}
+	return results
+}
+
+func countDocuments(ctx context.Context, export Exporter, rawQuery string) (int64, error) {
+	var q query
+	if err := json.Unmarshal([]byte(rawQuery), &q); err != nil {
+		return 0, fmt.Errorf("invalid query: %w", err)
+	}
+	if q.Collection == "" {
+		return 0, fmt.Errorf("invalid query: collection must be set")
+	}
+	filter := "{}"
+	if len(q.Filter) > 0 {
+		filter = string(q.Filter)
+	}
+
+	output, err := export(ctx, q.Collection, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// evaluate tells whether check passed given documents matched by its query.
+func evaluate(check Check, rows int64, duration time.Duration, err error) Result {
+	result := Result{
+		Name:     check.Name,
+		Rows:     rows,
+		Duration: duration.Round(time.Millisecond).String(),
+	}
+	switch {
+	case err != nil:
+		result.Message = report.Truncate(err.Error(), maxMessage)
+	case rows < check.MinRows:
+		result.Message = fmt.Sprintf("query returned %d rows, expected at least %d", rows, check.MinRows)
+	default:
+		result.Passed = true
+	}
+	return result
+}
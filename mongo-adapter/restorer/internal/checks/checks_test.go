@@ -0,0 +1,56 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Parse(t *testing.T) {
+	checks, err := Parse(`[{"name":"orders","query":"{\"collection\":\"orders\"}","minRows":10}]`)
+	require.NoError(t, err)
+	assert.Equal(t, []Check{{Name: "orders", Query: `{"collection":"orders"}`, MinRows: 10}}, checks)
+
+	checks, err = Parse("")
+	require.NoError(t, err)
+	assert.Empty(t, checks)
+}
+
+func Test_Run(t *testing.T) {
+	var filters []string
+	export := func(ctx context.Context, collection, filter string) ([]byte, error) {
+		filters = append(filters, filter)
+		switch collection {
+		case "orders":
+			return []byte("{\"_id\":1}\n{\"_id\":2}\n"), nil
+		case "users":
+			return nil, nil
+		}
+		return nil, errors.New("exit status 1")
+	}
+
+	results := Run(context.Background(), export, []Check{
+		{Name: "paid orders", Query: `{"collection":"orders","filter":{"status":"paid"}}`, MinRows: 2},
+		{Name: "users", Query: `{"collection":"users"}`, MinRows: 1},
+		{Name: "failing", Query: `{"collection":"payments"}`},
+		{Name: "no collection", Query: `{"filter":{}}`},
+	})
+
+	require.Len(t, results, 4)
+	assert.True(t, results[0].Passed)
+	assert.Equal(t, int64(2), results[0].Rows)
+	assert.False(t, results[1].Passed)
+	assert.Equal(t, "query returned 0 rows, expected at least 1", results[1].Message)
+	assert.Equal(t, "exit status 1", results[2].Message)
+	assert.Equal(t, "invalid query: collection must be set", results[3].Message)
+	assert.Equal(t, []string{`{"status":"paid"}`, "{}", "{}"}, filters)
+}
+
+func Test_Evaluate_TruncatesMessage(t *testing.T) {
+	result := evaluate(Check{Name: "orders"}, 0, 0, errors.New(strings.Repeat("x", 1000)))
+	assert.Len(t, result.Message, maxMessage)
+}
@@ -3,6 +3,7 @@ package restorer
 import (
 	"context"
 	"fmt"
+	"mongodb_restorer/internal/checks"
 	"os/exec"
 )
 
@@ -12,34 +13,77 @@ type Resotrer struct {
 	dbUser string
 	dbPass string
 	dbName string
+	// sourceDbName is a database the backup was made of.
+	sourceDbName string
 
 	backupPath string
 }
 
-func NewRestorer(dbHost, dbPort, dbUser, dbPassword, dbName, backupPath string) Resotrer {
+func NewRestorer(dbHost, dbPort, dbUser, dbPassword, dbName, sourceDbName, backupPath string) Resotrer {
 	return Resotrer{
-		dbHost:     dbHost,
-		dbPort:     dbPort,
-		dbUser:     dbUser,
-		dbPass:     dbPassword,
-		dbName:     dbName,
-		backupPath: backupPath,
+		dbHost:       dbHost,
+		dbPort:       dbPort,
+		dbUser:       dbUser,
+		dbPass:       dbPassword,
+		dbName:       dbName,
+		sourceDbName: sourceDbName,
+		backupPath:   backupPath,
 	}
 }
 
 func (r Resotrer) Restore(ctx context.Context) error {
-	cmd := exec.Command("mongorestore",
+	cmd := exec.Command("mongorestore", r.args()...)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed executing mongorestore: %+v\n.Output:%s", err, string(output))
+	}
+	return nil
+}
+
+// args returns arguments of mongorestore. Collections of another database
+// are renamed to the target one, so a backup can be cloned.
+func (r Resotrer) args() []string {
+	args := []string{
+		"--host", r.dbHost,
+		"--port", r.dbPort,
+		"--username", r.dbUser,
+		"--password", r.dbPass,
+		"--authenticationDatabase", "admin",
+	}
+	if r.sourceDbName == "" || r.sourceDbName == r.dbName {
+		args = append(args, "--db", r.dbName)
+	} else {
+		args = append(args,
+			fmt.Sprintf("--nsInclude=%s.*", r.sourceDbName),
+			fmt.Sprintf("--nsFrom=%s.*", r.sourceDbName),
+			fmt.Sprintf("--nsTo=%s.*", r.dbName),
+		)
+	}
+	return append(args, fmt.Sprint("--archive=", r.backupPath))
+}
+
+// Check runs checks against the restored database.
+func (r Resotrer) Check(ctx context.Context, list []checks.Check) ([]checks.Result, error) {
+	return checks.Run(ctx, r.export, list), nil
+}
+
+// export prints documents of collection matching filter by mongoexport.
+func (r Resotrer) export(ctx context.Context, collection, filter string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "mongoexport",
 		"--host", r.dbHost,
 		"--port", r.dbPort,
 		"--username", r.dbUser,
 		"--password", r.dbPass,
+		"--authenticationDatabase", "admin",
 		"--db", r.dbName,
-		fmt.Sprint("--archive=", r.backupPath),
+		"--collection", collection,
+		"--query", filter,
+		"--quiet",
 	)
-
-	output, err := cmd.CombinedOutput()
+	output, err := cmd.Output()
 	if err != nil {
-		return fmt.Errorf("failed executing mongorestore: %+v\n.Output:%s", err, string(output))
+		return nil, fmt.Errorf("failed executing mongoexport: %w", err)
 	}
-	return nil
+	return output, nil
 }
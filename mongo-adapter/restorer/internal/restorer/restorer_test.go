@@ -0,0 +1,37 @@
+package restorer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Args(t *testing.T) {
+	r := NewRestorer("mongo", "27017", "admin", "pass", "orders", "orders", "/tmp/backup.tar")
+
+	assert.Equal(t, []string{
+		"--host", "mongo",
+		"--port", "27017",
+		"--username", "admin",
+		"--password", "pass",
+		"--authenticationDatabase", "admin",
+		"--db", "orders",
+		"--archive=/tmp/backup.tar",
+	}, r.args())
+}
+
+func Test_Args_Clone(t *testing.T) {
+	r := NewRestorer("mongo", "27017", "admin", "pass", "orders_staging", "orders", "/tmp/backup.tar")
+
+	assert.Equal(t, []string{
+		"--host", "mongo",
+		"--port", "27017",
+		"--username", "admin",
+		"--password", "pass",
+		"--authenticationDatabase", "admin",
+		"--nsInclude=orders.*",
+		"--nsFrom=orders.*",
+		"--nsTo=orders_staging.*",
+		"--archive=/tmp/backup.tar",
+	}, r.args())
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"mongodb_restorer/internal/checks"
 	"mongodb_restorer/internal/config"
 	"mongodb_restorer/internal/restorer"
 	"os"
@@ -11,12 +13,14 @@ import (
 	loggerbase "github.com/oiler-backup/base/logger"
 	metricsbase "github.com/oiler-backup/base/metrics"
 	s3base "github.com/oiler-backup/base/s3"
+	"github.com/oiler-backup/core/common/revision"
 	"go.uber.org/zap"
 )
 
 const (
-	S3REGION    = "us-east-1" // Fictious
-	BACKUP_PATH = "/tmp/backup.tar"
+	S3REGION        = "us-east-1" // Fictious
+	BACKUP_PATH     = "/tmp/backup.tar"
+	TERMINATION_LOG = "/dev/termination-log"
 )
 
 var (
@@ -42,14 +46,28 @@ func main() {
 		panic(fmt.Sprintf("Failed to configurate: %v", err))
 	}
 
-	restorer := restorer.NewRestorer(cfg.DbHost, cfg.DbPort, cfg.DbUser, cfg.DbPassword, cfg.DbName, BACKUP_PATH)
+	restorer := restorer.NewRestorer(cfg.DbHost, cfg.DbPort, cfg.DbUser, cfg.DbPassword, cfg.DbName, cfg.BackupDbName(), BACKUP_PATH)
 	metricsReporter = metricsbase.NewMetricsReporter(cfg.CoreAddr, false)
+	checkList, err := checks.Parse(cfg.Checks)
+	if err != nil {
+		mustProccessErrors("Failed to parse checks", err)
+	}
 	downloader, err := s3base.NewS3Downloader(ctx, cfg.S3Endpoint, cfg.S3AccessKey, cfg.S3SecretKey, S3REGION, cfg.Secure)
 	if err != nil {
 		mustProccessErrors("Failed to create downloader", err)
 	}
 
-	err = downloader.Download(ctx, cfg.S3BucketName, cfg.DbName, cfg.BackupRevision, BACKUP_PATH)
+	s3Client, err := s3base.NewS3Client(ctx, cfg.S3Endpoint, cfg.S3AccessKey, cfg.S3SecretKey, S3REGION, cfg.Secure)
+	if err != nil {
+		mustProccessErrors("Failed to create S3 client", err)
+	}
+	backupRevision, err := revision.Resolve(ctx, s3Client, cfg.S3BucketName, cfg.Scope(), cfg.BackupRevision)
+	if err != nil {
+		mustProccessErrors("Failed to resolve backup revision", err, "revision", cfg.BackupRevision)
+	}
+	logger.Infow("Restoring backup", "revision", cfg.BackupRevision, "key", backupRevision)
+
+	err = downloader.Download(ctx, cfg.S3BucketName, cfg.BackupDbName(), backupRevision, BACKUP_PATH)
 	if err != nil {
 		mustProccessErrors("Failed to perform download", err)
 	}
@@ -59,6 +77,12 @@ func main() {
 		mustProccessErrors("Faild to restore backup", err)
 	}
 
+	results, err := restorer.Check(ctx, checkList)
+	if err != nil {
+		mustProccessErrors("Failed to run checks", err)
+	}
+	writeRestoreReport(backupRevision, results)
+
 	err = metricsReporter.ReportStatus(ctx, backupName, true, time.Now().Unix())
 	if err != nil {
 		mustProccessErrors("Failed to report successful status", err)
@@ -66,6 +90,22 @@ func main() {
 	logger.Infof("Backup was applied successfully")
 }
 
+// writeRestoreReport tells the core which backup was restored and how checks of it went.
+func writeRestoreReport(key string, results []checks.Result) {
+	report := struct {
+		Key    string          `json:"key"`
+		Checks []checks.Result `json:"checks,omitempty"`
+	}{Key: key, Checks: results}
+
+	data, err := json.Marshal(report)
+	if err == nil {
+		err = os.WriteFile(TERMINATION_LOG, data, 0o644)
+	}
+	if err != nil {
+		logger.Warnw("Failed to write restore report", "error", err)
+	}
+}
+
 func mustProccessErrors(msg string, err error, keysAndValues ...any) {
 	logger.Errorw(msg, "error", err, keysAndValues)
 	err = metricsReporter.ReportStatus(ctx, backupName, false, -1)
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	pb "github.com/oiler-backup/base/proto"
+	serverscommon "github.com/oiler-backup/core/common/servers/backup"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// deleteRequest asks to delete CronJob old-cj of BackupRequest with schedule.
+func deleteRequest(t *testing.T, schedule string) *serverscommon.DeleteRequest {
+	request, err := protojson.Marshal(&pb.BackupRequest{Schedule: schedule})
+	require.NoError(t, err)
+	return &serverscommon.DeleteRequest{Request: request, CronjobName: "old-cj", CronjobNamespace: "default"}
+}
+
+func Test_Delete(t *testing.T) {
+	mockJobsStub := new(MockJobsStub)
+	mockJobsCreator := new(MockJobsCreator)
+	kubeClient := fake.NewClientset(scheduledCronJob("old-cj", "0 0 * * *", "backuper"))
+	server := &BackupServer{
+		kubeClient:  kubeClient,
+		jobsStub:    mockJobsStub,
+		jobsCreator: mockJobsCreator,
+		namespace:   "default",
+	}
+	req := deleteRequest(t, "0 0 * * *")
+
+	mockJobsStub.On("BuildBackuperCj", "0 0 * * *", mock.Anything).Return(backuperCronJob())
+	mockJobsCreator.On("CreateJob", mock.Anything, mock.MatchedBy(func(job *batchv1.Job) bool {
+		return job.Name == "purge-old-cj" &&
+			job.Namespace == "team-a" &&
+			job.Annotations["cronjob.kubernetes.io/instantiate"] == "" &&
+			assert.ObjectsAreEqual([]corev1.EnvVar{{Name: serverscommon.PurgeEnv, Value: "true"}}, job.Spec.Template.Spec.Containers[0].Env)
+	})).Return("purge-old-cj", "team-a", nil)
+
+	resp, err := server.Delete(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, &serverscommon.DeleteResponse{JobName: "purge-old-cj", JobNamespace: "team-a"}, resp)
+
+	_, err = kubeClient.BatchV1().CronJobs("default").Get(context.Background(), "old-cj", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+	mockJobsCreator.AssertExpectations(t)
+}
+
+func Test_Delete_MissingCronJob(t *testing.T) {
+	mockJobsStub := new(MockJobsStub)
+	mockJobsCreator := new(MockJobsCreator)
+	server := &BackupServer{
+		kubeClient:  fake.NewClientset(),
+		jobsStub:    mockJobsStub,
+		jobsCreator: mockJobsCreator,
+		namespace:   "default",
+	}
+	req := deleteRequest(t, "")
+
+	mockJobsStub.On("BuildBackuperCj", "", mock.Anything).Return(backuperCronJob())
+	mockJobsCreator.On("CreateJob", mock.Anything, mock.Anything).Return("purge-old-cj", "team-a", nil)
+
+	resp, err := server.Delete(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "purge-old-cj", resp.JobName)
+}
+
+func Test_Delete_Retried(t *testing.T) {
+	mockJobsStub := new(MockJobsStub)
+	mockJobsCreator := new(MockJobsCreator)
+	kubeClient := fake.NewClientset(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "purge-old-cj", Namespace: "team-a"}})
+	server := &BackupServer{
+		kubeClient:  kubeClient,
+		jobsStub:    mockJobsStub,
+		jobsCreator: mockJobsCreator,
+		namespace:   "default",
+	}
+	req := deleteRequest(t, "0 0 * * *")
+
+	mockJobsStub.On("BuildBackuperCj", "0 0 * * *", mock.Anything).Return(backuperCronJob())
+
+	resp, err := server.Delete(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, &serverscommon.DeleteResponse{JobName: "purge-old-cj", JobNamespace: "team-a"}, resp)
+	mockJobsCreator.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything)
+}
+
+func Test_Delete_CreationError(t *testing.T) {
+	mockJobsStub := new(MockJobsStub)
+	mockJobsCreator := new(MockJobsCreator)
+	kubeClient := fake.NewClientset(scheduledCronJob("old-cj", "0 0 * * *", "backuper"))
+	server := &BackupServer{
+		kubeClient:  kubeClient,
+		jobsStub:    mockJobsStub,
+		jobsCreator: mockJobsCreator,
+		namespace:   "default",
+	}
+	req := deleteRequest(t, "")
+
+	mockJobsStub.On("BuildBackuperCj", "", mock.Anything).Return(backuperCronJob())
+	mockJobsCreator.On("CreateJob", mock.Anything, mock.Anything).Return("", "", errors.New("forbidden"))
+
+	_, err := server.Delete(context.Background(), req)
+	assert.EqualError(t, err, "failed to create purge Job: forbidden")
+
+	_, err = kubeClient.BatchV1().CronJobs("default").Get(context.Background(), "old-cj", metav1.GetOptions{})
+	assert.NoError(t, err, "CronJob is kept until backups can be purged")
+}
+
+func Test_Delete_InvalidRequest(t *testing.T) {
+	server := &BackupServer{kubeClient: fake.NewClientset(), namespace: "default"}
+
+	_, err := server.Delete(context.Background(), &serverscommon.DeleteRequest{Request: []byte("{")})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -7,12 +7,16 @@ import (
 	"log"
 
 	"google.golang.org/grpc"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
 
 	pb "github.com/oiler-backup/base/proto"
 	serversbase "github.com/oiler-backup/base/servers/backup"
 	eg "github.com/oiler-backup/base/servers/backup/envgetters"
+	serverscommon "github.com/oiler-backup/core/common/servers/backup"
 )
 
 // An ErrBackupServer is required for more verbosity.
@@ -23,7 +27,7 @@ type ErrBackupServer = error
 // and create underlying resources.
 type BackupServer struct {
 	pb.UnimplementedBackupServiceServer
-	kubeClient    *kubernetes.Clientset
+	kubeClient    kubernetes.Interface
 	jobsCreator   serversbase.IJobsCreator
 	namespace     string
 	backuperImage string
@@ -32,7 +36,8 @@ type BackupServer struct {
 }
 
 // NewBackupServer is a constructor for BackupServer.
-// Accepts systemNamespace where underlying resources will be created.
+// Accepts systemNamespace where underlying resources will be created
+// unless Core places them to namespace of a resource with overlay.
 // backuperImg and restorerImg will be used as images in Kubernetes pods
 func NewBackupServer(systemNamespace, backuperImg, restorerImg string) (*BackupServer, error) { // coverage-ignore
 	config, err := rest.InClusterConfig()
@@ -68,34 +73,27 @@ func RegisterBackupServer(grpcServer *grpc.Server, systemNamespace, backuperImag
 		return err
 	}
 	pb.RegisterBackupServiceServer(grpcServer, server)
+	serverscommon.RegisterDeletionServer(grpcServer, server)
 
 	return nil
 }
 
 // Backup creates CronJob with backuper image.
+// Hashed values of the schedule are expanded, see applySchedule.
 // Validates CronJob is actually created.
 // Returns Status "Exists" in case of already created resource.
+// If Core asks to run backup once, a Job built from the CronJob is created instead, see runOnce.
 func (s *BackupServer) Backup(ctx context.Context, req *pb.BackupRequest) (*pb.BackupResponse, error) {
-	cj := s.jobsStub.BuildBackuperCj(
-		req.Schedule,
-		eg.NewEnvGetterMerger([]eg.EnvGetter{
-			eg.CommonEnvGetter{
-				DbUri:        req.DbUri,
-				DbPort:       fmt.Sprint(req.DbPort),
-				DbUser:       req.DbUser,
-				DbPass:       req.DbPass,
-				DbName:       req.DbName,
-				S3Endpoint:   req.S3Endpoint,
-				S3AccessKey:  req.S3AccessKey,
-				S3SecretKey:  req.S3SecretKey,
-				S3BucketName: req.S3BucketName,
-				CoreAddr:     req.CoreAddr,
-			},
-			eg.BackuperEnvGetter{
-				MaxBackupCount: int(req.MaxBackupCount),
-			},
-		}),
-	)
+	cj := s.jobsStub.BuildBackuperCj(req.Schedule, backuperEnvGetter(req))
+	if err := serverscommon.ApplyCronJobOverlay(ctx, cj); err != nil {
+		return nil, err
+	}
+	if err := applySchedule(cj, req); err != nil {
+		return nil, err
+	}
+	if serverscommon.RunOnceFromContext(ctx) {
+		return s.runOnce(ctx, cj)
+	}
 	name, namespace, err := s.jobsCreator.CreateCronJob(ctx, cj)
 	if errors.Is(err, serversbase.ErrAlreadyExists) {
 		return &pb.BackupResponse{
@@ -116,31 +114,48 @@ func (s *BackupServer) Backup(ctx context.Context, req *pb.BackupRequest) (*pb.B
 	}, nil
 }
 
-// Update performs update of a CronJob with backuper.
-// Currently only changes environment variables.
+// runOnce creates a one-off Job from cj.
+// CronjobName and CronjobNamespace of the response hold name and namespace of the Job.
+func (s *BackupServer) runOnce(ctx context.Context, cj *batchv1.CronJob) (*pb.BackupResponse, error) {
+	name, namespace, err := s.jobsCreator.CreateJob(ctx, serverscommon.JobFromCronJob(cj))
+	if err != nil {
+		log.Printf("Failed to create Job: %v", err)
+		return &pb.BackupResponse{Status: "Failed to create Job"}, nil
+	}
+
+	return &pb.BackupResponse{
+		Status:           "Job created successfully",
+		CronjobName:      name,
+		CronjobNamespace: namespace,
+	}, nil
+}
+
+// Update reconciles CronJob with backuper against req.
+// Spec of the CronJob is replaced by the one Backup would create now: schedule,
+// image, resources, env and overlay passed by Core. Metadata of the CronJob is kept.
 func (s *BackupServer) Update(ctx context.Context, req *pb.UpdateBackupRequest) (*pb.BackupResponse, error) {
-	err := s.jobsCreator.UpdateCronJob(
-		ctx,
-		req.CronjobName,
-		req.CronjobNamespace,
-		eg.NewEnvGetterMerger([]eg.EnvGetter{
-			eg.CommonEnvGetter{
-				DbUri:        req.Request.DbUri,
-				DbPort:       fmt.Sprint(req.Request.DbPort),
-				DbUser:       req.Request.DbUser,
-				DbPass:       req.Request.DbPass,
-				DbName:       req.Request.DbName,
-				S3Endpoint:   req.Request.S3Endpoint,
-				S3AccessKey:  req.Request.S3AccessKey,
-				S3SecretKey:  req.Request.S3SecretKey,
-				S3BucketName: req.Request.S3BucketName,
-				CoreAddr:     req.Request.CoreAddr,
-			},
-			eg.BackuperEnvGetter{
-				MaxBackupCount: int(req.Request.MaxBackupCount),
-			},
-		}).GetEnvs(),
-	)
+	desired := s.jobsStub.BuildBackuperCj(req.Request.Schedule, backuperEnvGetter(req.Request))
+	if err := serverscommon.ApplyCronJobOverlay(ctx, desired); err != nil {
+		return &pb.BackupResponse{
+			Status: "Failed to apply overlay",
+		}, err
+	}
+	if err := applySchedule(desired, req.Request); err != nil {
+		return &pb.BackupResponse{
+			Status: "Failed to expand schedule",
+		}, err
+	}
+
+	cronJobs := s.kubeClient.BatchV1().CronJobs(req.CronjobNamespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cj, err := cronJobs.Get(ctx, req.CronjobName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		cj.Spec = desired.Spec
+		_, err = cronJobs.Update(ctx, cj, metav1.UpdateOptions{})
+		return err
+	})
 	if err != nil {
 		return &pb.BackupResponse{
 			Status: "Failed to update cronjob",
@@ -154,6 +169,32 @@ func (s *BackupServer) Update(ctx context.Context, req *pb.UpdateBackupRequest)
 	}, nil
 }
 
+// backuperEnvGetter returns envs of backuper for req.
+func backuperEnvGetter(req *pb.BackupRequest) eg.EnvGetter {
+	return eg.NewEnvGetterMerger([]eg.EnvGetter{
+		eg.CommonEnvGetter{
+			DbUri:        req.DbUri,
+			DbPort:       fmt.Sprint(req.DbPort),
+			DbUser:       req.DbUser,
+			DbPass:       req.DbPass,
+			DbName:       req.DbName,
+			S3Endpoint:   req.S3Endpoint,
+			S3AccessKey:  req.S3AccessKey,
+			S3SecretKey:  req.S3SecretKey,
+			S3BucketName: req.S3BucketName,
+			CoreAddr:     req.CoreAddr,
+		},
+		eg.BackuperEnvGetter{
+			MaxBackupCount: int(req.MaxBackupCount),
+		},
+	})
+}
+
+// applySchedule expands hashed values of the schedule of cj built for req, see schedule.Expand.
+func applySchedule(cj *batchv1.CronJob, req *pb.BackupRequest) serverscommon.ErrSchedule {
+	return serverscommon.ApplySchedule(cj, serverscommon.ScheduleSeed(cj.Namespace, req.DbUri, req.DbPort, req.DbName))
+}
+
 // Restore restores backup from s3-compatible storage.
 func (s *BackupServer) Restore(ctx context.Context, req *pb.BackupRestore) (*pb.BackupRestoreResponse, error) {
 	job := s.jobsStub.BuildRestorerJob(
@@ -176,6 +217,9 @@ func (s *BackupServer) Restore(ctx context.Context, req *pb.BackupRestore) (*pb.
 		},
 		),
 	)
+	if err := serverscommon.ApplyJobOverlay(ctx, job); err != nil {
+		return nil, err
+	}
 	name, namespace, err := s.jobsCreator.CreateJob(ctx, job)
 	if errors.Is(err, serversbase.ErrAlreadyExists) {
 		return &pb.BackupRestoreResponse{
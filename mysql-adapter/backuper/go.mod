@@ -3,6 +3,7 @@ module mysql_backuper
 go 1.24.2
 
 require (
+	github.com/oiler-backup/core/common v0.0.0
 	github.com/caarlos0/env/v11 v11.3.1
 	github.com/go-sql-driver/mysql v1.9.2
 	github.com/oiler-backup/base v0.0.0-20250523073134-cc72e34a783e
@@ -90,3 +91,5 @@ require (
 	google.golang.org/protobuf v1.36.6 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/oiler-backup/core/common => ../../common
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"fmt"
 	"os/exec"
+	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -43,11 +44,13 @@ func NewBackuper(dbHost, dbPort, dbUser, dbPassword, dbName, backupPath string)
 	}
 }
 
+func (b Backuper) connString() string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", b.dbUser, b.dbPass, b.dbHost, b.dbPort, b.dbName)
+}
+
 // Backup performs backup of MySQL Database by using mysqldump CLI.
 func (b Backuper) Backup(ctx context.Context, secure bool) error {
-	connStr := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", b.dbUser, b.dbPass, b.dbHost, b.dbPort, b.dbName)
-
-	db, err := sql.Open("mysql", connStr)
+	db, err := sql.Open("mysql", b.connString())
 	if err != nil { // coverage-ignore
 		return buildBackupError("Failed to open driver for database: %+v", err)
 	}
@@ -86,3 +89,36 @@ func (b Backuper) Backup(ctx context.Context, secure bool) error {
 	}
 	return nil
 }
+
+// Open opens a pool of connections to the database, e.g. to run hooks around Backup.
+func (b Backuper) Open() (*sql.DB, error) {
+	db, err := sql.Open("mysql", b.connString())
+	if err != nil { // coverage-ignore
+		return nil, buildBackupError("Failed to open driver for database: %+v", err)
+	}
+	return db, nil
+}
+
+// DatabaseVersion returns version of MySQL server.
+func (b Backuper) DatabaseVersion(ctx context.Context) (string, error) {
+	db, err := sql.Open("mysql", b.connString())
+	if err != nil { // coverage-ignore
+		return "", buildBackupError("Failed to open driver for database: %+v", err)
+	}
+	defer db.Close()
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version); err != nil {
+		return "", buildBackupError("Failed to query server version: %+v", err)
+	}
+	return version, nil
+}
+
+// ToolVersion returns version of mysqldump CLI.
+func (b Backuper) ToolVersion(ctx context.Context) (string, error) {
+	output, err := exec.CommandContext(ctx, "mysqldump", "--version").Output()
+	if err != nil { // coverage-ignore
+		return "", buildBackupError("Failed executing mysqldump: %+v", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
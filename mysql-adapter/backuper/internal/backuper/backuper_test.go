@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -61,6 +62,10 @@ func Test_Backup_CreatesValidDump(t *testing.T) {
 	fileInfo, err := os.Stat(backupFile)
 	require.NoError(t, err)
 	assert.Greater(t, fileInfo.Size(), int64(0))
+
+	version, err := b.DatabaseVersion(ctx)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(version, "8.0."), version)
 }
 
 func Test_BuildBackup(t *testing.T) {
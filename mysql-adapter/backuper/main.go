@@ -4,8 +4,12 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"time"
 
 	"mysql_backuper/internal/backuper"
@@ -14,12 +18,18 @@ import (
 	loggerbase "github.com/oiler-backup/base/logger"
 	metricsbase "github.com/oiler-backup/base/metrics"
 	s3base "github.com/oiler-backup/base/s3"
+	"github.com/oiler-backup/core/common/hooks"
+	"github.com/oiler-backup/core/common/layout"
+	"github.com/oiler-backup/core/common/report"
+	"github.com/oiler-backup/core/common/retention"
 	"go.uber.org/zap"
 )
 
 const (
 	S3REGION    = "us-east-1" // Fictious
 	BACKUP_PATH = "/tmp/backup.sql"
+	// TERMINATION_LOG is read by the core as a termination message of the container
+	TERMINATION_LOG = "/dev/termination-log"
 )
 
 var (
@@ -27,8 +37,26 @@ var (
 	metricsReporter metricsbase.MetricsReporter
 	ctx             context.Context
 	backupName      string
+	// hookResults are reported by the run report, even if the backup fails.
+	hookResults []hooks.Result
 )
 
+// A runReport is written to the termination message of the container.
+// Error is set if the backup failed after hooks were run.
+type runReport struct {
+	Key             string         `json:"key,omitempty"`
+	Size            int64          `json:"size,omitempty"`
+	Checksum        string         `json:"checksum,omitempty"`
+	DatabaseVersion string         `json:"databaseVersion,omitempty"`
+	ToolVersion     string         `json:"toolVersion,omitempty"`
+	Hooks           []hooks.Result `json:"hooks,omitempty"`
+	// Deleted are keys of backups deleted by the retention policy, so the core deletes their Backups.
+	// DeletedOmitted counts keys dropped to fit the report.
+	Deleted        []string `json:"deleted,omitempty"`
+	DeletedOmitted int      `json:"deletedOmitted,omitempty"`
+	Error          string   `json:"error,omitempty"`
+}
+
 func main() {
 	ctx = context.Background()
 
@@ -44,32 +72,69 @@ func main() {
 	if err != nil {
 		panic(fmt.Sprintf("Failed to configurate: %v", err))
 	}
+	if cfg.Purge {
+		purgeBackups(cfg)
+		return
+	}
 	backupName = fmt.Sprintf("%s:%s/%s", cfg.DbHost, cfg.DbPort, cfg.DbName)
+
+	// Backward metrics reporter
+	metricsReporter = metricsbase.NewMetricsReporter(cfg.CoreAddr, false)
+
+	policy, err := cfg.RetentionPolicy()
+	if err != nil {
+		mustProccessErrors("Failed to configure retention", err)
+	}
 	backuper := backuper.NewBackuper(cfg.DbHost, cfg.DbPort, cfg.DbUser, cfg.DbPassword, cfg.DbName, BACKUP_PATH)
-	s3UploaderCleaner, err := s3base.NewS3UploadCleaner(ctx, cfg.S3Endpoint, cfg.S3AccessKey, cfg.S3SecretKey, S3REGION, cfg.Secure)
+	s3Uploader, err := s3base.NewS3Uploader(ctx, cfg.S3Endpoint, cfg.S3AccessKey, cfg.S3SecretKey, S3REGION, cfg.Secure)
 	if err != nil {
 		mustProccessErrors("Failed to initialize s3Uploader: %+v", err)
 	}
+	s3Client, err := s3base.NewS3Client(ctx, cfg.S3Endpoint, cfg.S3AccessKey, cfg.S3SecretKey, S3REGION, cfg.Secure)
+	if err != nil {
+		mustProccessErrors("Failed to initialize s3 client: %+v", err)
+	}
 
-	// Backward metrics reporter
-	metricsReporter = metricsbase.NewMetricsReporter(cfg.CoreAddr, false)
+	hookSpec, err := hooks.Parse(cfg.Hooks)
+	if err != nil {
+		mustProccessErrors("Failed to configure hooks", err)
+	}
+	runner, closeSession := newHookRunner(backuper, hookSpec)
 
 	start := time.Now()
-	err = backuper.Backup(ctx, cfg.Secure)
+	hookResults, err = runner.Run(ctx, hooks.PhasePre, hookSpec.Pre)
+	if err == nil {
+		err = backuper.Backup(ctx, cfg.Secure)
+	}
+	// Post hooks always run, e.g. to release locks taken by pre hooks.
+	postResults, postErr := runner.Run(ctx, hooks.PhasePost, hookSpec.Post)
+	hookResults = append(hookResults, postResults...)
+	closeSession()
+	if err == nil {
+		err = postErr
+	}
 	if err != nil {
 		mustProccessErrors("Failed to perform backup", err)
 	}
 
-	dateNow := time.Now().Format("2006-01-02-15-04-05")
 	backupFile, err := os.Open(BACKUP_PATH)
 	if err != nil {
 		mustProccessErrors("Failed to open backupFile: %+v", err)
 	}
 	defer backupFile.Close()
-	err = s3UploaderCleaner.CleanAndUpload(ctx, cfg.S3BucketName, cfg.DbName, cfg.MaxBackupCount, fmt.Sprintf("%s/%s-backup.sql", cfg.DbName, dateNow), backupFile)
+	backupKey := layout.Key(cfg.Prefix(), time.Now(), "sql")
+	err = s3Uploader.Upload(ctx, cfg.S3BucketName, backupKey, backupFile)
 	if err != nil {
 		mustProccessErrors("Failed to upload backup to S3: %+v", err)
 	}
+	// The backup is uploaded already, so failed retention is retried by the next run instead of failing this one.
+	deleted, err := retention.Apply(ctx, s3Client, cfg.S3BucketName, cfg.Prefix(), policy, time.Now())
+	if err != nil {
+		logger.Warnw("Failed to apply retention policy", "policy", policy.String(), "error", err)
+	} else {
+		logger.Infow("Retention policy is applied", "policy", policy.String(), "deleted", deleted)
+	}
+	writeRunReport(ctx, backuper, backupKey, deleted)
 
 	timeElapsed := time.Since(start)
 	err = metricsReporter.ReportStatus(ctx, backupName, true, int64(timeElapsed.Milliseconds()))
@@ -79,8 +144,116 @@ func main() {
 	logger.Infof("Backup successfully loaded to S3")
 }
 
+// purgeBackups deletes all backups of the BackupRequest from S3.
+// The core runs it when BackupRequest with deletionPolicy Delete is deleted.
+// Backups of the legacy layout are shared by BackupRequests, so they are never purged.
+func purgeBackups(cfg config.Config) {
+	if cfg.BackupPrefix == "" {
+		logger.Fatalf("Refusing to purge backups: BACKUP_PREFIX is not set")
+	}
+	s3Client, err := s3base.NewS3Client(ctx, cfg.S3Endpoint, cfg.S3AccessKey, cfg.S3SecretKey, S3REGION, cfg.Secure)
+	if err != nil {
+		logger.Fatalf("Failed to initialize s3 client: %+v", err)
+	}
+	deleted, err := retention.Purge(ctx, s3Client, cfg.S3BucketName, cfg.Prefix())
+	if err != nil {
+		logger.Fatalf("Failed to purge backups from S3: %+v", err)
+	}
+	logger.Infow("Backups are purged from S3", "prefix", cfg.Prefix(), "deleted", len(deleted))
+}
+
+// newHookRunner returns a runner of hooks and a function closing its session.
+// Queries of all hooks run in one session of the database.
+func newHookRunner(b backuper.Backuper, spec hooks.Hooks) (hooks.Runner, func()) {
+	if !spec.NeedsQuerier() {
+		return hooks.Runner{}, func() {}
+	}
+	db, err := b.Open()
+	if err != nil {
+		mustProccessErrors("Failed to connect to database for hooks", err)
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		mustProccessErrors("Failed to connect to database for hooks", err)
+	}
+	return hooks.Runner{Query: hooks.SessionQuerier(conn)}, func() {
+		conn.Close()
+		db.Close()
+	}
+}
+
+// writeRunReport tells the core which object was uploaded and which were deleted,
+// so it can record a Backup and delete Backups of deleted objects.
+func writeRunReport(ctx context.Context, b backuper.Backuper, key string, deleted []string) {
+	report := runReport{Key: key, Hooks: hookResults, Deleted: deleted}
+
+	size, checksum, err := fileChecksum(BACKUP_PATH)
+	if err != nil {
+		logger.Warnw("Failed to calculate checksum of backup", "error", err)
+	}
+	report.Size, report.Checksum = size, checksum
+	if report.DatabaseVersion, err = b.DatabaseVersion(ctx); err != nil {
+		logger.Warnw("Failed to get database version", "error", err)
+	}
+	if report.ToolVersion, err = b.ToolVersion(ctx); err != nil {
+		logger.Warnw("Failed to get tool version", "error", err)
+	}
+
+	writeReport(report)
+}
+
+// maxError is how many bytes of the error of a failed run are reported.
+const maxError = 1024
+
+// writeReport writes r to the termination message. If it does not fit, details
+// of hooks are dropped: outputs first, then messages, then deleted keys and at last
+// the hooks themselves.
+func writeReport(r runReport) {
+	r.Error = report.Truncate(r.Error, maxError)
+	r.Hooks = slices.Clone(r.Hooks)
+	data, err := report.Marshal(&r,
+		func() {
+			for i := range r.Hooks {
+				r.Hooks[i].Output = ""
+			}
+		},
+		func() {
+			for i := range r.Hooks {
+				r.Hooks[i].Message = ""
+			}
+		},
+		func() { r.Deleted, r.DeletedOmitted = nil, len(r.Deleted) },
+		func() { r.Hooks = nil },
+	)
+	if err == nil {
+		err = os.WriteFile(TERMINATION_LOG, data, 0o644)
+	}
+	if err != nil {
+		logger.Warnw("Failed to write run report", "error", err)
+	}
+}
+
+// fileChecksum returns size and SHA-256 checksum of file at path.
+func fileChecksum(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 func mustProccessErrors(msg string, err error, keysAndValues ...any) {
 	logger.Errorw(msg, "error", err, keysAndValues)
+	if len(hookResults) > 0 {
+		writeReport(runReport{Error: fmt.Sprintf("%s: %v", msg, err), Hooks: hookResults})
+	}
 	err = metricsReporter.ReportStatus(ctx, backupName, false, -1)
 	if err != nil {
 		logger.Fatalf("Failed to report metric %w\n", err)
@@ -3,6 +3,7 @@ module mysql_restorer
 go 1.24.2
 
 require (
+	github.com/oiler-backup/core/common v0.0.0
 	filippo.io/edwards25519 v1.1.0 // indirect
 	github.com/aws/aws-sdk-go-v2 v1.36.3 // indirect
 	github.com/aws/aws-sdk-go-v2/config v1.29.14 // indirect
@@ -11,7 +12,7 @@ require (
 	github.com/golang/protobuf v1.5.4 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/objx v0.5.2 // indirect
-	github.com/stretchr/testify v1.10.0 // indirect
+	github.com/stretchr/testify v1.10.0
 	go.uber.org/multierr v1.10.0 // indirect
 	golang.org/x/net v0.38.0 // indirect
 	golang.org/x/sys v0.31.0 // indirect
@@ -43,3 +44,5 @@ require (
 	github.com/oiler-backup/base v0.0.0-20250521192127-215469f19f06
 	go.uber.org/zap v1.27.0
 )
+
+replace github.com/oiler-backup/core/common => ../../common
@@ -0,0 +1,38 @@
+package checks
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Parse(t *testing.T) {
+	checks, err := Parse(`[{"name":"orders","query":"SELECT id FROM orders LIMIT 10","minRows":10}]`)
+	require.NoError(t, err)
+	assert.Equal(t, []Check{{Name: "orders", Query: "SELECT id FROM orders LIMIT 10", MinRows: 10}}, checks)
+
+	checks, err = Parse("")
+	require.NoError(t, err)
+	assert.Empty(t, checks)
+
+	_, err = Parse("SELECT 1")
+	require.ErrorContains(t, err, "failed to parse checks")
+}
+
+func Test_Evaluate(t *testing.T) {
+	check := Check{Name: "orders", MinRows: 2}
+
+	assert.Equal(t, Result{Name: "orders", Passed: true, Rows: 3, Duration: "15ms"},
+		evaluate(check, 3, 15*time.Millisecond, nil))
+	assert.Equal(t, Result{Name: "orders", Rows: 1, Duration: "0s", Message: "query returned 1 rows, expected at least 2"},
+		evaluate(check, 1, 0, nil))
+	assert.Equal(t, Result{Name: "orders", Duration: "0s", Message: "Table 'db.orders' doesn't exist"},
+		evaluate(check, 0, 0, errors.New("Table 'db.orders' doesn't exist")))
+
+	result := evaluate(check, 0, 0, errors.New(strings.Repeat("x", 1000)))
+	assert.Len(t, result.Message, maxMessage)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/caarlos0/env/v11"
+	"github.com/oiler-backup/core/common/layout"
 )
 
 type Config struct {
@@ -18,8 +19,17 @@ type Config struct {
 	S3SecretKey  string `env:"S3_SECRET_KEY,required,notEmpty,unset"`
 	S3BucketName string `env:"S3_BUCKET_NAME,required,notEmpty"`
 
+	// SourceDbName is a database backups of which are restored, DbName if empty.
+	SourceDbName   string `env:"SOURCE_DB_NAME"`
 	BackupRevision string `env:"BACKUP_REVISION"`
 	Secure         bool   `env:"SECURE" envDefault:"false"`
+
+	// BackupNamespace and BackupRequestName narrow backups to those made in the namespace
+	// and by the BackupRequest, see layout.Scope.
+	BackupNamespace   string `env:"BACKUP_NAMESPACE"`
+	BackupRequestName string `env:"BACKUP_REQUEST_NAME"`
+	// Checks is a JSON list of queries verifying the restored database.
+	Checks string `env:"CHECKS"`
 }
 
 func GetConfig() (Config, error) {
@@ -34,8 +44,21 @@ func GetConfig() (Config, error) {
 func (c Config) String() string {
 	return fmt.Sprintf("{DbHost: %s, DbPort: %s, DbUser: %s, DbPassword: <unset>, DbName: %s, "+
 		"CoreAddr: %s, S3Endpoint: %s, S3AccessKey: <unset>, S3SecretKey: <unset>, S3BucketName: %s, "+
-		"backupRevision: %s, Secure: %t}",
+		"SourceDbName: %s, BackupNamespace: %s, BackupRequestName: %s, backupRevision: %s, Secure: %t}",
 		c.DbHost, c.DbPort, c.DbUser, c.DbName,
 		c.CoreAddr, c.S3Endpoint, c.S3BucketName,
-		c.BackupRevision, c.Secure)
+		c.SourceDbName, c.BackupNamespace, c.BackupRequestName, c.BackupRevision, c.Secure)
+}
+
+// BackupDbName returns a database backups of which are restored.
+func (c Config) BackupDbName() string {
+	if c.SourceDbName != "" {
+		return c.SourceDbName
+	}
+	return c.DbName
+}
+
+// Scope returns backups a revision is selected among.
+func (c Config) Scope() layout.Scope {
+	return layout.Scope{Namespace: c.BackupNamespace, Name: c.BackupRequestName, Database: c.BackupDbName()}
 }
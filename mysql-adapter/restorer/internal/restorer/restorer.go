@@ -3,12 +3,18 @@ package restorer
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"mysql_restorer/internal/checks"
 	"os/exec"
+	"strings"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
+// errBadDb is returned by MySQL on connection to a missing database.
+const errBadDb = 1049
+
 type Restorer struct {
 	dbHost string
 	dbPort string
@@ -31,20 +37,23 @@ func NewRestorer(dbHost, dbPort, dbUser, dbPassword, dbName, backupPath string)
 }
 
 func (r Restorer) Restore(ctx context.Context) error {
-	connStr := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", r.dbUser, r.dbPass, r.dbHost, r.dbPort, r.dbName)
-
-	db, err := sql.Open("mysql", connStr)
+	db, err := sql.Open("mysql", r.connString(r.dbName))
 	if err != nil {
 		return fmt.Errorf("failed to open driver for database: %v", err)
 	}
 	defer db.Close()
 
 	err = db.PingContext(ctx)
-	if err != nil {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errBadDb {
+		if err := r.createDatabase(ctx); err != nil {
+			return err
+		}
+	} else if err != nil {
 		return fmt.Errorf("failed to connect to database: %v", err)
 	}
 
-	connStr = fmt.Sprintf("mysql -h %s -P %s -u %s -p%s %s < %s",
+	connStr := fmt.Sprintf("mysql -h %s -P %s -u %s -p%s %s < %s",
 		r.dbHost,
 		r.dbPort,
 		r.dbUser,
@@ -60,3 +69,40 @@ func (r Restorer) Restore(ctx context.Context) error {
 	}
 	return nil
 }
+
+// createDatabase creates the target database, so a backup can be cloned to a new database.
+func (r Restorer) createDatabase(ctx context.Context) error {
+	db, err := sql.Open("mysql", r.connString(""))
+	if err != nil {
+		return fmt.Errorf("failed to open driver for database: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, "CREATE DATABASE IF NOT EXISTS "+quoteIdentifier(r.dbName))
+	if err != nil {
+		return fmt.Errorf("failed to create database %s: %v", r.dbName, err)
+	}
+	return nil
+}
+
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// Check runs checks against the restored database.
+func (r Restorer) Check(ctx context.Context, list []checks.Check) ([]checks.Result, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	db, err := sql.Open("mysql", r.connString(r.dbName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open driver for database: %v", err)
+	}
+	defer db.Close()
+
+	return checks.Run(ctx, db, list), nil
+}
+
+func (r Restorer) connString(dbName string) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", r.dbUser, r.dbPass, r.dbHost, r.dbPort, dbName)
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/oiler-backup/base/proto"
+	serverscommon "github.com/oiler-backup/core/common/servers/backup"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func runOnceContext() context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(serverscommon.RunOnceMetadataKey, "true"))
+}
+
+func backuperCronJob() *batchv1.CronJob {
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-1a2b3c4d-example", Namespace: "team-a"},
+		Spec: batchv1.CronJobSpec{
+			Schedule: "0 0 * * *",
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "backup"}},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "backup-job", Image: "backuper"}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_Backup_RunOnce(t *testing.T) {
+	mockJobsStub := new(MockJobsStub)
+	mockJobsCreator := new(MockJobsCreator)
+	server := &BackupServer{
+		jobsStub:    mockJobsStub,
+		jobsCreator: mockJobsCreator,
+		namespace:   "default",
+	}
+	req := &pb.BackupRequest{Schedule: "0 0 * * *"}
+
+	mockJobsStub.On("BuildBackuperCj", req.Schedule, mock.AnythingOfType("envgetters.EnvGetterMerger")).Return(backuperCronJob())
+	mockJobsCreator.On("CreateJob", mock.Anything, mock.MatchedBy(func(job *batchv1.Job) bool {
+		return job.Name == "backup-1a2b3c4d-example" &&
+			job.Namespace == "team-a" &&
+			job.Labels["app"] == "backup" &&
+			job.Annotations["cronjob.kubernetes.io/instantiate"] == "manual" &&
+			job.Spec.Template.Spec.Containers[0].Image == "backuper"
+	})).Return("backup-1a2b3c4d-example", "team-a", nil)
+
+	resp, err := server.Backup(runOnceContext(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "Job created successfully", resp.Status)
+	assert.Equal(t, "backup-1a2b3c4d-example", resp.CronjobName)
+	assert.Equal(t, "team-a", resp.CronjobNamespace)
+
+	mockJobsStub.AssertExpectations(t)
+	mockJobsCreator.AssertExpectations(t)
+	mockJobsCreator.AssertNotCalled(t, "CreateCronJob", mock.Anything, mock.Anything)
+}
+
+func Test_Backup_RunOnce_CreationError(t *testing.T) {
+	mockJobsStub := new(MockJobsStub)
+	mockJobsCreator := new(MockJobsCreator)
+	server := &BackupServer{
+		jobsStub:    mockJobsStub,
+		jobsCreator: mockJobsCreator,
+		namespace:   "default",
+	}
+	req := &pb.BackupRequest{Schedule: "0 0 * * *"}
+
+	mockJobsStub.On("BuildBackuperCj", req.Schedule, mock.AnythingOfType("envgetters.EnvGetterMerger")).Return(backuperCronJob())
+	mockJobsCreator.On("CreateJob", mock.Anything, mock.Anything).Return("", "", errors.New("forbidden"))
+
+	resp, err := server.Backup(runOnceContext(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "Failed to create Job", resp.Status)
+	assert.Empty(t, resp.CronjobName)
+}
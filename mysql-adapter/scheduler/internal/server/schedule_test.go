@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/oiler-backup/base/proto"
+	"github.com/oiler-backup/core/common/schedule"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_Backup_HashedSchedule(t *testing.T) {
+	mockJobsStub := new(MockJobsStub)
+	mockJobsCreator := new(MockJobsCreator)
+	server := &BackupServer{
+		jobsCreator: mockJobsCreator,
+		jobsStub:    mockJobsStub,
+		namespace:   "default",
+	}
+
+	req := &pb.BackupRequest{Schedule: "H 3 * * *", DbUri: "db", DbPort: 5432, DbName: "orders"}
+	mockJobsStub.On("BuildBackuperCj", req.Schedule, mock.Anything).
+		Return(scheduledCronJob("backup-new", req.Schedule, "backuper:new"))
+	var created *batchv1.CronJob
+	mockJobsCreator.On("CreateCronJob", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { created = args.Get(1).(*batchv1.CronJob) }).
+		Return("backup-new", "default", nil)
+
+	_, err := server.Backup(context.Background(), req)
+	require.NoError(t, err)
+	expected, err := schedule.Expand(req.Schedule, "default/db:5432/orders")
+	require.NoError(t, err)
+	assert.Equal(t, expected, created.Spec.Schedule)
+}
+
+func Test_Update_InvalidHashedSchedule(t *testing.T) {
+	mockJobsStub := new(MockJobsStub)
+	kubeClient := fake.NewClientset(scheduledCronJob("old-cj", "0 0 * * *", "backuper:old"))
+	server := &BackupServer{
+		kubeClient: kubeClient,
+		jobsStub:   mockJobsStub,
+		namespace:  "default",
+	}
+
+	req := &pb.UpdateBackupRequest{
+		CronjobName:      "old-cj",
+		CronjobNamespace: "default",
+		Request:          &pb.BackupRequest{Schedule: "H(0-99) * * * *"},
+	}
+	mockJobsStub.On("BuildBackuperCj", req.Request.Schedule, mock.Anything).
+		Return(scheduledCronJob("backup-new", req.Request.Schedule, "backuper:old"))
+
+	resp, err := server.Update(context.Background(), req)
+	require.Error(t, err)
+	assert.Equal(t, "Failed to expand schedule", resp.Status)
+
+	cj, err := kubeClient.BatchV1().CronJobs("default").Get(context.Background(), "old-cj", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "0 0 * * *", cj.Spec.Schedule)
+}
@@ -11,8 +11,11 @@ import (
 
 	pb "github.com/oiler-backup/base/proto"
 	serversbase "github.com/oiler-backup/base/servers/backup"
+	"github.com/oiler-backup/base/servers/backup/envgetters"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func Test_Backup(t *testing.T) {
@@ -130,18 +133,43 @@ func Test_Backup_CJ_CreationError(t *testing.T) {
 	assert.Empty(t, resp.CronjobNamespace)
 }
 
+func scheduledCronJob(name, schedule, image string) *batchv1.CronJob {
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: batchv1.CronJobSpec{
+			Schedule: schedule,
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{Name: "backup-job", Image: image, Env: []corev1.EnvVar{{Name: "DB_PASSWORD"}}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func Test_Update(t *testing.T) {
-	mockJobsCreator := new(MockJobsCreator)
+	mockJobsStub := new(MockJobsStub)
+	existing := scheduledCronJob("old-cj", "0 0 * * *", "backuper:old")
+	existing.Labels = map[string]string{"app": "backup"}
+	kubeClient := fake.NewClientset(existing)
 
 	server := &BackupServer{
-		jobsCreator: mockJobsCreator,
-		namespace:   "default",
+		kubeClient: kubeClient,
+		jobsStub:   mockJobsStub,
+		namespace:  "default",
 	}
 
 	req := &pb.UpdateBackupRequest{
 		CronjobName:      "old-cj",
 		CronjobNamespace: "default",
 		Request: &pb.BackupRequest{
+			Schedule:       "0 3 * * *",
 			DbUri:          "localhost",
 			DbPort:         5432,
 			DbUser:         "user",
@@ -170,7 +198,10 @@ func Test_Update(t *testing.T) {
 		{Name: "MAX_BACKUP_COUNT", Value: fmt.Sprint(req.Request.MaxBackupCount)},
 	}
 
-	mockJobsCreator.On("UpdateCronJob", mock.Anything, req.CronjobName, req.CronjobNamespace, expectedEnvs).Return(nil)
+	desired := scheduledCronJob("backup-new", "0 3 * * *", "backuper:new")
+	mockJobsStub.On("BuildBackuperCj", req.Request.Schedule, mock.MatchedBy(func(getter envgetters.EnvGetter) bool {
+		return assert.ObjectsAreEqual(expectedEnvs, getter.GetEnvs())
+	})).Return(desired)
 
 	resp, err := server.Update(context.Background(), req)
 	require.NoError(t, err)
@@ -178,50 +209,31 @@ func Test_Update(t *testing.T) {
 	assert.Equal(t, "old-cj", resp.CronjobName)
 	assert.Equal(t, "default", resp.CronjobNamespace)
 
-	mockJobsCreator.AssertExpectations(t)
+	cj, err := kubeClient.BatchV1().CronJobs("default").Get(context.Background(), "old-cj", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, desired.Spec, cj.Spec)
+	assert.Equal(t, existing.Labels, cj.Labels)
+
+	mockJobsStub.AssertExpectations(t)
 }
 
 func Test_Update_Error(t *testing.T) {
-	mockJobsCreator := new(MockJobsCreator)
+	mockJobsStub := new(MockJobsStub)
 
 	server := &BackupServer{
-		jobsCreator: mockJobsCreator,
-		namespace:   "default",
+		kubeClient: fake.NewClientset(),
+		jobsStub:   mockJobsStub,
+		namespace:  "default",
 	}
 
 	req := &pb.UpdateBackupRequest{
-		CronjobName:      "old-cj",
+		CronjobName:      "missing-cj",
 		CronjobNamespace: "default",
-		Request: &pb.BackupRequest{
-			DbUri:          "localhost",
-			DbPort:         5432,
-			DbUser:         "user",
-			DbPass:         "pass",
-			DbName:         "mydb",
-			S3Endpoint:     "s3.example.com",
-			S3AccessKey:    "key",
-			S3SecretKey:    "secret",
-			S3BucketName:   "bucket",
-			CoreAddr:       "http://core:8080",
-			MaxBackupCount: 5,
-		},
+		Request:          &pb.BackupRequest{Schedule: "0 0 * * *"},
 	}
 
-	expectedEnvs := []corev1.EnvVar{
-		{Name: "DB_HOST", Value: req.Request.DbUri},
-		{Name: "DB_PORT", Value: fmt.Sprint(req.Request.DbPort)},
-		{Name: "DB_USER", Value: req.Request.DbUser},
-		{Name: "DB_PASSWORD", Value: req.Request.DbPass},
-		{Name: "DB_NAME", Value: req.Request.DbName},
-		{Name: "S3_ENDPOINT", Value: req.Request.S3Endpoint},
-		{Name: "S3_ACCESS_KEY", Value: req.Request.S3AccessKey},
-		{Name: "S3_SECRET_KEY", Value: req.Request.S3SecretKey},
-		{Name: "S3_BUCKET_NAME", Value: req.Request.S3BucketName},
-		{Name: "CORE_ADDR", Value: req.Request.CoreAddr},
-		{Name: "MAX_BACKUP_COUNT", Value: fmt.Sprint(req.Request.MaxBackupCount)},
-	}
-
-	mockJobsCreator.On("UpdateCronJob", mock.Anything, req.CronjobName, req.CronjobNamespace, expectedEnvs).Return(fmt.Errorf("some error"))
+	mockJobsStub.On("BuildBackuperCj", req.Request.Schedule, mock.Anything).
+		Return(scheduledCronJob("backup-new", "0 0 * * *", "backuper:new"))
 
 	resp, err := server.Update(context.Background(), req)
 	require.Error(t, err)
@@ -229,7 +241,7 @@ func Test_Update_Error(t *testing.T) {
 	assert.Empty(t, resp.CronjobName)
 	assert.Empty(t, resp.CronjobNamespace)
 
-	mockJobsCreator.AssertExpectations(t)
+	mockJobsStub.AssertExpectations(t)
 }
 
 func Test_Restore(t *testing.T) {
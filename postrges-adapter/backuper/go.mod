@@ -3,6 +3,7 @@ module backuper
 go 1.24.2
 
 require (
+	github.com/oiler-backup/core/common v0.0.0
 	github.com/caarlos0/env/v11 v11.3.1
 	github.com/lib/pq v1.10.9
 	github.com/oiler-backup/base v0.0.0-20250523073134-cc72e34a783e
@@ -92,3 +93,5 @@ require (
 	golang.org/x/text v0.24.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250428153025-10db94c68c34 // indirect
 )
+
+replace github.com/oiler-backup/core/common => ../../common
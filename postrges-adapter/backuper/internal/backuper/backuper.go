@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 
 	_ "github.com/lib/pq"
 )
@@ -44,13 +45,15 @@ func NewBackuper(dbHost, dbPort, dbUser, dbPassword, dbName, backupPath string)
 	}
 }
 
-// Backup performs backup of PostgreSQL Database by using pg_dump CLI.
-func (b Backuper) Backup(ctx context.Context, secure bool) error {
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+func (b Backuper) connString() string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		b.dbHost, b.dbPort, b.dbUser, b.dbPass, b.dbName,
 	)
+}
 
-	db, err := sql.Open("postgres", connStr)
+// Backup performs backup of PostgreSQL Database by using pg_dump CLI.
+func (b Backuper) Backup(ctx context.Context, secure bool) error {
+	db, err := sql.Open("postgres", b.connString())
 	if err != nil { // coverage-ignore
 		return buildBackupError("Failed to open driver for database: %+v", err)
 	}
@@ -86,3 +89,36 @@ func (b Backuper) Backup(ctx context.Context, secure bool) error {
 	}
 	return nil
 }
+
+// Open opens a pool of connections to the database, e.g. to run hooks around Backup.
+func (b Backuper) Open() (*sql.DB, error) {
+	db, err := sql.Open("postgres", b.connString())
+	if err != nil { // coverage-ignore
+		return nil, buildBackupError("Failed to open driver for database: %+v", err)
+	}
+	return db, nil
+}
+
+// DatabaseVersion returns version of PostgreSQL server.
+func (b Backuper) DatabaseVersion(ctx context.Context) (string, error) {
+	db, err := sql.Open("postgres", b.connString())
+	if err != nil { // coverage-ignore
+		return "", buildBackupError("Failed to open driver for database: %+v", err)
+	}
+	defer db.Close()
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SHOW server_version").Scan(&version); err != nil {
+		return "", buildBackupError("Failed to query server version: %+v", err)
+	}
+	return version, nil
+}
+
+// ToolVersion returns version of pg_dump CLI.
+func (b Backuper) ToolVersion(ctx context.Context) (string, error) {
+	output, err := exec.CommandContext(ctx, "pg_dump", "--version").Output()
+	if err != nil { // coverage-ignore
+		return "", buildBackupError("Failed executing pg_dump: %+v", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
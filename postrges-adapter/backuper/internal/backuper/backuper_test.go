@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -59,6 +60,10 @@ func Test_Backup_CreatesValidDump(t *testing.T) {
 	fileInfo, err := os.Stat(backupFile)
 	require.NoError(t, err)
 	assert.Greater(t, fileInfo.Size(), int64(0))
+
+	version, err := b.DatabaseVersion(ctx)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(version, "14."), version)
 }
 
 func Test_BuildBackup(t *testing.T) {
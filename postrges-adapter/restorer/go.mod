@@ -5,6 +5,7 @@ go 1.24.2
 require go.uber.org/zap v1.27.0
 
 require (
+	github.com/oiler-backup/core/common v0.0.0
 	github.com/aws/aws-sdk-go-v2 v1.36.3 // indirect
 	github.com/aws/aws-sdk-go-v2/aws/protocol/eventstream v1.6.10 // indirect
 	github.com/aws/aws-sdk-go-v2/config v1.29.14 // indirect
@@ -27,7 +28,7 @@ require (
 	github.com/golang/protobuf v1.5.4 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/objx v0.5.2 // indirect
-	github.com/stretchr/testify v1.10.0 // indirect
+	github.com/stretchr/testify v1.10.0
 	go.uber.org/multierr v1.10.0 // indirect
 	golang.org/x/net v0.38.0 // indirect
 	golang.org/x/sys v0.31.0 // indirect
@@ -43,3 +44,5 @@ require (
 	github.com/lib/pq v1.10.9
 	github.com/oiler-backup/base v0.0.0-20250518161511-755ace4b7df7
 )
+
+replace github.com/oiler-backup/core/common => ../../common
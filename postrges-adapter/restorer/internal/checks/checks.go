@@ -0,0 +1,92 @@
+// Package checks runs queries verifying a restored database.
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/oiler-backup/core/common/report"
+)
+
+// A Check is a query which must return at least MinRows rows.
+type Check struct {
+	Name    string `json:"name"`
+	Query   string `json:"query"`
+	MinRows int64  `json:"minRows"`
+}
+
+// maxMessage is how many bytes of an error a Result keeps,
+// so results of all checks fit the termination message.
+const maxMessage = 160
+
+// A Result is an outcome of Check.
+type Result struct {
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Rows     int64  `json:"rows"`
+	Duration string `json:"duration"`
+	Message  string `json:"message,omitempty"`
+}
+
+// A Querier runs queries against the restored database.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Parse parses checks from JSON list. Empty string means no checks.
+func Parse(s string) ([]Check, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var checks []Check
+	if err := json.Unmarshal([]byte(s), &checks); err != nil {
+		return nil, fmt.Errorf("failed to parse checks: %w", err)
+	}
+	return checks, nil
+}
+
+// Run runs checks one by one. Failed check does not stop the others.
+func Run(ctx context.Context, db Querier, checks []Check) []Result {
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		start := time.Now()
+		rows, err := countRows(ctx, db, check.Query)
+		results = append(results, evaluate(check, rows, time.Since(start), err))
+	}
+	return results
+}
+
+func countRows(ctx context.Context, db Querier, query string) (int64, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var count int64
+	for rows.Next() {
+		count++
+	}
+	return count, rows.Err()
+}
+
+// evaluate tells whether check passed given rows returned by its query.
+func evaluate(check Check, rows int64, duration time.Duration, err error) Result {
+	result := Result{
+		Name:     check.Name,
+		Rows:     rows,
+		Duration: duration.Round(time.Millisecond).String(),
+	}
+	switch {
+	case err != nil:
+		result.Message = report.Truncate(err.Error(), maxMessage)
+	case rows < check.MinRows:
+		result.Message = fmt.Sprintf("query returned %d rows, expected at least %d", rows, check.MinRows)
+	default:
+		result.Passed = true
+	}
+	return result
+}
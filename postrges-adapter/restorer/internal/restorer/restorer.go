@@ -3,13 +3,18 @@ package restorer
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"restorer/internal/checks"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
+// invalidCatalogName is returned by PostgreSQL on connection to a missing database.
+const invalidCatalogName = "3D000"
+
 type Restorer struct {
 	dbHost string
 	dbPort string
@@ -32,16 +37,19 @@ func NewRestorer(dbHost, dbPort, dbUser, dbPassword, dbName, backupPath string)
 }
 
 func (r Restorer) Restore(ctx context.Context) error {
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		r.dbHost, r.dbPort, r.dbUser, r.dbPass, r.dbName)
-	db, err := sql.Open("postgres", connStr)
+	db, err := sql.Open("postgres", r.connString(r.dbName))
 	if err != nil {
 		return fmt.Errorf("failed to open driver for database: %v", err)
 	}
 	defer db.Close()
 
 	err = db.PingContext(ctx)
-	if err != nil {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == invalidCatalogName {
+		if err := r.createDatabase(ctx); err != nil {
+			return err
+		}
+	} else if err != nil {
 		return fmt.Errorf("failed to connect to database: %v", err)
 	}
 
@@ -52,6 +60,7 @@ func (r Restorer) Restore(ctx context.Context) error {
 		"-d", r.dbName,
 		"--no-owner",
 		"--clean",
+		"--if-exists",
 		r.backupPath,
 	)
 	cmd.Env = append(os.Environ(), fmt.Sprintf("PGPASSWORD=%s", r.dbPass))
@@ -62,3 +71,37 @@ func (r Restorer) Restore(ctx context.Context) error {
 	}
 	return nil
 }
+
+func (r Restorer) connString(dbName string) string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		r.dbHost, r.dbPort, r.dbUser, r.dbPass, dbName)
+}
+
+// createDatabase creates the target database, so a backup can be cloned to a new database.
+func (r Restorer) createDatabase(ctx context.Context) error {
+	db, err := sql.Open("postgres", r.connString("postgres"))
+	if err != nil {
+		return fmt.Errorf("failed to open driver for database: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, "CREATE DATABASE "+pq.QuoteIdentifier(r.dbName))
+	if err != nil {
+		return fmt.Errorf("failed to create database %s: %v", r.dbName, err)
+	}
+	return nil
+}
+
+// Check runs checks against the restored database.
+func (r Restorer) Check(ctx context.Context, list []checks.Check) ([]checks.Result, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	db, err := sql.Open("postgres", r.connString(r.dbName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open driver for database: %v", err)
+	}
+	defer db.Close()
+
+	return checks.Run(ctx, db, list), nil
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"restorer/internal/checks"
 	"restorer/internal/config"
 	"restorer/internal/restorer"
 	"time"
@@ -11,12 +13,14 @@ import (
 	loggerbase "github.com/oiler-backup/base/logger"
 	metricsbase "github.com/oiler-backup/base/metrics"
 	s3base "github.com/oiler-backup/base/s3"
+	"github.com/oiler-backup/core/common/revision"
 	"go.uber.org/zap"
 )
 
 const (
-	S3REGION    = "us-east-1" // Fictious
-	BACKUP_PATH = "/tmp/backup.sql"
+	S3REGION        = "us-east-1" // Fictious
+	BACKUP_PATH     = "/tmp/backup.sql"
+	TERMINATION_LOG = "/dev/termination-log"
 )
 
 var (
@@ -43,13 +47,27 @@ func main() {
 	}
 
 	metricsReporter = metricsbase.NewMetricsReporter(cfg.CoreAddr, false)
+	checkList, err := checks.Parse(cfg.Checks)
+	if err != nil {
+		mustProccessErrors("Failed to parse checks", err)
+	}
 	restorer := restorer.NewRestorer(cfg.DbHost, cfg.DbPort, cfg.DbUser, cfg.DbPassword, cfg.DbName, BACKUP_PATH)
 	downloader, err := s3base.NewS3Downloader(ctx, cfg.S3Endpoint, cfg.S3AccessKey, cfg.S3SecretKey, S3REGION, cfg.Secure)
 	if err != nil {
 		mustProccessErrors("Failed to create downloader", err)
 	}
 
-	err = downloader.Download(ctx, cfg.S3BucketName, cfg.BackupRevision, BACKUP_PATH)
+	s3Client, err := s3base.NewS3Client(ctx, cfg.S3Endpoint, cfg.S3AccessKey, cfg.S3SecretKey, S3REGION, cfg.Secure)
+	if err != nil {
+		mustProccessErrors("Failed to create S3 client", err)
+	}
+	backupRevision, err := revision.Resolve(ctx, s3Client, cfg.S3BucketName, cfg.Scope(), cfg.BackupRevision)
+	if err != nil {
+		mustProccessErrors("Failed to resolve backup revision", err, "revision", cfg.BackupRevision)
+	}
+	logger.Infow("Restoring backup", "revision", cfg.BackupRevision, "key", backupRevision)
+
+	err = downloader.Download(ctx, cfg.S3BucketName, backupRevision, BACKUP_PATH)
 	if err != nil {
 		mustProccessErrors("Failed to perform download", err)
 	}
@@ -59,6 +77,12 @@ func main() {
 		mustProccessErrors("Faild to restore backup", err)
 	}
 
+	results, err := restorer.Check(ctx, checkList)
+	if err != nil {
+		mustProccessErrors("Failed to run checks", err)
+	}
+	writeRestoreReport(backupRevision, results)
+
 	err = metricsReporter.ReportStatus(ctx, backupName, true, time.Now().Unix())
 	if err != nil {
 		mustProccessErrors("Failed to report successful status", err)
@@ -66,6 +90,22 @@ func main() {
 	logger.Infof("Backup was applied successfully")
 }
 
+// writeRestoreReport tells the core which backup was restored and how checks of it went.
+func writeRestoreReport(key string, results []checks.Result) {
+	report := struct {
+		Key    string          `json:"key"`
+		Checks []checks.Result `json:"checks,omitempty"`
+	}{Key: key, Checks: results}
+
+	data, err := json.Marshal(report)
+	if err == nil {
+		err = os.WriteFile(TERMINATION_LOG, data, 0o644)
+	}
+	if err != nil {
+		logger.Warnw("Failed to write restore report", "error", err)
+	}
+}
+
 func mustProccessErrors(msg string, err error, keysAndValues ...any) {
 	logger.Errorw(msg, "error", err, keysAndValues)
 	err = metricsReporter.ReportStatus(ctx, backupName, false, -1)
@@ -9,6 +9,9 @@ type Config struct {
 	BackuperVersion string `env:"BACKUPER_VERSION" envDefault:"ashadrinnn/pgbackuper:0.0.1-0"`
 	RestorerVersion string `env:"RESTORER_VERSION" envDefault:"sveb00/pgrestorer:0.0.1-1"`
 	Port            int64  `env:"PORT" envDefault:"50051"` // gRPC port
+	TLSCertFile     string `env:"TLS_CERT_FILE"`           // PEM certificate of gRPC server, plaintext if empty
+	TLSKeyFile      string `env:"TLS_KEY_FILE"`            // PEM key of TLSCertFile
+	TLSClientCAFile string `env:"TLS_CLIENT_CA_FILE"`      // PEM CAs of client certificates, not required if empty
 }
 
 // GetConfig reads environment variables, validates them and return Config object or
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pb "github.com/oiler-backup/base/proto"
+	serverscommon "github.com/oiler-backup/core/common/servers/backup"
+)
+
+// Delete starts a Job purging backups of req and deletes its backuper CronJob.
+// It is idempotent: the Job started by a previous call is reused.
+// The Job is built like Update builds the CronJob, with the overlay passed by Core,
+// so backups are purged even if the CronJob is gone already.
+func (s *BackupServer) Delete(ctx context.Context, req *serverscommon.DeleteRequest) (*serverscommon.DeleteResponse, error) {
+	var backupRequest pb.BackupRequest
+	if err := protojson.Unmarshal(req.Request, &backupRequest); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	desired := s.jobsStub.BuildBackuperCj(backupRequest.Schedule, backuperEnvGetter(&backupRequest))
+	if err := serverscommon.ApplyCronJobOverlay(ctx, desired); err != nil {
+		return nil, err
+	}
+
+	if req.CronjobName != "" {
+		// The purge Job is named after the CronJob rather than the random name of desired,
+		// so a retried Delete finds the Job it started before instead of starting another one.
+		desired.Name = req.CronjobName
+	}
+	name, namespace, err := s.startPurgeJob(ctx, serverscommon.PurgeJobFromCronJob(desired))
+	if err != nil {
+		return nil, err
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	err = s.kubeClient.BatchV1().CronJobs(req.CronjobNamespace).Delete(
+		ctx,
+		req.CronjobName,
+		metav1.DeleteOptions{PropagationPolicy: &propagation},
+	)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to delete CronJob %s: %w", req.CronjobName, err)
+	}
+
+	return &serverscommon.DeleteResponse{JobName: name, JobNamespace: namespace}, nil
+}
+
+// startPurgeJob creates job unless a Job of the same name exists already,
+// and returns name and namespace of the Job purging backups.
+func (s *BackupServer) startPurgeJob(ctx context.Context, job *batchv1.Job) (string, string, error) {
+	existing, err := s.kubeClient.BatchV1().Jobs(job.Namespace).Get(ctx, job.Name, metav1.GetOptions{})
+	if err == nil {
+		return existing.Name, existing.Namespace, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return "", "", fmt.Errorf("failed to get purge Job %s: %w", job.Name, err)
+	}
+
+	name, namespace, err := s.jobsCreator.CreateJob(ctx, job)
+	if apierrors.IsAlreadyExists(err) {
+		return job.Name, job.Namespace, nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create purge Job: %w", err)
+	}
+	return name, namespace, nil
+}
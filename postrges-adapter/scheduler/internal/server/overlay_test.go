@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/oiler-backup/base/proto"
+	serverscommon "github.com/oiler-backup/core/common/servers/backup"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const secretEnvOverlay = `{"spec":{"template":{"spec":{"containers":[{"name":"backup-restore-job",` +
+	`"env":[{"name":"DB_PASSWORD","valueFrom":{"secretKeyRef":{"name":"creds","key":"DB_PASSWORD"}}}]}]}}}}`
+
+func overlayContext(patch string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(serverscommon.OverlayMetadataKey, patch))
+}
+
+func restorerJob() *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: "default"},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "backup-restore-job",
+							Env: []corev1.EnvVar{
+								{Name: "DB_HOST", Value: "localhost"},
+								{Name: "DB_PASSWORD", Value: ""},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_Restore_WithOverlay(t *testing.T) {
+	mockJobsStub := new(MockJobsStub)
+	mockJobsCreator := new(MockJobsCreator)
+
+	server := &BackupServer{
+		jobsStub:    mockJobsStub,
+		jobsCreator: mockJobsCreator,
+		namespace:   "default",
+	}
+
+	job := restorerJob()
+	mockJobsStub.On("BuildRestorerJob", mock.AnythingOfType("envgetters.EnvGetterMerger")).Return(job)
+	mockJobsCreator.On("CreateJob", mock.Anything, mock.MatchedBy(func(j *batchv1.Job) bool {
+		env := j.Spec.Template.Spec.Containers[0].Env[1]
+		return env.ValueFrom != nil && env.ValueFrom.SecretKeyRef.Name == "creds"
+	})).Return("job-name", "default", nil)
+
+	resp, err := server.Restore(overlayContext(secretEnvOverlay), &pb.BackupRestore{})
+	require.NoError(t, err)
+	assert.Equal(t, "Job created successfully", resp.Status)
+
+	mockJobsCreator.AssertExpectations(t)
+}
+
+func Test_Update_WithOverlay(t *testing.T) {
+	mockJobsStub := new(MockJobsStub)
+	kubeClient := fake.NewClientset(scheduledCronJob("old-cj", "0 0 * * *", "backuper:old"))
+
+	server := &BackupServer{
+		kubeClient: kubeClient,
+		jobsStub:   mockJobsStub,
+		namespace:  "default",
+	}
+
+	req := &pb.UpdateBackupRequest{
+		CronjobName:      "old-cj",
+		CronjobNamespace: "default",
+		Request:          &pb.BackupRequest{Schedule: "0 0 * * *"},
+	}
+	mockJobsStub.On("BuildBackuperCj", req.Request.Schedule, mock.Anything).
+		Return(scheduledCronJob("backup-new", "0 0 * * *", "backuper:old"))
+
+	patch := `{"spec":{"suspend":true,"jobTemplate":{"spec":{"template":{"spec":{"containers":[{"name":"backup-job",` +
+		`"env":[{"name":"DB_PASSWORD","valueFrom":{"secretKeyRef":{"name":"creds","key":"DB_PASSWORD"}}}]}]}}}}}}`
+	resp, err := server.Update(overlayContext(patch), req)
+	require.NoError(t, err)
+	assert.Equal(t, "CronJob updated successfully", resp.Status)
+
+	cj, err := kubeClient.BatchV1().CronJobs("default").Get(context.Background(), "old-cj", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, cj.Spec.Suspend)
+	assert.True(t, *cj.Spec.Suspend)
+	env := cj.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env[0]
+	require.NotNil(t, env.ValueFrom)
+	assert.Equal(t, "creds", env.ValueFrom.SecretKeyRef.Name)
+}
+
+func Test_Update_WithOverlay_Invalid(t *testing.T) {
+	mockJobsStub := new(MockJobsStub)
+
+	server := &BackupServer{
+		kubeClient: fake.NewClientset(scheduledCronJob("old-cj", "0 0 * * *", "backuper:old")),
+		jobsStub:   mockJobsStub,
+		namespace:  "default",
+	}
+
+	req := &pb.UpdateBackupRequest{
+		CronjobName:      "old-cj",
+		CronjobNamespace: "default",
+		Request:          &pb.BackupRequest{},
+	}
+	mockJobsStub.On("BuildBackuperCj", req.Request.Schedule, mock.Anything).
+		Return(scheduledCronJob("backup-new", "", "backuper:old"))
+
+	resp, err := server.Update(overlayContext("not a json"), req)
+	require.Error(t, err)
+	assert.Equal(t, "Failed to apply overlay", resp.Status)
+}
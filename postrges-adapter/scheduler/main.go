@@ -7,11 +7,14 @@ import (
 	"net"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"postgres_adapter/internal/config"
 	"postgres_adapter/internal/server"
 
 	loggerbase "github.com/oiler-backup/base/logger"
+	serverscommon "github.com/oiler-backup/core/common/servers/backup"
 )
 
 func main() {
@@ -30,7 +33,13 @@ func main() {
 		logger.Panicw("Failed to listen port", "error", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	creds, err := serverscommon.TransportCredentials(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile)
+	if err != nil {
+		logger.Panicw("Failed to load TLS credentials", "error", err)
+	}
+	grpcServer := grpc.NewServer(grpc.Creds(creds))
+	// Kubernetes Operator Core probes DatabaseAdapter with the health service.
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
 
 	err = server.RegisterBackupServer(grpcServer, cfg.SystemNamespace, cfg.BackuperVersion, cfg.RestorerVersion)
 	if err != nil {